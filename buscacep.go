@@ -0,0 +1,205 @@
+package soawebservices
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"github.com/diegohordi/soawebservices/internal/texto"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+const (
+	ErrUFInvalida            = Error("a uf informada é inválida")
+	ErrCidadeObrigatoria     = Error("a cidade é obrigatória")
+	ErrLogradouroObrigatorio = Error("o logradouro é obrigatório")
+)
+
+var ufs = map[string]bool{
+	"AC": true, "AL": true, "AP": true, "AM": true, "BA": true, "CE": true, "DF": true,
+	"ES": true, "GO": true, "MA": true, "MT": true, "MS": true, "MG": true, "PA": true,
+	"PB": true, "PR": true, "PE": true, "PI": true, "RJ": true, "RN": true, "RS": true,
+	"RO": true, "RR": true, "SC": true, "SP": true, "SE": true, "TO": true,
+}
+
+// UFValida reports whether uf, in any case, is one of the 27 Brazilian UFs.
+func UFValida(uf string) bool {
+	return ufs[strings.ToUpper(strings.TrimSpace(uf))]
+}
+
+// BuscaCEP holds the CEPs found by BuscarCEPPorLogradouro, from the most to
+// the least relevant.
+type BuscaCEP struct {
+	Resultados []CEPEncontrado
+}
+
+// Total returns the number of CEPs found.
+func (b BuscaCEP) Total() int {
+	return len(b.Resultados)
+}
+
+// Pagina returns the CEPs of the given page, numbered from 1, with up to
+// tamanho CEPs each. Pages beyond the last one are empty.
+func (b BuscaCEP) Pagina(numero, tamanho int) []CEPEncontrado {
+	if numero < 1 || tamanho < 1 {
+		return nil
+	}
+	inicio := (numero - 1) * tamanho
+	if inicio >= len(b.Resultados) {
+		return nil
+	}
+	fim := inicio + tamanho
+	if fim > len(b.Resultados) {
+		fim = len(b.Resultados)
+	}
+	return b.Resultados[inicio:fim]
+}
+
+// relevancia returns how closely the words of the logradouro of a result
+// match the searched ones, as their Dice coefficient. A searched word matches
+// a word of the result it is a prefix of, so abbreviations such as AV match.
+func relevancia(busca []string, logradouro string) float64 {
+	tokens := texto.Tokens(logradouro)
+	if len(busca) == 0 || len(tokens) == 0 {
+		return 0
+	}
+	usados := make([]bool, len(tokens))
+	iguais := 0
+	for _, b := range busca {
+		for i, t := range tokens {
+			if !usados[i] && strings.HasPrefix(t, b) {
+				usados[i] = true
+				iguais++
+				break
+			}
+		}
+	}
+	return 2 * float64(iguais) / float64(len(busca)+len(tokens))
+}
+
+// ranquear returns the given addresses as CEPs ranked by the relevance of
+// their logradouro, with or without its type, to the searched one.
+func ranquear(logradouro string, enderecos []enderecoResult) []CEPEncontrado {
+	busca := texto.Tokens(logradouro)
+	encontrados := make([]CEPEncontrado, 0, len(enderecos))
+	for _, e := range enderecos {
+		r := relevancia(busca, e.LogradouroCompleto)
+		if semTipo := relevancia(busca, e.Logradouro); semTipo > r {
+			r = semTipo
+		}
+		encontrados = append(encontrados, CEPEncontrado{
+			CEP: CEP{
+				CEP:                   e.Cep,
+				UF:                    e.UF,
+				TipoLogradouro:        e.TipoLogradouro,
+				LogradouroCompleto:    e.LogradouroCompleto,
+				LogradouroComplemento: e.LogradouroComplemento,
+				Bairro:                e.Bairro,
+				Cidade:                e.Cidade,
+				CodigoIBGE:            e.CodigoIBGE,
+			},
+			Relevancia: r,
+		})
+	}
+	sort.SliceStable(encontrados, func(i, j int) bool {
+		if encontrados[i].Relevancia != encontrados[j].Relevancia {
+			return encontrados[i].Relevancia > encontrados[j].Relevancia
+		}
+		return encontrados[i].CEP.CEP < encontrados[j].CEP.CEP
+	})
+	return encontrados
+}
+
+func (d *defaultClient) buildBuscaCEPRequestBody(credenciais Credenciais, uf, cidade, logradouro string) (io.Reader, error) {
+	consulta := newConsultaLogradouro(credenciais, uf, cidade, logradouro)
+	buf, err := xml.Marshal(newRequestEnvelope(consulta))
+	if err != nil {
+		return nil, fmt.Errorf("an error occurred while build the request body: %w", err)
+	}
+	return bytes.NewBuffer(buf), err
+}
+
+func (d *defaultClient) parseBuscaCEPResponseBody(resp *http.Response, logradouro string) (BuscaCEP, error) {
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+	var respBody struct {
+		XMLName xml.Name
+		Body    struct {
+			XMLName  xml.Name
+			Response struct {
+				XMLName xml.Name `xml:"ConsultaLogradouroResponse"`
+				Result  consultaLogradouroResult
+			}
+		}
+	}
+	if err := xml.NewDecoder(resp.Body).Decode(&respBody); err != nil {
+		return BuscaCEP{}, err
+	}
+	result := respBody.Body.Response.Result
+	encontrado, err := parseCEPTransacao(result.Status, result.Transacao)
+	if err != nil || !encontrado {
+		return BuscaCEP{}, err
+	}
+	return BuscaCEP{Resultados: ranquear(logradouro, result.Enderecos)}, nil
+}
+
+// BuscarCEPPorLogradouro searches the CEPs of the given logradouro in the
+// given city and UF. Logradouros not found are not an error, but an empty
+// BuscaCEP.
+func (d *defaultClient) BuscarCEPPorLogradouro(ctx context.Context, uf, cidade, logradouro string) (BuscaCEP, error) {
+	uf = strings.ToUpper(strings.TrimSpace(uf))
+	switch {
+	case !UFValida(uf):
+		return BuscaCEP{}, ErrUFInvalida
+	case strings.TrimSpace(cidade) == "":
+		return BuscaCEP{}, ErrCidadeObrigatoria
+	case len(texto.Tokens(logradouro)) == 0:
+		return BuscaCEP{}, ErrLogradouroObrigatorio
+	}
+	credenciais, err := d.credenciais(ctx)
+	if err != nil {
+		return BuscaCEP{}, err
+	}
+	custo, err := d.usage.reservar(ServicoBuscaCEP)
+	if err != nil {
+		return BuscaCEP{}, err
+	}
+	result, err := d.buscarCEPPorLogradouro(ctx, credenciais, uf, strings.TrimSpace(cidade), strings.TrimSpace(logradouro))
+	d.usage.registrar(ctx, ServicoBuscaCEP, custo, err)
+	return result, err
+}
+
+func (d *defaultClient) buscarCEPPorLogradouro(ctx context.Context, credenciais Credenciais, uf, cidade, logradouro string) (BuscaCEP, error) {
+	errChan := make(chan error, 1)
+	resultChan := make(chan BuscaCEP, 1)
+	requestBody, err := d.buildBuscaCEPRequestBody(credenciais, uf, cidade, logradouro)
+	if err != nil {
+		return BuscaCEP{}, err
+	}
+	go func() {
+		serviceURL := fmt.Sprintf("%s/webservices/%s/%s", d.baseURL, d.ambiente, urlCEP)
+		resp, err := d.httpClient.Post(serviceURL, "text/xml", requestBody)
+		if err != nil {
+			errChan <- err
+			return
+		}
+		result, err := d.parseBuscaCEPResponseBody(resp, logradouro)
+		if err != nil {
+			errChan <- err
+			return
+		}
+		resultChan <- result
+	}()
+	select {
+	case err = <-errChan:
+		return BuscaCEP{}, err
+	case <-ctx.Done():
+		return BuscaCEP{}, ctx.Err()
+	case result := <-resultChan:
+		return result, nil
+	}
+}
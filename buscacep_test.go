@@ -0,0 +1,98 @@
+package soawebservices_test
+
+import (
+	"context"
+	"errors"
+	"github.com/diegohordi/soawebservices"
+	"testing"
+)
+
+func Test_defaultClient_BuscarCEPPorLogradouro(t *testing.T) {
+	tests := []struct {
+		name       string
+		file       string
+		uf         string
+		cidade     string
+		logradouro string
+		wantCEPs   []string
+		wantErr    error
+	}{
+		{
+			name:       "should return the CEPs ranked by relevance",
+			file:       "buscacep_success.xml",
+			uf:         "sp",
+			cidade:     "São Paulo",
+			logradouro: "Av. Paulista",
+			wantCEPs:   []string{"01310100", "01310200", "01310300"},
+		},
+		{
+			name:       "should return no CEP",
+			file:       "buscacep_not_found.xml",
+			uf:         "SP",
+			cidade:     "São Paulo",
+			logradouro: "Rua Inexistente",
+		},
+		{
+			name:       "should fail due to the given invalid UF",
+			file:       "buscacep_success.xml",
+			uf:         "XX",
+			cidade:     "São Paulo",
+			logradouro: "Paulista",
+			wantErr:    soawebservices.ErrUFInvalida,
+		},
+		{
+			name:       "should fail due to the missing logradouro",
+			file:       "buscacep_success.xml",
+			uf:         "SP",
+			cidade:     "São Paulo",
+			logradouro: " - ",
+			wantErr:    soawebservices.ErrLogradouroObrigatorio,
+		},
+		{
+			name:       "should fail due to wrong credentials",
+			file:       "buscacep_wrong_credentials.xml",
+			uf:         "SP",
+			cidade:     "São Paulo",
+			logradouro: "Paulista",
+			wantErr:    soawebservices.ErrCredenciaisInvalidas,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			client := MustCreateClient(fixtureClient(t, tt.file))
+			result, err := client.BuscarCEPPorLogradouro(context.TODO(), tt.uf, tt.cidade, tt.logradouro)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if result.Total() != len(tt.wantCEPs) {
+				t.Fatalf("expected %d CEPs, got %+v", len(tt.wantCEPs), result)
+			}
+			for i, cep := range tt.wantCEPs {
+				if result.Resultados[i].CEP.CEP != cep {
+					t.Errorf("expected CEP %s at %d, got %s", cep, i, result.Resultados[i].CEP.CEP)
+				}
+			}
+		})
+	}
+}
+
+func TestBuscaCEP_Pagina(t *testing.T) {
+	busca := soawebservices.BuscaCEP{Resultados: make([]soawebservices.CEPEncontrado, 5)}
+	tests := []struct {
+		numero  int
+		tamanho int
+		want    int
+	}{
+		{numero: 1, tamanho: 2, want: 2},
+		{numero: 3, tamanho: 2, want: 1},
+		{numero: 4, tamanho: 2, want: 0},
+		{numero: 0, tamanho: 2, want: 0},
+	}
+	for _, tt := range tests {
+		if got := busca.Pagina(tt.numero, tt.tamanho); len(got) != tt.want {
+			t.Errorf("Pagina(%d, %d) got %d CEPs, want %d", tt.numero, tt.tamanho, len(got), tt.want)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"github.com/diegohordi/soawebservices"
+	"net"
+	"net/http"
+)
+
+// Error codes returned in the codigo field of the error responses. They are
+// part of the gateway API and must not change.
+const (
+	codigoNaoAutorizado           = "nao_autorizado"
+	codigoLimiteExcedido          = "limite_excedido"
+	codigoRequisicaoInvalida      = "requisicao_invalida"
+	codigoMetodoNaoPermitido      = "metodo_nao_permitido"
+	codigoNaoEncontrado           = "nao_encontrado"
+	codigoDocumentoInvalido       = "documento_invalido"
+	codigoCEPInvalido             = "cep_invalido"
+	codigoDataNascimentoInvalida  = "data_nascimento_invalida"
+	codigoServicoIndisponivel     = "servico_indisponivel"
+	codigoFalhaAutenticacaoOrigem = "falha_autenticacao_origem"
+	codigoSituacaoDesconhecida    = "situacao_cadastral_desconhecida"
+	codigoErroInterno             = "erro_interno"
+)
+
+const (
+	errNaoEncontrado       = soawebservices.Error("nenhum resultado encontrado")
+	errRequisicaoInvalida  = soawebservices.Error("requisição inválida")
+	errNaoAutorizado       = soawebservices.Error("chave de API ausente ou inválida")
+	errLimiteExcedido      = soawebservices.Error("limite de requisições excedido")
+	errMetodoNaoPermitido  = soawebservices.Error("método não permitido")
+	errRecursoInexistente  = soawebservices.Error("recurso inexistente")
+	errServicoIndisponivel = soawebservices.Error("serviço indisponível no momento")
+)
+
+// erroAPI is the body of the error responses.
+type erroAPI struct {
+	Codigo   string `json:"codigo"`
+	Mensagem string `json:"mensagem"`
+}
+
+// erroResposta maps the given error to the HTTP status code and the body of
+// its response. The messages of the errors not caused by the caller are not
+// exposed, as they may describe the gateway configuration.
+func erroResposta(err error) (int, erroAPI) {
+	var statusErr *soawebservices.StatusError
+	var netErr net.Error
+	switch {
+	case errors.Is(err, errNaoAutorizado):
+		return http.StatusUnauthorized, erroAPI{codigoNaoAutorizado, err.Error()}
+	case errors.Is(err, errLimiteExcedido):
+		return http.StatusTooManyRequests, erroAPI{codigoLimiteExcedido, err.Error()}
+	case errors.Is(err, errMetodoNaoPermitido):
+		return http.StatusMethodNotAllowed, erroAPI{codigoMetodoNaoPermitido, err.Error()}
+	case errors.Is(err, errRequisicaoInvalida):
+		return http.StatusBadRequest, erroAPI{codigoRequisicaoInvalida, err.Error()}
+	case errors.Is(err, errNaoEncontrado), errors.Is(err, errRecursoInexistente):
+		return http.StatusNotFound, erroAPI{codigoNaoEncontrado, err.Error()}
+	case errors.Is(err, soawebservices.ErrCPFInvalido), errors.Is(err, soawebservices.ErrCNPJInvalido):
+		return http.StatusUnprocessableEntity, erroAPI{codigoDocumentoInvalido, err.Error()}
+	case errors.Is(err, soawebservices.ErrCEPInvalido):
+		return http.StatusUnprocessableEntity, erroAPI{codigoCEPInvalido, err.Error()}
+	case errors.Is(err, soawebservices.ErrDataNascimentoInvalida), errors.Is(err, soawebservices.ErrDataNascimentoObrigatoria):
+		return http.StatusUnprocessableEntity, erroAPI{codigoDataNascimentoInvalida, err.Error()}
+	case errors.Is(err, soawebservices.ErrPessoaFisicaStatusDesconhecido):
+		return http.StatusBadGateway, erroAPI{codigoSituacaoDesconhecida, err.Error()}
+	case errors.Is(err, soawebservices.ErrCredenciaisInvalidas):
+		return http.StatusBadGateway, erroAPI{codigoFalhaAutenticacaoOrigem, "falha de autenticação no SOA WebServices"}
+	case errors.Is(err, soawebservices.ErrCEPServicoIndisponivel),
+		errors.Is(err, soawebservices.ErrCEPFalhaTransacao),
+		errors.Is(err, errServicoIndisponivel),
+		errors.Is(err, soawebservices.ErrOrcamentoExcedido),
+		errors.Is(err, soawebservices.ErrSaldoInsuficiente),
+		errors.Is(err, context.DeadlineExceeded),
+		errors.As(err, &netErr):
+		return http.StatusServiceUnavailable, erroAPI{codigoServicoIndisponivel, errServicoIndisponivel.Error()}
+	case errors.As(err, &statusErr):
+		return erroStatus(statusErr)
+	}
+	return http.StatusInternalServerError, erroAPI{codigoErroInterno, "erro interno"}
+}
+
+// erroStatus maps the CodigoStatus not handled by erroResposta by their
+// categories. The billing failures of the gateway account, as the lack of
+// credits, are not the fault of the caller and are reported as an unavailable
+// service.
+func erroStatus(err *soawebservices.StatusError) (int, erroAPI) {
+	switch {
+	case err.Retentavel || err.Categoria == soawebservices.CategoriaDisponibilidade,
+		err.Categoria == soawebservices.CategoriaCobranca:
+		return http.StatusServiceUnavailable, erroAPI{codigoServicoIndisponivel, errServicoIndisponivel.Error()}
+	case err.Categoria == soawebservices.CategoriaEntrada:
+		return http.StatusUnprocessableEntity, erroAPI{codigoRequisicaoInvalida, err.Error()}
+	case err.Categoria == soawebservices.CategoriaNaoEncontrado:
+		return http.StatusNotFound, erroAPI{codigoNaoEncontrado, errNaoEncontrado.Error()}
+	case err.Categoria == soawebservices.CategoriaAutenticacao:
+		return http.StatusBadGateway, erroAPI{codigoFalhaAutenticacaoOrigem, "falha de autenticação no SOA WebServices"}
+	}
+	return http.StatusInternalServerError, erroAPI{codigoErroInterno, "erro interno"}
+}
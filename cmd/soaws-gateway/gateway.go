@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"github.com/diegohordi/soawebservices"
+	"github.com/diegohordi/soawebservices/internal/documento"
+	"github.com/diegohordi/soawebservices/internal/ratelimit"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const headerAPIKey = "X-API-Key"
+
+// gateway exposes the Client lookups as JSON endpoints, authenticated by API
+// key and rate limited per caller.
+type gateway struct {
+	client   soawebservices.Client
+	chaves   map[[sha256.Size]byte]string
+	cache    *cache
+	taxa     float64
+	rajada   int
+	timeout  time.Duration
+	logger   *log.Logger
+	mu       sync.Mutex
+	limiters map[string]*ratelimit.Limiter
+	draining int32
+}
+
+// newGateway returns a gateway for the given API keys, mapped to the names of
+// their callers.
+func newGateway(client soawebservices.Client, chaves map[string]string, opts options, logger *log.Logger) *gateway {
+	g := &gateway{
+		client:   client,
+		chaves:   make(map[[sha256.Size]byte]string, len(chaves)),
+		cache:    newCache(opts.cacheTTL, opts.cacheMax),
+		taxa:     opts.taxa,
+		rajada:   opts.rajada,
+		timeout:  opts.timeout,
+		logger:   logger,
+		limiters: make(map[string]*ratelimit.Limiter),
+	}
+	for chave, chamador := range chaves {
+		g.chaves[sha256.Sum256([]byte(chave))] = chamador
+	}
+	return g
+}
+
+func (g *gateway) routes() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", g.healthz)
+	mux.HandleFunc("/readyz", g.readyz)
+	mux.Handle("/cep/", g.autenticar(g.cep))
+	mux.Handle("/cpf/", g.autenticar(g.cpf))
+	mux.Handle("/cnpj/", g.autenticar(g.cnpj))
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		g.writeError(w, r, fmt.Errorf("%w: %s", errRecursoInexistente, r.URL.Path))
+	})
+	return mux
+}
+
+// drain makes the readiness endpoint fail, so the load balancer stops sending
+// requests before the server shuts down.
+func (g *gateway) drain() {
+	atomic.StoreInt32(&g.draining, 1)
+}
+
+func (g *gateway) healthz(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (g *gateway) readyz(w http.ResponseWriter, r *http.Request) {
+	if atomic.LoadInt32(&g.draining) == 1 {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"status": "encerrando"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+type lookupFunc func(ctx context.Context, r *http.Request, documento string) (interface{}, error)
+
+// autenticar wraps the given lookup with the API key authentication, the rate
+// limit of the caller, the cache and the error responses.
+func (g *gateway) autenticar(lookup lookupFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			g.writeError(w, r, errMetodoNaoPermitido)
+			return
+		}
+		chamador, ok := g.chaves[sha256.Sum256([]byte(r.Header.Get(headerAPIKey)))]
+		if !ok {
+			g.writeError(w, r, errNaoAutorizado)
+			return
+		}
+		if !g.limiter(chamador).Allow() {
+			w.Header().Set("Retry-After", "1")
+			g.writeError(w, r, errLimiteExcedido)
+			return
+		}
+		// Everything after the service is the document, so formatted CNPJs
+		// such as 11.222.333/0001-81 are accepted.
+		parts := strings.SplitN(strings.Trim(r.URL.Path, "/"), "/", 2)
+		if len(parts) != 2 || parts[1] == "" {
+			g.writeError(w, r, fmt.Errorf("%w: %s", errRecursoInexistente, r.URL.Path))
+			return
+		}
+		doc := documento.SomenteDigitos(parts[1])
+		key := parts[0] + ":" + doc + ":" + r.URL.Query().Get("nascimento")
+		if result, ok := g.cache.get(key); ok {
+			w.Header().Set("X-Cache", "HIT")
+			writeJSON(w, http.StatusOK, result)
+			return
+		}
+		// The caller is the tenant, so the usage of the Client is attributed
+		// to it.
+		ctx, cancel := context.WithTimeout(soawebservices.WithTenant(r.Context(), chamador), g.timeout)
+		defer cancel()
+		result, err := lookup(ctx, r, doc)
+		if err != nil {
+			g.writeError(w, r, err)
+			return
+		}
+		g.cache.set(key, result)
+		w.Header().Set("X-Cache", "MISS")
+		writeJSON(w, http.StatusOK, result)
+	})
+}
+
+// limiter returns the rate limiter of the given caller.
+func (g *gateway) limiter(chamador string) *ratelimit.Limiter {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	l, ok := g.limiters[chamador]
+	if !ok {
+		l = ratelimit.New(g.taxa, g.rajada)
+		g.limiters[chamador] = l
+	}
+	return l
+}
+
+func (g *gateway) cep(ctx context.Context, r *http.Request, cep string) (interface{}, error) {
+	result, err := g.client.ConsultarCEP(ctx, cep)
+	if err != nil {
+		return nil, err
+	}
+	if result == (soawebservices.CEP{}) {
+		return nil, fmt.Errorf("cep %s: %w", cep, errNaoEncontrado)
+	}
+	return result, nil
+}
+
+func (g *gateway) cpf(ctx context.Context, r *http.Request, cpf string) (interface{}, error) {
+	nascimento := r.URL.Query().Get("nascimento")
+	if nascimento == "" {
+		return nil, soawebservices.ErrDataNascimentoObrigatoria
+	}
+	dataNascimento, err := parseData(nascimento)
+	if err != nil {
+		return nil, fmt.Errorf("%w: data de nascimento %q fora do formato aaaa-mm-dd ou dd/mm/aaaa", errRequisicaoInvalida, nascimento)
+	}
+	return g.client.ConsultarCPF(ctx, cpf, dataNascimento)
+}
+
+func (g *gateway) cnpj(ctx context.Context, r *http.Request, cnpj string) (interface{}, error) {
+	return g.client.ConsultarCNPJ(ctx, cnpj)
+}
+
+// parseData parses a date in the ISO 8601 or in the dd/mm/aaaa format.
+func parseData(value string) (time.Time, error) {
+	if data, err := time.Parse("2006-01-02", value); err == nil {
+		return data, nil
+	}
+	return time.Parse("02/01/2006", value)
+}
+
+func (g *gateway) writeError(w http.ResponseWriter, r *http.Request, err error) {
+	statusCode, body := erroResposta(err)
+	if statusCode >= http.StatusInternalServerError {
+		// The path is not logged, as it holds the looked up document.
+		servico := strings.SplitN(strings.Trim(r.URL.Path, "/"), "/", 2)[0]
+		g.logger.Printf("%s /%s: %v", r.Method, servico, err)
+	}
+	writeJSON(w, statusCode, body)
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(v)
+}
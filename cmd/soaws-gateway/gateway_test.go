@@ -0,0 +1,308 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/diegohordi/soawebservices"
+	"github.com/diegohordi/soawebservices/soawebservicestest"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const chaveTeste = "chave-de-teste"
+
+func newTestGateway(fake *soawebservicestest.FakeClient, taxa float64) *gateway {
+	opts := options{timeout: time.Second, cacheTTL: time.Minute, cacheMax: 10, taxa: taxa, rajada: 1}
+	return newGateway(fake, map[string]string{chaveTeste: "testes"}, opts, log.New(io.Discard, "", 0))
+}
+
+func get(t *testing.T, h http.Handler, path, chave string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if chave != "" {
+		req.Header.Set(headerAPIKey, chave)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func Test_gateway(t *testing.T) {
+	fake := soawebservicestest.NewFakeClient()
+	fake.OnCEP("01001000").Return(soawebservices.CEP{CEP: "01001000", UF: "SP", Cidade: "SAO PAULO"})
+	fake.OnCEP("99999999").Return(soawebservices.CEP{})
+	fake.OnCEP("02002000").ReturnErr(soawebservices.ErrCEPServicoIndisponivel)
+	fake.OnCPF("52998224725").Return(soawebservices.PessoaFisica{Documento: "52998224725", Nome: "JOSE DA SILVA"})
+	fake.OnCPF("11111111111").ReturnErr(soawebservices.ErrCPFInvalido)
+	fake.OnCPF("11144477735").ReturnErr(fmt.Errorf("%w: %q", soawebservices.ErrPessoaFisicaStatusDesconhecido, "14"))
+	fake.OnCNPJ("11222333000181").ReturnErr(soawebservices.ErrCredenciaisInvalidas)
+	fake.OnCNPJ("11444777000161").ReturnErr(soawebservices.NewStatusError(soawebservices.StatusContaBloqueada, "Conta bloqueada"))
+	fake.OnCNPJ("39621470000109").ReturnErr(soawebservices.NewStatusError(soawebservices.StatusSemCreditos, "Usuario sem creditos"))
+	fake.OnCNPJ("04252011000110").ReturnErr(&soawebservices.OrcamentoExcedidoError{})
+	h := newTestGateway(fake, 0).routes()
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		chave      string
+		wantStatus int
+		wantCodigo string
+	}{
+		{
+			name:       "should return a CEP",
+			path:       "/cep/01001-000",
+			chave:      chaveTeste,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "should return a Pessoa Física",
+			path:       "/cpf/529.982.247-25?nascimento=1990-01-02",
+			chave:      chaveTeste,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "should return a Pessoa Jurídica",
+			path:       "/cnpj/99999999999962",
+			chave:      chaveTeste,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "should fail due to a missing API key",
+			path:       "/cep/01001000",
+			wantStatus: http.StatusUnauthorized,
+			wantCodigo: codigoNaoAutorizado,
+		},
+		{
+			name:       "should fail due to an invalid API key",
+			path:       "/cep/01001000",
+			chave:      "outra",
+			wantStatus: http.StatusUnauthorized,
+			wantCodigo: codigoNaoAutorizado,
+		},
+		{
+			name:       "should fail due to a CEP not found",
+			path:       "/cep/99999999",
+			chave:      chaveTeste,
+			wantStatus: http.StatusNotFound,
+			wantCodigo: codigoNaoEncontrado,
+		},
+		{
+			name:       "should fail due to an unavailable service",
+			path:       "/cep/02002000",
+			chave:      chaveTeste,
+			wantStatus: http.StatusServiceUnavailable,
+			wantCodigo: codigoServicoIndisponivel,
+		},
+		{
+			name:       "should fail due to an invalid CPF",
+			path:       "/cpf/11111111111?nascimento=02/01/1990",
+			chave:      chaveTeste,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCodigo: codigoDocumentoInvalido,
+		},
+		{
+			name:       "should fail due to an unknown situação cadastral",
+			path:       "/cpf/11144477735?nascimento=02/01/1990",
+			chave:      chaveTeste,
+			wantStatus: http.StatusBadGateway,
+			wantCodigo: codigoSituacaoDesconhecida,
+		},
+		{
+			name:       "should fail due to a missing data de nascimento",
+			path:       "/cpf/52998224725",
+			chave:      chaveTeste,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCodigo: codigoDataNascimentoInvalida,
+		},
+		{
+			name:       "should fail due to a malformed data de nascimento",
+			path:       "/cpf/52998224725?nascimento=ontem",
+			chave:      chaveTeste,
+			wantStatus: http.StatusBadRequest,
+			wantCodigo: codigoRequisicaoInvalida,
+		},
+		{
+			name:       "should fail due to the upstream credentials",
+			path:       "/cnpj/11222333000181",
+			chave:      chaveTeste,
+			wantStatus: http.StatusBadGateway,
+			wantCodigo: codigoFalhaAutenticacaoOrigem,
+		},
+		{
+			name:       "should fail due to the blocked upstream account",
+			path:       "/cnpj/11444777000161",
+			chave:      chaveTeste,
+			wantStatus: http.StatusServiceUnavailable,
+			wantCodigo: codigoServicoIndisponivel,
+		},
+		{
+			name:       "should fail due to the lack of upstream credits",
+			path:       "/cnpj/39621470000109",
+			chave:      chaveTeste,
+			wantStatus: http.StatusServiceUnavailable,
+			wantCodigo: codigoServicoIndisponivel,
+		},
+		{
+			name:       "should fail due to the exceeded budget",
+			path:       "/cnpj/04252011000110",
+			chave:      chaveTeste,
+			wantStatus: http.StatusServiceUnavailable,
+			wantCodigo: codigoServicoIndisponivel,
+		},
+		{
+			name:       "should fail due to an unknown path",
+			path:       "/rg/123",
+			chave:      chaveTeste,
+			wantStatus: http.StatusNotFound,
+			wantCodigo: codigoNaoEncontrado,
+		},
+		{
+			name:       "should fail due to a missing document",
+			path:       "/cep/",
+			chave:      chaveTeste,
+			wantStatus: http.StatusNotFound,
+			wantCodigo: codigoNaoEncontrado,
+		},
+		{
+			name:       "should fail due to the method",
+			method:     http.MethodPost,
+			path:       "/cep/01001000",
+			chave:      chaveTeste,
+			wantStatus: http.StatusMethodNotAllowed,
+			wantCodigo: codigoMetodoNaoPermitido,
+		},
+		{
+			name:       "should report the liveness",
+			path:       "/healthz",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "should report the readiness",
+			path:       "/readyz",
+			wantStatus: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			req := httptest.NewRequest(method, tt.path, nil)
+			if tt.chave != "" {
+				req.Header.Set(headerAPIKey, tt.chave)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d (%s)", tt.wantStatus, rec.Code, rec.Body)
+			}
+			if tt.wantCodigo == "" {
+				return
+			}
+			body := erroAPI{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if body.Codigo != tt.wantCodigo {
+				t.Errorf("expected codigo %s, got %s", tt.wantCodigo, body.Codigo)
+			}
+		})
+	}
+}
+
+func Test_gateway_cache(t *testing.T) {
+	fake := soawebservicestest.NewFakeClient()
+	h := newTestGateway(fake, 0).routes()
+	for _, want := range []string{"MISS", "HIT"} {
+		rec := get(t, h, "/cnpj/99.999.999/9999-62", chaveTeste)
+		if rec.Code != http.StatusOK || rec.Header().Get("X-Cache") != want {
+			t.Errorf("expected a %s, got %d %s", want, rec.Code, rec.Header().Get("X-Cache"))
+		}
+	}
+	if len(fake.Calls()) != 1 {
+		t.Errorf("expected a single lookup, got %d", len(fake.Calls()))
+	}
+}
+
+func Test_gateway_rateLimit(t *testing.T) {
+	g := newTestGateway(soawebservicestest.NewFakeClient(), 0.001)
+	h := g.routes()
+	if rec := get(t, h, "/cep/01001000", chaveTeste); rec.Code != http.StatusOK {
+		t.Fatalf("expected the first request to be allowed, got %d", rec.Code)
+	}
+	rec := get(t, h, "/cep/01001000", chaveTeste)
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") == "" {
+		t.Errorf("expected the second request to be limited, got %d", rec.Code)
+	}
+	g.drain()
+	if rec = get(t, h, "/readyz", ""); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected the gateway not to be ready while draining, got %d", rec.Code)
+	}
+}
+
+func Test_parseOptions(t *testing.T) {
+	dir := t.TempDir()
+	chaves := filepath.Join(dir, "chaves.json")
+	if err := os.WriteFile(chaves, []byte(`{"chave": "faturamento"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	env := map[string]string{envEmail: "test@test.com", envSenha: "test", envChaves: chaves}
+	tests := []struct {
+		name    string
+		args    []string
+		env     map[string]string
+		wantErr bool
+	}{
+		{
+			name: "should read the credentials and keys from the environment",
+			env:  env,
+		},
+		{
+			name: "should read the credentials from the flags",
+			args: []string{"-email", "test@test.com", "-senha", "test", "-chaves", chaves, "-ambiente", "test-drive"},
+			env:  map[string]string{},
+		},
+		{
+			name:    "should fail due to missing credentials",
+			env:     map[string]string{envChaves: chaves},
+			wantErr: true,
+		},
+		{
+			name:    "should fail due to missing keys",
+			env:     map[string]string{envEmail: "test@test.com", envSenha: "test"},
+			wantErr: true,
+		},
+		{
+			name:    "should fail due to an invalid ambiente",
+			args:    []string{"-ambiente", "homologacao"},
+			env:     env,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			opts, err := parseOptions(tt.args, func(key string) string { return tt.env[key] }, io.Discard)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if _, err = loadChaves(opts.chaves); err != nil {
+				t.Errorf("unexpected error %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,196 @@
+// Command soaws-gateway exposes the SOA WebServices lookups as JSON endpoints,
+// so other services can use them without holding the SOA WebServices
+// credentials.
+//
+// Usage:
+//
+//	soaws-gateway -chaves chaves.json [-addr :8080] [flags]
+//
+// Endpoints:
+//
+//	GET /cep/{cep}
+//	GET /cpf/{cpf}?nascimento=<aaaa-mm-dd|dd/mm/aaaa>
+//	GET /cnpj/{cnpj}
+//	GET /healthz
+//	GET /readyz
+//
+// The lookup endpoints require an API key in the X-API-Key header. The keys
+// are read from a JSON file mapping each key to the name of its caller, as in
+// {"<chave>": "faturamento"}, and every caller has its own rate limit.
+// Successful lookups are cached in memory.
+//
+// Errors are returned as {"codigo": "<codigo>", "mensagem": "<mensagem>"},
+// where codigo is one of nao_autorizado, limite_excedido, requisicao_invalida,
+// metodo_nao_permitido, nao_encontrado, documento_invalido, cep_invalido,
+// data_nascimento_invalida, servico_indisponivel, falha_autenticacao_origem
+// and erro_interno.
+//
+// The SOA WebServices credentials are read from the -email and -senha flags
+// or the SOAWS_EMAIL and SOAWS_SENHA environment variables.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/diegohordi/soawebservices"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+const (
+	envEmail    = "SOAWS_EMAIL"
+	envSenha    = "SOAWS_SENHA"
+	envAmbiente = "SOAWS_AMBIENTE"
+	envChaves   = "SOAWS_GATEWAY_CHAVES"
+)
+
+const (
+	defaultBaseURL     = "https://soawebservices.com.br"
+	defaultTimeout     = 30 * time.Second
+	shutdownTimeout    = 30 * time.Second
+	readyzGracePeriod  = 5 * time.Second
+	defaultCacheTTL    = time.Hour
+	defaultCacheMax    = 10000
+	defaultTaxa        = 5
+	defaultRajada      = 10
+	defaultGatewayAddr = ":8080"
+)
+
+type options struct {
+	addr     string
+	chaves   string
+	email    string
+	senha    string
+	ambiente soawebservices.Ambiente
+	baseURL  string
+	timeout  time.Duration
+	cacheTTL time.Duration
+	cacheMax int
+	taxa     float64
+	rajada   int
+}
+
+func parseOptions(args []string, getenv func(string) string, output io.Writer) (options, error) {
+	fs := flag.NewFlagSet("soaws-gateway", flag.ContinueOnError)
+	fs.SetOutput(output)
+	opts := options{}
+	fs.StringVar(&opts.addr, "addr", defaultGatewayAddr, "endereço em que o servidor escuta")
+	fs.StringVar(&opts.chaves, "chaves", "", "arquivo JSON com as chaves de API e seus chamadores (padrão: $"+envChaves+")")
+	fs.StringVar(&opts.email, "email", "", "e-mail de acesso (padrão: $"+envEmail+")")
+	fs.StringVar(&opts.senha, "senha", "", "senha de acesso (padrão: $"+envSenha+")")
+	fs.StringVar((*string)(&opts.ambiente), "ambiente", "", "ambiente: producao ou test-drive (padrão: $"+envAmbiente+" ou producao)")
+	fs.StringVar(&opts.baseURL, "url", defaultBaseURL, "URL base do serviço")
+	fs.DurationVar(&opts.timeout, "timeout", defaultTimeout, "tempo máximo de cada consulta")
+	fs.DurationVar(&opts.cacheTTL, "cache-ttl", defaultCacheTTL, "tempo em que as consultas ficam em cache (0 para desabilitar)")
+	fs.IntVar(&opts.cacheMax, "cache-max", defaultCacheMax, "número máximo de consultas em cache")
+	fs.Float64Var(&opts.taxa, "taxa", defaultTaxa, "número máximo de requisições por segundo de cada chamador (0 para ilimitado)")
+	fs.IntVar(&opts.rajada, "rajada", defaultRajada, "número de requisições que um chamador pode fazer de uma vez")
+	if err := fs.Parse(args); err != nil {
+		return options{}, err
+	}
+	if fs.NArg() > 0 {
+		return options{}, fmt.Errorf("argumentos inesperados: %v", fs.Args())
+	}
+	if opts.chaves == "" {
+		opts.chaves = getenv(envChaves)
+	}
+	if opts.email == "" {
+		opts.email = getenv(envEmail)
+	}
+	if opts.senha == "" {
+		opts.senha = getenv(envSenha)
+	}
+	if opts.ambiente == "" {
+		opts.ambiente = soawebservices.Ambiente(getenv(envAmbiente))
+	}
+	if opts.ambiente == "" {
+		opts.ambiente = soawebservices.Producao
+	}
+	if opts.ambiente != soawebservices.Producao && opts.ambiente != soawebservices.TestDrive {
+		return options{}, fmt.Errorf("ambiente %q inválido, use producao ou test-drive", opts.ambiente)
+	}
+	if opts.email == "" || opts.senha == "" {
+		return options{}, errors.New("informe as credenciais por -email e -senha ou $" + envEmail + " e $" + envSenha)
+	}
+	if opts.chaves == "" {
+		return options{}, errors.New("informe o arquivo de chaves de API por -chaves ou $" + envChaves)
+	}
+	return opts, nil
+}
+
+// loadChaves reads the JSON file of API keys, mapped to their callers.
+func loadChaves(path string) (map[string]string, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	chaves := make(map[string]string)
+	if err = json.Unmarshal(buf, &chaves); err != nil {
+		return nil, fmt.Errorf("arquivo de chaves %s inválido: %w", path, err)
+	}
+	for chave, chamador := range chaves {
+		if chave == "" || chamador == "" {
+			return nil, fmt.Errorf("arquivo de chaves %s inválido: chaves e chamadores não podem ser vazios", path)
+		}
+	}
+	if len(chaves) == 0 {
+		return nil, fmt.Errorf("arquivo de chaves %s sem chaves", path)
+	}
+	return chaves, nil
+}
+
+func run(ctx context.Context, args []string, getenv func(string) string, stderr io.Writer) error {
+	opts, err := parseOptions(args, getenv, stderr)
+	if err != nil {
+		return err
+	}
+	chaves, err := loadChaves(opts.chaves)
+	if err != nil {
+		return err
+	}
+	httpClient := &http.Client{Timeout: opts.timeout}
+	credenciais := soawebservices.Credenciais{Email: opts.email, Senha: opts.senha}
+	client, err := soawebservices.NewClient(httpClient, opts.baseURL, opts.ambiente, credenciais)
+	if err != nil {
+		return err
+	}
+	logger := log.New(stderr, "", log.LstdFlags)
+	g := newGateway(client, chaves, opts, logger)
+	server := &http.Server{
+		Addr:              opts.addr,
+		Handler:           g.routes(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	errServe := make(chan error, 1)
+	go func() {
+		errServe <- server.ListenAndServe()
+	}()
+	logger.Printf("soaws-gateway escutando em %s", opts.addr)
+	select {
+	case err = <-errServe:
+		return err
+	case <-ctx.Done():
+	}
+	g.drain()
+	time.Sleep(readyzGracePeriod)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	return server.Shutdown(shutdownCtx)
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := run(ctx, os.Args[1:], os.Getenv, os.Stderr); err != nil && !errors.Is(err, flag.ErrHelp) {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
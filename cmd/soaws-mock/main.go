@@ -0,0 +1,266 @@
+// Command soaws-mock serves a local stand-in for soawebservices.com.br, for
+// offline development and QA.
+//
+// Usage:
+//
+//	soaws-mock [-addr :8080] [-fixtures test/testdata] [-latencia 200ms] [-cenario cpf=wrong_credentials]
+//
+// It answers the CPF, CNPJ and CEP endpoints of every environment with the
+// fixtures of the given directory, named as in test/testdata:
+// consultacpf_<cenario>.json, consultacnpj_<cenario>.json and
+// consultacep_<cenario>.xml. Successful fixtures are served to the requests
+// for their documents; a scenario makes every request to a service be
+// answered with the same fixture.
+//
+// Scenarios, latency and failures can be switched at runtime:
+//
+//	GET    /_mock/cenarios                        lists the scenarios
+//	PUT    /_mock/cenarios/{servico}?nome=<nome>  switches the scenario
+//	DELETE /_mock/cenarios/{servico}              removes the scenario
+//	PUT    /_mock/falhas/{servico}?status=503     answers with an HTTP status
+//	DELETE /_mock/falhas/{servico}                removes the failure
+//	PUT    /_mock/latencia?valor=500ms            delays every response
+//	GET    /_mock/requisicoes                     lists the received requests
+//	POST   /_mock/reset                           removes scenarios, failures and latency
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/diegohordi/soawebservices/soawebservicestest"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const adminPrefix = "/_mock/"
+
+var servicos = map[string]soawebservicestest.Servico{
+	"cep":  soawebservicestest.ServicoCEP,
+	"cpf":  soawebservicestest.ServicoCPF,
+	"cnpj": soawebservicestest.ServicoCNPJ,
+}
+
+// cenarios is a repeatable flag of servico=nome scenarios.
+type cenarios map[soawebservicestest.Servico]string
+
+func (c cenarios) String() string {
+	pares := make([]string, 0, len(c))
+	for servico, nome := range c {
+		pares = append(pares, fmt.Sprintf("%s=%s", servico, nome))
+	}
+	return strings.Join(pares, ",")
+}
+
+func (c cenarios) Set(value string) error {
+	parts := strings.SplitN(value, "=", 2)
+	servico, ok := servicos[strings.ToLower(strings.TrimSpace(parts[0]))]
+	if !ok || len(parts) != 2 {
+		return fmt.Errorf("cenário %q inválido, use <cep|cpf|cnpj>=<nome>", value)
+	}
+	c[servico] = strings.TrimSpace(parts[1])
+	return nil
+}
+
+type options struct {
+	addr     string
+	fixtures string
+	latencia time.Duration
+	cenarios cenarios
+}
+
+func parseOptions(args []string, output io.Writer) (options, error) {
+	fs := flag.NewFlagSet("soaws-mock", flag.ContinueOnError)
+	fs.SetOutput(output)
+	opts := options{cenarios: make(cenarios)}
+	fs.StringVar(&opts.addr, "addr", ":8080", "endereço em que o servidor escuta")
+	fs.StringVar(&opts.fixtures, "fixtures", "", "diretório com as fixtures, no formato de test/testdata")
+	fs.DurationVar(&opts.latencia, "latencia", 0, "latência adicionada a cada resposta")
+	fs.Var(opts.cenarios, "cenario", "cenário inicial de um serviço, como cpf=wrong_credentials (pode ser repetido)")
+	if err := fs.Parse(args); err != nil {
+		return options{}, err
+	}
+	if fs.NArg() > 0 {
+		return options{}, fmt.Errorf("argumentos inesperados: %s", strings.Join(fs.Args(), " "))
+	}
+	return opts, nil
+}
+
+// newHandler returns the mock handler configured with the given options.
+func newHandler(opts options) (*soawebservicestest.Handler, error) {
+	h := soawebservicestest.NewHandler()
+	if opts.fixtures != "" {
+		if err := h.LoadFixtures(opts.fixtures); err != nil {
+			return nil, err
+		}
+	}
+	for servico, nome := range opts.cenarios {
+		if err := h.SetScenario(servico, nome); err != nil {
+			return nil, err
+		}
+	}
+	h.SetLatency(opts.latencia)
+	return h, nil
+}
+
+// newMux returns the mux serving the admin endpoints and, on every other
+// path, the mock handler.
+func newMux(h *soawebservicestest.Handler) *http.ServeMux {
+	a := &admin{handler: h}
+	mux := http.NewServeMux()
+	mux.HandleFunc(adminPrefix+"cenarios", a.listarCenarios)
+	mux.HandleFunc(adminPrefix+"cenarios/", a.cenario)
+	mux.HandleFunc(adminPrefix+"falhas/", a.falha)
+	mux.HandleFunc(adminPrefix+"latencia", a.latencia)
+	mux.HandleFunc(adminPrefix+"requisicoes", a.requisicoes)
+	mux.HandleFunc(adminPrefix+"reset", a.reset)
+	mux.Handle("/", h)
+	return mux
+}
+
+type admin struct {
+	handler *soawebservicestest.Handler
+}
+
+func (a *admin) listarCenarios(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+	writeJSON(w, http.StatusOK, a.handler.Scenarios())
+}
+
+func (a *admin) cenario(w http.ResponseWriter, r *http.Request) {
+	servico, ok := servicoFromPath(w, r, "cenarios/")
+	if !ok || !allowMethods(w, r, http.MethodPut, http.MethodDelete) {
+		return
+	}
+	nome := r.URL.Query().Get("nome")
+	if r.Method == http.MethodDelete {
+		nome = ""
+	} else if nome == "" {
+		writeError(w, http.StatusBadRequest, errors.New("informe o nome do cenário"))
+		return
+	}
+	if err := a.handler.SetScenario(servico, nome); err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (a *admin) falha(w http.ResponseWriter, r *http.Request) {
+	servico, ok := servicoFromPath(w, r, "falhas/")
+	if !ok || !allowMethods(w, r, http.MethodPut, http.MethodDelete) {
+		return
+	}
+	statusCode := 0
+	if r.Method == http.MethodPut {
+		var err error
+		statusCode, err = strconv.Atoi(r.URL.Query().Get("status"))
+		if err != nil || statusCode < 100 || statusCode > 599 {
+			writeError(w, http.StatusBadRequest, errors.New("informe um status HTTP válido"))
+			return
+		}
+	}
+	a.handler.SetHTTPStatus(servico, statusCode)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (a *admin) latencia(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodPut) {
+		return
+	}
+	latencia, err := time.ParseDuration(r.URL.Query().Get("valor"))
+	if err != nil || latencia < 0 {
+		writeError(w, http.StatusBadRequest, errors.New("informe uma latência válida, como 500ms"))
+		return
+	}
+	a.handler.SetLatency(latencia)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (a *admin) requisicoes(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+	writeJSON(w, http.StatusOK, a.handler.Requests())
+}
+
+func (a *admin) reset(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodPost) {
+		return
+	}
+	a.handler.Reset()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func servicoFromPath(w http.ResponseWriter, r *http.Request, prefix string) (soawebservicestest.Servico, bool) {
+	name := strings.TrimPrefix(r.URL.Path, adminPrefix+prefix)
+	servico, ok := servicos[strings.ToLower(name)]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("serviço %q desconhecido, use cep, cpf ou cnpj", name))
+	}
+	return servico, ok
+}
+
+func allowMethods(w http.ResponseWriter, r *http.Request, methods ...string) bool {
+	for _, method := range methods {
+		if r.Method == method {
+			return true
+		}
+	}
+	w.Header().Set("Allow", strings.Join(methods, ", "))
+	writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("método %s não permitido", r.Method))
+	return false
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, statusCode int, err error) {
+	writeJSON(w, statusCode, map[string]string{"erro": err.Error()})
+}
+
+func run(ctx context.Context, args []string, stderr io.Writer) error {
+	opts, err := parseOptions(args, stderr)
+	if err != nil {
+		return err
+	}
+	h, err := newHandler(opts)
+	if err != nil {
+		return err
+	}
+	server := &http.Server{Addr: opts.addr, Handler: newMux(h)}
+	errServe := make(chan error, 1)
+	go func() {
+		errServe <- server.ListenAndServe()
+	}()
+	_, _ = fmt.Fprintf(stderr, "soaws-mock escutando em %s\n", opts.addr)
+	select {
+	case err = <-errServe:
+		return err
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return server.Shutdown(shutdownCtx)
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if err := run(ctx, os.Args[1:], os.Stderr); err != nil && !errors.Is(err, flag.ErrHelp) {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
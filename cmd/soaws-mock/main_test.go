@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"github.com/diegohordi/soawebservices"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_newMux(t *testing.T) {
+	opts, err := parseOptions([]string{"-fixtures", "../../test/testdata", "-cenario", "cnpj=invalid_cnpj"}, io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := newHandler(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(newMux(h))
+	defer server.Close()
+	client, err := soawebservices.NewClient(server.Client(), server.URL, soawebservices.TestDrive, soawebservices.Credenciais{Email: "test@test.com", Senha: "test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	admin := func(method, path string, wantStatus int) {
+		t.Helper()
+		req, err := http.NewRequest(method, server.URL+path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := server.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != wantStatus {
+			t.Fatalf("%s %s = %d, expected %d", method, path, resp.StatusCode, wantStatus)
+		}
+	}
+
+	if _, err = client.ConsultarCNPJ(context.TODO(), "99999999999962"); !errors.Is(err, soawebservices.ErrCNPJInvalido) {
+		t.Errorf("expected the initial scenario error, got %v", err)
+	}
+	admin(http.MethodDelete, "/_mock/cenarios/cnpj", http.StatusNoContent)
+	if _, err = client.ConsultarCNPJ(context.TODO(), "99999999999962"); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	admin(http.MethodPut, "/_mock/cenarios/cpf?nome=wrong_credentials", http.StatusNoContent)
+	if _, err = client.ConsultarCNPJ(context.TODO(), "99999999999962"); err != nil {
+		t.Errorf("expected the scenario to switch only the CPF service, got %v", err)
+	}
+	admin(http.MethodPut, "/_mock/cenarios/cpf?nome=unknown", http.StatusNotFound)
+	admin(http.MethodPut, "/_mock/cenarios/rg?nome=success", http.StatusNotFound)
+
+	admin(http.MethodPut, "/_mock/falhas/cep?status=503", http.StatusNoContent)
+	if _, err = client.ConsultarCEP(context.TODO(), "99999999"); err == nil {
+		t.Error("expected the injected failure")
+	}
+	admin(http.MethodPut, "/_mock/latencia?valor=10ms", http.StatusNoContent)
+	admin(http.MethodPut, "/_mock/latencia?valor=rapido", http.StatusBadRequest)
+	admin(http.MethodGet, "/_mock/reset", http.StatusMethodNotAllowed)
+	admin(http.MethodPost, "/_mock/reset", http.StatusNoContent)
+	if _, err = client.ConsultarCEP(context.TODO(), "99999999"); err != nil {
+		t.Errorf("unexpected error after reset %v", err)
+	}
+	admin(http.MethodGet, "/_mock/cenarios", http.StatusOK)
+	admin(http.MethodGet, "/_mock/requisicoes", http.StatusOK)
+}
+
+func Test_parseOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr bool
+	}{
+		{
+			name: "should parse the default options",
+			args: []string{},
+		},
+		{
+			name: "should parse repeated scenarios",
+			args: []string{"-cenario", "cpf=wrong_credentials", "-cenario", "CEP=not_found"},
+		},
+		{
+			name:    "should fail due to an invalid scenario",
+			args:    []string{"-cenario", "rg=success"},
+			wantErr: true,
+		},
+		{
+			name:    "should fail due to unexpected arguments",
+			args:    []string{"fixtures"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if _, err := parseOptions(tt.args, io.Discard); (err != nil) != tt.wantErr {
+				t.Errorf("parseOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,311 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/diegohordi/soawebservices"
+	"github.com/diegohordi/soawebservices/internal/ratelimit"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	statusOK   = "ok"
+	statusErro = "erro"
+)
+
+type batchFlags struct {
+	entrada      string
+	saida        string
+	checkpoint   string
+	concorrencia int
+	taxa         float64
+	precos       precos
+}
+
+// precos is a repeatable flag of tipo=valor prices, as in cpf=0,50.
+type precos soawebservices.TabelaPrecos
+
+func (p precos) String() string {
+	pares := make([]string, 0, len(p))
+	for servico, valor := range p {
+		pares = append(pares, fmt.Sprintf("%s=%s", servico, valor))
+	}
+	sort.Strings(pares)
+	return strings.Join(pares, " ")
+}
+
+func (p precos) Set(value string) error {
+	parts := strings.SplitN(value, "=", 2)
+	tipo := strings.ToLower(strings.TrimSpace(parts[0]))
+	if len(parts) != 2 || tipo != tipoCEP && tipo != tipoCPF && tipo != tipoCNPJ {
+		return fmt.Errorf("preço %q inválido, use <cep|cpf|cnpj>=<valor>", value)
+	}
+	valor, err := soawebservices.ParseValor(parts[1])
+	if err != nil {
+		return fmt.Errorf("preço %q inválido: %w", value, err)
+	}
+	p[soawebservices.Servico(tipo)] = valor
+	return nil
+}
+
+// batch looks up every entry of a CSV or JSONL file, writing one output row
+// per entry. Processed entries are recorded in a checkpoint file, so running
+// the same command again after an interruption resumes from where it stopped,
+// appending to the output file.
+func (a *app) batch(args []string) error {
+	fs := flag.NewFlagSet("batch", flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	flags := newConfigFlags(fs)
+	bf := batchFlags{precos: make(precos)}
+	fs.StringVar(&bf.entrada, "entrada", "", "arquivo de entrada .csv ou .jsonl, com os campos tipo, documento e nascimento")
+	fs.StringVar(&bf.saida, "saida", "", "arquivo de saída .csv ou .jsonl")
+	fs.StringVar(&bf.checkpoint, "checkpoint", "", "arquivo de checkpoint (padrão: <saida>.checkpoint)")
+	fs.IntVar(&bf.concorrencia, "concorrencia", 4, "número de consultas simultâneas")
+	fs.Float64Var(&bf.taxa, "taxa", 0, "número máximo de consultas por segundo (0 para ilimitado)")
+	fs.Var(bf.precos, "preco", "preço da consulta de um tipo, como cpf=0,50 (pode ser repetido); se informado, o saldo é verificado antes de começar")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return usageError{err}
+	}
+	if bf.entrada == "" || bf.saida == "" {
+		return usageError{errors.New("informe os arquivos de -entrada e -saida")}
+	}
+	if bf.concorrencia < 1 {
+		return usageError{errors.New("a concorrência deve ser maior que zero")}
+	}
+	if bf.checkpoint == "" {
+		bf.checkpoint = bf.saida + ".checkpoint"
+	}
+	cfg, err := flags.resolve(a.getenv)
+	if err != nil {
+		return usageError{err}
+	}
+	formatoEntrada, err := formatoArquivo(bf.entrada)
+	if err != nil {
+		return usageError{err}
+	}
+	formatoSaida, err := formatoArquivo(bf.saida)
+	if err != nil {
+		return usageError{err}
+	}
+
+	in, err := os.Open(bf.entrada)
+	if err != nil {
+		return err
+	}
+	defer func(in *os.File) {
+		_ = in.Close()
+	}(in)
+	l, err := newLeitor(in, formatoEntrada)
+	if err != nil {
+		return err
+	}
+	cp, err := openCheckpoint(bf.checkpoint)
+	if err != nil {
+		return fmt.Errorf("não foi possível abrir o checkpoint: %w", err)
+	}
+	defer func(cp *checkpoint) {
+		_ = cp.Close()
+	}(cp)
+	client, err := a.newClient(cfg)
+	if err != nil {
+		return usageError{err}
+	}
+	if len(bf.precos) > 0 {
+		if err = a.verificarSaldo(client, cfg, bf, formatoEntrada, cp); err != nil {
+			return err
+		}
+	}
+	out, err := os.OpenFile(bf.saida, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer func(out *os.File) {
+		_ = out.Close()
+	}(out)
+	info, err := out.Stat()
+	if err != nil {
+		return err
+	}
+	e, err := newEscritor(out, formatoSaida, info.Size() == 0)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go func() {
+		// After the first interrupt, the lookups in flight are still completed
+		// and written; a second one terminates the process right away.
+		<-ctx.Done()
+		stop()
+	}()
+	p := &processamento{
+		client:  client,
+		cfg:     cfg,
+		limiter: ratelimit.New(bf.taxa, 1),
+	}
+	resumo, err := p.executar(ctx, l, e, cp, bf.concorrencia)
+	_, _ = fmt.Fprintf(a.stderr, "%d consultadas, %d com erro, %d já processadas anteriormente\n", resumo.consultadas, resumo.erros, resumo.ignoradas)
+	if err == nil && ctx.Err() != nil {
+		return errors.New("interrompido: execute o mesmo comando para continuar")
+	}
+	return err
+}
+
+// verificarSaldo counts the pending entries of each type and checks that the
+// account balance covers their cost, so the batch does not stop halfway
+// through for lack of credits.
+func (a *app) verificarSaldo(client soawebservices.Client, cfg config, bf batchFlags, formato string, cp *checkpoint) error {
+	in, err := os.Open(bf.entrada)
+	if err != nil {
+		return err
+	}
+	defer func(in *os.File) {
+		_ = in.Close()
+	}(in)
+	l, err := newLeitor(in, formato)
+	if err != nil {
+		return err
+	}
+	quantidades := make(map[soawebservices.Servico]int)
+	for {
+		en, err := l.ler()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if tipo, err := en.tipo(); err == nil && !cp.processada(en.Linha) {
+			quantidades[soawebservices.Servico(tipo)]++
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Timeout)
+	defer cancel()
+	estimativa, err := soawebservices.VerificarSaldo(ctx, client, soawebservices.TabelaPrecos(bf.precos), quantidades)
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(a.stderr, "custo estimado de %s, saldo de %s\n", estimativa.Custo, estimativa.Saldo)
+	return nil
+}
+
+type resumo struct {
+	consultadas int
+	erros       int
+	ignoradas   int
+}
+
+type processamento struct {
+	client  soawebservices.Client
+	cfg     config
+	limiter *ratelimit.Limiter
+}
+
+// executar reads the entries, looks them up with the given concurrency and
+// writes the results in completion order. An entry is marked in the checkpoint
+// only after its output row is written. Once the context is done no new
+// lookups are started, but the ones in flight are completed and written.
+func (p *processamento) executar(ctx context.Context, l leitor, e escritor, cp *checkpoint, concorrencia int) (resumo, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	r := resumo{}
+	entradas := make(chan entrada)
+	saidas := make(chan saida)
+	errRead := make(chan error, 1)
+
+	go func() {
+		defer close(entradas)
+		for {
+			en, err := l.ler()
+			if errors.Is(err, io.EOF) {
+				errRead <- nil
+				return
+			}
+			if err != nil {
+				errRead <- err
+				return
+			}
+			if cp.processada(en.Linha) {
+				r.ignoradas++
+				continue
+			}
+			select {
+			case entradas <- en:
+			case <-ctx.Done():
+				errRead <- nil
+				return
+			}
+		}
+	}()
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < concorrencia; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for en := range entradas {
+				if err := p.limiter.Wait(ctx); err != nil {
+					continue
+				}
+				saidas <- p.consultar(en)
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(saidas)
+	}()
+
+	var errWrite error
+	for s := range saidas {
+		if errWrite != nil {
+			continue
+		}
+		if errWrite = e.escrever(s); errWrite == nil {
+			errWrite = cp.marcar(s.Linha)
+		}
+		if errWrite != nil {
+			cancel()
+			continue
+		}
+		r.consultadas++
+		if s.Status == statusErro {
+			r.erros++
+		}
+	}
+	if err := <-errRead; err != nil {
+		return r, err
+	}
+	return r, errWrite
+}
+
+func (p *processamento) consultar(en entrada) saida {
+	s := saida{entrada: en, Status: statusOK}
+	if tipo, err := en.tipo(); err == nil {
+		s.Tipo = tipo
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), p.cfg.Timeout)
+	defer cancel()
+	result, err := consultar(ctx, p.client, en)
+	if err != nil {
+		s.Status = statusErro
+		s.ErroClasse = classe(err)
+		s.ErroCodigo = codigoStatus(err)
+		s.ErroMensagem = err.Error()
+		return s
+	}
+	s.Resultado = result.valor
+	s.campos = result.campos
+	return s
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"github.com/diegohordi/soawebservices"
+	"github.com/diegohordi/soawebservices/soawebservicestest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func Test_app_batch(t *testing.T) {
+	dir := t.TempDir()
+	entradaCSV := filepath.Join(dir, "entrada.csv")
+	content := "tipo,documento,nascimento\n" +
+		"cpf,529.982.247-25,02/01/1990\n" +
+		",11.222.333/0001-81,\n" +
+		"cep,01001-000,\n" +
+		"cpf,111.111.111-11,02/01/1990\n" +
+		"cpf,529.982.247-25,\n"
+	if err := os.WriteFile(entradaCSV, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	fake := soawebservicestest.NewFakeClient()
+	fake.OnCEP("01001000").Return(soawebservices.CEP{CEP: "01001000", UF: "SP", Cidade: "SAO PAULO"})
+	fake.OnCPF("11111111111").ReturnErr(soawebservices.ErrCPFInvalido)
+
+	saidaCSV := filepath.Join(dir, "saida.csv")
+	a, _, stderr, _ := newTestApp(fake, nil)
+	if code := a.run([]string{"batch", "-entrada", entradaCSV, "-saida", saidaCSV, "-concorrencia", "2"}); code != exitSucesso {
+		t.Fatalf("run() = %d (stderr: %s)", code, stderr)
+	}
+
+	f, err := os.Open(saidaCSV)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 6 {
+		t.Fatalf("expected a header and 5 rows, got %d records", len(records))
+	}
+	header := records[0]
+	col := func(record []string, name string) string {
+		for i, h := range header {
+			if h == name {
+				return record[i]
+			}
+		}
+		t.Fatalf("column %s not found", name)
+		return ""
+	}
+	rows := records[1:]
+	sort.Slice(rows, func(i, j int) bool { return col(rows[i], "linha") < col(rows[j], "linha") })
+	want := []struct {
+		tipo, status, classe, codigo string
+	}{
+		{tipoCPF, statusOK, "", ""},
+		{tipoCNPJ, statusOK, "", ""},
+		{tipoCEP, statusOK, "", ""},
+		{tipoCPF, statusErro, classeDadosInvalidos, soawebservices.StatusDocumentoInvalido},
+		{tipoCPF, statusErro, classeDadosInvalidos, ""},
+	}
+	for i, w := range want {
+		row := rows[i]
+		if col(row, "tipo") != w.tipo || col(row, "status") != w.status || col(row, "erro_classe") != w.classe || col(row, "erro_codigo") != w.codigo {
+			t.Errorf("row %d: unexpected %v", i, row)
+		}
+	}
+	if col(rows[2], "cidade") != "SAO PAULO" {
+		t.Errorf("expected the CEP row to be enriched, got %v", rows[2])
+	}
+}
+
+func Test_app_batch_resume(t *testing.T) {
+	dir := t.TempDir()
+	entradaJSONL := filepath.Join(dir, "entrada.jsonl")
+	content := `{"tipo": "cep", "documento": "01001000"}
+{"tipo": "cep", "documento": "02002000"}
+{"tipo": "cep", "documento": "03003000"}
+`
+	if err := os.WriteFile(entradaJSONL, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	saidaJSONL := filepath.Join(dir, "saida.jsonl")
+	if err := os.WriteFile(saidaJSONL+".checkpoint", []byte("1\n3\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	fake := soawebservicestest.NewFakeClient()
+	a, _, stderr, _ := newTestApp(fake, nil)
+	if code := a.run([]string{"batch", "-entrada", entradaJSONL, "-saida", saidaJSONL, "-taxa", "100"}); code != exitSucesso {
+		t.Fatalf("run() = %d (stderr: %s)", code, stderr)
+	}
+	calls := fake.Calls()
+	if len(calls) != 1 || calls[0].Documento != "02002000" {
+		t.Fatalf("expected only the pending line to be looked up, got %+v", calls)
+	}
+
+	buf, err := os.ReadFile(saidaJSONL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(buf)), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expected 1 output line, got %d", len(lines))
+	}
+	out := struct {
+		Linha     int
+		Status    string
+		Resultado soawebservices.CEP
+	}{}
+	if err = json.Unmarshal([]byte(lines[0]), &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Linha != 2 || out.Status != statusOK || out.Resultado.CEP != "02002000" {
+		t.Errorf("unexpected output %+v", out)
+	}
+
+	checkpoint, err := os.ReadFile(saidaJSONL + ".checkpoint")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(checkpoint) != "1\n3\n2\n" {
+		t.Errorf("unexpected checkpoint %q", checkpoint)
+	}
+
+	if code := a.run([]string{"batch", "-entrada", entradaJSONL, "-saida", saidaJSONL}); code != exitSucesso {
+		t.Fatalf("run() = %d (stderr: %s)", code, stderr)
+	}
+	if len(fake.Calls()) != 1 {
+		t.Errorf("expected no lookups once every line is processed, got %d", len(fake.Calls()))
+	}
+}
+
+func Test_app_batch_saldo(t *testing.T) {
+	dir := t.TempDir()
+	entradaCSV := filepath.Join(dir, "entrada.csv")
+	content := "documento,nascimento\n01001-000,\n02002-000,\n529.982.247-25,02/01/1990\n"
+	if err := os.WriteFile(entradaCSV, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name      string
+		saldo     soawebservices.Valor
+		wantCode  int
+		wantCalls int
+	}{
+		{
+			name:      "should start a batch covered by the balance",
+			saldo:     soawebservices.Reais(0, 70),
+			wantCode:  exitSucesso,
+			wantCalls: 4,
+		},
+		{
+			name:      "should refuse a batch not covered by the balance",
+			saldo:     soawebservices.Reais(0, 69),
+			wantCode:  exitSaldoInsuficiente,
+			wantCalls: 1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			fake := soawebservicestest.NewFakeClient()
+			fake.OnSaldo().Return(soawebservices.Saldo{Disponivel: tt.saldo})
+			a, _, stderr, _ := newTestApp(fake, nil)
+			saida := filepath.Join(t.TempDir(), "saida.jsonl")
+			code := a.run([]string{"batch", "-entrada", entradaCSV, "-saida", saida, "-preco", "cep=0,10", "-preco", "cpf=0,50"})
+			if code != tt.wantCode {
+				t.Fatalf("run() = %d, want %d (stderr: %s)", code, tt.wantCode, stderr)
+			}
+			if len(fake.Calls()) != tt.wantCalls {
+				t.Errorf("expected %d calls, got %d", tt.wantCalls, len(fake.Calls()))
+			}
+		})
+	}
+}
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/diegohordi/soawebservices"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	formatoCSV   = "csv"
+	formatoJSONL = "jsonl"
+)
+
+// colunasEntrada are the columns read from a CSV input. Only documento is
+// required.
+var colunasEntrada = []string{"tipo", "documento", "nascimento"}
+
+// colunasSaida are the columns written before the result fields.
+var colunasSaida = []string{"linha", "tipo", "documento", "nascimento", "status", "erro_classe", "erro_codigo", "erro_mensagem"}
+
+// camposResultado are the result columns of a CSV output: the union of the
+// fields of every lookup type, in order.
+var camposResultado = func() []string {
+	var campos []string
+	seen := map[string]bool{"documento": true}
+	for _, r := range []resultado{resultadoCEP(soawebservices.CEP{}), resultadoPessoaFisica(soawebservices.PessoaFisica{}), resultadoPessoaJuridica(soawebservices.PessoaJuridica{})} {
+		for _, c := range r.campos {
+			if !seen[c.chave] {
+				seen[c.chave] = true
+				campos = append(campos, c.chave)
+			}
+		}
+	}
+	return campos
+}()
+
+// saida is the output of a batch entry.
+type saida struct {
+	entrada
+	Status       string      `json:"status"`
+	ErroClasse   string      `json:"erro_classe,omitempty"`
+	ErroCodigo   string      `json:"erro_codigo,omitempty"`
+	ErroMensagem string      `json:"erro_mensagem,omitempty"`
+	Resultado    interface{} `json:"resultado,omitempty"`
+	campos       []campo
+}
+
+func formatoArquivo(path string) (string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return formatoCSV, nil
+	case ".jsonl", ".ndjson":
+		return formatoJSONL, nil
+	}
+	return "", fmt.Errorf("formato do arquivo %s desconhecido: use .csv ou .jsonl", path)
+}
+
+type leitor interface {
+	// ler returns the next entry, or io.EOF at the end of the input.
+	ler() (entrada, error)
+}
+
+func newLeitor(r io.Reader, formato string) (leitor, error) {
+	if formato == formatoJSONL {
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		return &leitorJSONL{scanner: scanner}, nil
+	}
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("não foi possível ler o cabeçalho do CSV: %w", err)
+	}
+	indices := make(map[string]int)
+	for i, coluna := range header {
+		indices[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(coluna, "\ufeff")))] = i
+	}
+	if _, ok := indices["documento"]; !ok {
+		return nil, fmt.Errorf("o CSV deve ter a coluna documento, e opcionalmente %s", strings.Join(colunasEntrada, ", "))
+	}
+	return &leitorCSV{reader: reader, indices: indices, linha: 1}, nil
+}
+
+type leitorCSV struct {
+	reader  *csv.Reader
+	indices map[string]int
+	linha   int
+}
+
+func (l *leitorCSV) coluna(record []string, nome string) string {
+	i, ok := l.indices[nome]
+	if !ok || i >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[i])
+}
+
+func (l *leitorCSV) ler() (entrada, error) {
+	record, err := l.reader.Read()
+	if err != nil {
+		return entrada{}, err
+	}
+	l.linha++
+	return entrada{
+		Linha:      l.linha,
+		Tipo:       l.coluna(record, "tipo"),
+		Documento:  l.coluna(record, "documento"),
+		Nascimento: l.coluna(record, "nascimento"),
+	}, nil
+}
+
+type leitorJSONL struct {
+	scanner *bufio.Scanner
+	linha   int
+}
+
+func (l *leitorJSONL) ler() (entrada, error) {
+	for l.scanner.Scan() {
+		l.linha++
+		line := strings.TrimSpace(l.scanner.Text())
+		if line == "" {
+			continue
+		}
+		e := entrada{}
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			return entrada{}, fmt.Errorf("linha %d inválida: %w", l.linha, err)
+		}
+		e.Linha = l.linha
+		return e, nil
+	}
+	if err := l.scanner.Err(); err != nil {
+		return entrada{}, err
+	}
+	return entrada{}, io.EOF
+}
+
+type escritor interface {
+	escrever(s saida) error
+}
+
+func newEscritor(w io.Writer, formato string, cabecalho bool) (escritor, error) {
+	if formato == formatoJSONL {
+		return &escritorJSONL{w: w}, nil
+	}
+	e := &escritorCSV{writer: csv.NewWriter(w)}
+	if cabecalho {
+		if err := e.writer.Write(append(append([]string{}, colunasSaida...), camposResultado...)); err != nil {
+			return nil, err
+		}
+		e.writer.Flush()
+		if err := e.writer.Error(); err != nil {
+			return nil, err
+		}
+	}
+	return e, nil
+}
+
+type escritorCSV struct {
+	writer *csv.Writer
+}
+
+func (e *escritorCSV) escrever(s saida) error {
+	valores := make(map[string]string, len(s.campos))
+	for _, c := range s.campos {
+		valores[c.chave] = c.valor
+	}
+	record := []string{strconv.Itoa(s.Linha), s.Tipo, s.Documento, s.Nascimento, s.Status, s.ErroClasse, s.ErroCodigo, s.ErroMensagem}
+	for _, campo := range camposResultado {
+		record = append(record, valores[campo])
+	}
+	if err := e.writer.Write(record); err != nil {
+		return err
+	}
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+type escritorJSONL struct {
+	w io.Writer
+}
+
+func (e *escritorJSONL) escrever(s saida) error {
+	return json.NewEncoder(e.w).Encode(s)
+}
+
+// checkpoint records the lines already processed, one per line, so an
+// interrupted batch can be resumed without looking them up again.
+type checkpoint struct {
+	file        *os.File
+	processadas map[int]bool
+}
+
+func openCheckpoint(path string) (*checkpoint, error) {
+	c := &checkpoint{processadas: make(map[int]bool)}
+	buf, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	for _, line := range strings.Split(string(buf), "\n") {
+		if linha, err := strconv.Atoi(strings.TrimSpace(line)); err == nil {
+			c.processadas[linha] = true
+		}
+	}
+	c.file, err = os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *checkpoint) processada(linha int) bool {
+	return c.processadas[linha]
+}
+
+// marcar records the given line as processed. It does not change the lines
+// returned by processada, which are the ones loaded when it was opened.
+func (c *checkpoint) marcar(linha int) error {
+	if _, err := fmt.Fprintln(c.file, linha); err != nil {
+		return err
+	}
+	return c.file.Sync()
+}
+
+func (c *checkpoint) Close() error {
+	return c.file.Close()
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/diegohordi/soawebservices"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	envEmail    = "SOAWS_EMAIL"
+	envSenha    = "SOAWS_SENHA"
+	envAmbiente = "SOAWS_AMBIENTE"
+	envConfig   = "SOAWS_CONFIG"
+)
+
+const (
+	defaultBaseURL = "https://soawebservices.com.br"
+	defaultTimeout = 30 * time.Second
+)
+
+type config struct {
+	Email    string                  `json:"email"`
+	Senha    string                  `json:"senha"`
+	Ambiente soawebservices.Ambiente `json:"ambiente"`
+	BaseURL  string                  `json:"base_url"`
+	Timeout  time.Duration           `json:"-"`
+	Formato  string                  `json:"-"`
+}
+
+// configFlags holds the flags shared by every subcommand.
+type configFlags struct {
+	fs         *flag.FlagSet
+	configFile string
+	config     config
+}
+
+func newConfigFlags(fs *flag.FlagSet) *configFlags {
+	c := &configFlags{fs: fs}
+	fs.StringVar(&c.configFile, "config", "", "arquivo de configuração JSON (padrão: $"+envConfig+" ou <config do usuário>/soaws/config.json)")
+	fs.StringVar(&c.config.Email, "email", "", "e-mail de acesso (padrão: $"+envEmail+")")
+	fs.StringVar(&c.config.Senha, "senha", "", "senha de acesso (padrão: $"+envSenha+")")
+	fs.StringVar((*string)(&c.config.Ambiente), "ambiente", "", "ambiente: producao ou test-drive (padrão: $"+envAmbiente+" ou producao)")
+	fs.StringVar(&c.config.BaseURL, "url", "", "URL base do serviço (padrão: "+defaultBaseURL+")")
+	fs.DurationVar(&c.config.Timeout, "timeout", defaultTimeout, "tempo máximo da consulta")
+	fs.StringVar(&c.config.Formato, "formato", formatoTabela, "formato da saída: tabela, json ou yaml")
+	return c
+}
+
+// resolve merges the configuration given by flags, environment variables and
+// configuration file, in this order of precedence.
+func (c *configFlags) resolve(getenv func(string) string) (config, error) {
+	resolved := c.config
+	set := make(map[string]bool)
+	c.fs.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+	if !set["email"] {
+		resolved.Email = getenv(envEmail)
+	}
+	if !set["senha"] {
+		resolved.Senha = getenv(envSenha)
+	}
+	if !set["ambiente"] {
+		resolved.Ambiente = soawebservices.Ambiente(getenv(envAmbiente))
+	}
+	file, err := c.loadFile(getenv)
+	if err != nil {
+		return config{}, err
+	}
+	if resolved.Email == "" {
+		resolved.Email = file.Email
+	}
+	if resolved.Senha == "" {
+		resolved.Senha = file.Senha
+	}
+	if resolved.Ambiente == "" {
+		resolved.Ambiente = file.Ambiente
+	}
+	if resolved.BaseURL == "" {
+		resolved.BaseURL = file.BaseURL
+	}
+	if resolved.Ambiente == "" {
+		resolved.Ambiente = soawebservices.Producao
+	}
+	if resolved.BaseURL == "" {
+		resolved.BaseURL = defaultBaseURL
+	}
+	if resolved.Ambiente != soawebservices.Producao && resolved.Ambiente != soawebservices.TestDrive {
+		return config{}, fmt.Errorf("ambiente inválido %q: use %s ou %s", resolved.Ambiente, soawebservices.Producao, soawebservices.TestDrive)
+	}
+	if resolved.Email == "" || resolved.Senha == "" {
+		return config{}, fmt.Errorf("credenciais não informadas: use -email e -senha, $%s e $%s ou o arquivo de configuração", envEmail, envSenha)
+	}
+	if _, ok := formatters[resolved.Formato]; !ok {
+		return config{}, fmt.Errorf("formato inválido %q: use tabela, json ou yaml", resolved.Formato)
+	}
+	return resolved, nil
+}
+
+func (c *configFlags) loadFile(getenv func(string) string) (config, error) {
+	path, required := c.configFile, true
+	if path == "" {
+		path = getenv(envConfig)
+	}
+	if path == "" {
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return config{}, nil
+		}
+		path, required = filepath.Join(dir, "soaws", "config.json"), false
+	}
+	buf, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) && !required {
+		return config{}, nil
+	}
+	if err != nil {
+		return config{}, fmt.Errorf("não foi possível ler o arquivo de configuração: %w", err)
+	}
+	file := config{}
+	if len(bytes.TrimSpace(buf)) == 0 {
+		return file, nil
+	}
+	if err = json.Unmarshal(buf, &file); err != nil {
+		return config{}, fmt.Errorf("arquivo de configuração %s inválido: %w", path, err)
+	}
+	return file, nil
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/diegohordi/soawebservices"
+	"github.com/diegohordi/soawebservices/internal/documento"
+	"strings"
+	"time"
+)
+
+const (
+	tipoCEP  = "cep"
+	tipoCPF  = "cpf"
+	tipoCNPJ = "cnpj"
+)
+
+// entrada is a document to be looked up.
+type entrada struct {
+	Linha      int    `json:"linha,omitempty"`
+	Tipo       string `json:"tipo"`
+	Documento  string `json:"documento"`
+	Nascimento string `json:"nascimento,omitempty"`
+}
+
+// tipo returns the type of the entry, inferred from the length of the document
+// when it is not given.
+func (e entrada) tipo() (string, error) {
+	if e.Tipo != "" {
+		tipo := strings.ToLower(strings.TrimSpace(e.Tipo))
+		if tipo != tipoCEP && tipo != tipoCPF && tipo != tipoCNPJ {
+			return "", fmt.Errorf("%w: tipo %q desconhecido, use cep, cpf ou cnpj", errEntradaInvalida, e.Tipo)
+		}
+		return tipo, nil
+	}
+	switch len(documento.SomenteDigitos(e.Documento)) {
+	case 8:
+		return tipoCEP, nil
+	case documento.TamanhoCPF:
+		return tipoCPF, nil
+	case documento.TamanhoCNPJ:
+		return tipoCNPJ, nil
+	}
+	return "", fmt.Errorf("%w: não foi possível identificar o tipo do documento %q", errEntradaInvalida, e.Documento)
+}
+
+func (e entrada) dataNascimento() (time.Time, error) {
+	dataNascimento, err := time.Parse("02/01/2006", strings.TrimSpace(e.Nascimento))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: data de nascimento %q fora do formato dd/mm/aaaa", errEntradaInvalida, e.Nascimento)
+	}
+	return dataNascimento, nil
+}
+
+// consultar looks up the given entry, according to its type.
+func consultar(ctx context.Context, client soawebservices.Client, e entrada) (resultado, error) {
+	tipo, err := e.tipo()
+	if err != nil {
+		return resultado{}, err
+	}
+	switch tipo {
+	case tipoCEP:
+		result, err := client.ConsultarCEP(ctx, e.Documento)
+		if err != nil {
+			return resultado{}, err
+		}
+		if result == (soawebservices.CEP{}) {
+			return resultado{}, fmt.Errorf("cep %s: %w", e.Documento, errNaoEncontrado)
+		}
+		return resultadoCEP(result), nil
+	case tipoCPF:
+		dataNascimento, err := e.dataNascimento()
+		if err != nil {
+			return resultado{}, err
+		}
+		result, err := client.ConsultarCPF(ctx, e.Documento, dataNascimento)
+		if err != nil {
+			return resultado{}, err
+		}
+		return resultadoPessoaFisica(result), nil
+	default:
+		result, err := client.ConsultarCNPJ(ctx, e.Documento)
+		if err != nil {
+			return resultado{}, err
+		}
+		return resultadoPessoaJuridica(result), nil
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"github.com/diegohordi/soawebservices"
+	"net"
+)
+
+const (
+	exitSucesso = iota
+	exitErro
+	exitUso
+	exitCredenciais
+	exitDadosInvalidos
+	exitIndisponivel
+	exitNaoEncontrado
+	exitSaldoInsuficiente
+)
+
+const (
+	classeErro           = "erro"
+	classeCredenciais    = "credenciais"
+	classeDadosInvalidos = "dados_invalidos"
+	classeIndisponivel   = "indisponivel"
+	classeNaoEncontrado  = "nao_encontrado"
+	classeSaldo          = "saldo_insuficiente"
+)
+
+const (
+	errNaoEncontrado   = soawebservices.Error("nenhum resultado encontrado")
+	errEntradaInvalida = soawebservices.Error("entrada inválida")
+)
+
+var exitCodes = map[string]int{
+	classeErro:           exitErro,
+	classeCredenciais:    exitCredenciais,
+	classeDadosInvalidos: exitDadosInvalidos,
+	classeIndisponivel:   exitIndisponivel,
+	classeNaoEncontrado:  exitNaoEncontrado,
+	classeSaldo:          exitSaldoInsuficiente,
+}
+
+// classesCategoria maps the categories of the CodigoStatus to their classes.
+var classesCategoria = map[soawebservices.Categoria]string{
+	soawebservices.CategoriaEntrada:         classeDadosInvalidos,
+	soawebservices.CategoriaAutenticacao:    classeCredenciais,
+	soawebservices.CategoriaDisponibilidade: classeIndisponivel,
+	soawebservices.CategoriaCobranca:        classeSaldo,
+	soawebservices.CategoriaNaoEncontrado:   classeNaoEncontrado,
+}
+
+// codigosStatus maps the errors not returned by the SOA WebServices, such as
+// the ones of the local validations, to the CodigoStatus of the same failure.
+var codigosStatus = []struct {
+	err    error
+	codigo string
+}{
+	{soawebservices.ErrCredenciaisInvalidas, soawebservices.StatusCredenciaisInvalidas},
+	{soawebservices.ErrCPFInvalido, soawebservices.StatusDocumentoInvalido},
+	{soawebservices.ErrCNPJInvalido, soawebservices.StatusDocumentoInvalido},
+	{soawebservices.ErrDataNascimentoInvalida, soawebservices.StatusDataNascimentoInvalida},
+	{soawebservices.ErrDataNascimentoObrigatoria, soawebservices.StatusDataNascimentoObrigatoria},
+	{soawebservices.ErrCEPInvalido, soawebservices.StatusCEPInvalido},
+	{soawebservices.ErrCEPFalhaTransacao, soawebservices.StatusCEPFalhaProcessamento},
+	{soawebservices.ErrCEPServicoIndisponivel, soawebservices.StatusCEPServicoIndisponivel},
+	{soawebservices.ErrSaldoInsuficiente, soawebservices.StatusSemCreditos},
+	{errNaoEncontrado, soawebservices.StatusCEPNaoEncontrado},
+}
+
+// classe returns the class of the given error.
+func classe(err error) string {
+	var statusErr *soawebservices.StatusError
+	var netErr net.Error
+	switch {
+	case errors.Is(err, errNaoEncontrado):
+		return classeNaoEncontrado
+	case errors.As(err, &statusErr):
+		if c, ok := classesCategoria[statusErr.Categoria]; ok {
+			return c
+		}
+		return classeErro
+	case errors.Is(err, soawebservices.ErrCredenciaisInvalidas):
+		return classeCredenciais
+	case errors.Is(err, soawebservices.ErrSaldoInsuficiente):
+		return classeSaldo
+	case errors.Is(err, errEntradaInvalida),
+		errors.Is(err, soawebservices.ErrCPFInvalido),
+		errors.Is(err, soawebservices.ErrCNPJInvalido),
+		errors.Is(err, soawebservices.ErrCEPInvalido),
+		errors.Is(err, soawebservices.ErrDataNascimentoInvalida),
+		errors.Is(err, soawebservices.ErrDataNascimentoObrigatoria):
+		return classeDadosInvalidos
+	case errors.Is(err, soawebservices.ErrCEPServicoIndisponivel),
+		errors.Is(err, soawebservices.ErrCEPFalhaTransacao),
+		errors.Is(err, context.DeadlineExceeded),
+		errors.As(err, &netErr):
+		return classeIndisponivel
+	}
+	return classeErro
+}
+
+// codigoStatus returns the SOA WebServices CodigoStatus behind the given error,
+// if any.
+func codigoStatus(err error) string {
+	var statusErr *soawebservices.StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.Codigo
+	}
+	for _, c := range codigosStatus {
+		if errors.Is(err, c.err) {
+			return c.codigo
+		}
+	}
+	return ""
+}
+
+// exitCode maps the given error to the exit code of its class.
+func exitCode(err error) int {
+	if err == nil {
+		return exitSucesso
+	}
+	return exitCodes[classe(err)]
+}
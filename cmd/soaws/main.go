@@ -0,0 +1,184 @@
+// Command soaws looks up CEP, CPF and CNPJ data through the SOA WebServices
+// client.
+//
+// Usage:
+//
+//	soaws cep [flags] <cep>
+//	soaws cpf [flags] -nascimento <dd/mm/aaaa> <cpf>
+//	soaws cnpj [flags] <cnpj>
+//	soaws batch [flags] -entrada <arquivo> -saida <arquivo>
+//
+// Credentials are read from the -email and -senha flags, the SOAWS_EMAIL and
+// SOAWS_SENHA environment variables or a JSON configuration file, in this
+// order of precedence.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/diegohordi/soawebservices"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+const usage = `uso: soaws <comando> [flags] <documento>
+
+comandos:
+  cep    consulta um CEP
+  cpf    consulta um CPF (requer -nascimento)
+  cnpj   consulta um CNPJ
+  batch  consulta os documentos de um arquivo CSV ou JSONL
+
+Use "soaws <comando> -h" para ver as flags de cada comando.
+`
+
+type clientFactory func(cfg config) (soawebservices.Client, error)
+
+type app struct {
+	stdout    io.Writer
+	stderr    io.Writer
+	getenv    func(string) string
+	newClient clientFactory
+}
+
+func newClient(cfg config) (soawebservices.Client, error) {
+	httpClient := &http.Client{Timeout: cfg.Timeout}
+	credenciais := soawebservices.Credenciais{Email: cfg.Email, Senha: cfg.Senha}
+	return soawebservices.NewClient(httpClient, cfg.BaseURL, cfg.Ambiente, credenciais)
+}
+
+func main() {
+	a := &app{stdout: os.Stdout, stderr: os.Stderr, getenv: os.Getenv, newClient: newClient}
+	os.Exit(a.run(os.Args[1:]))
+}
+
+func (a *app) run(args []string) int {
+	if len(args) == 0 {
+		_, _ = fmt.Fprint(a.stderr, usage)
+		return exitUso
+	}
+	var cmd func(args []string) error
+	switch args[0] {
+	case "cep":
+		cmd = a.cep
+	case "cpf":
+		cmd = a.cpf
+	case "cnpj":
+		cmd = a.cnpj
+	case "batch":
+		cmd = a.batch
+	case "-h", "-help", "--help", "help":
+		_, _ = fmt.Fprint(a.stdout, usage)
+		return exitSucesso
+	default:
+		_, _ = fmt.Fprintf(a.stderr, "comando desconhecido %q\n\n%s", args[0], usage)
+		return exitUso
+	}
+	err := cmd(args[1:])
+	var usageErr usageError
+	switch {
+	case errors.Is(err, flag.ErrHelp):
+		return exitSucesso
+	case errors.As(err, &usageErr):
+		_, _ = fmt.Fprintf(a.stderr, "soaws %s: %s\n", args[0], err)
+		return exitUso
+	case err != nil:
+		_, _ = fmt.Fprintf(a.stderr, "soaws %s: %s\n", args[0], err)
+	}
+	return exitCode(err)
+}
+
+type usageError struct {
+	error
+}
+
+// parse parses the flags of a subcommand, accepting them both before and after
+// the positional argument, which is returned.
+func (a *app) parse(fs *flag.FlagSet, args []string) (string, error) {
+	fs.SetOutput(a.stderr)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return "", err
+		}
+		return "", usageError{err}
+	}
+	if fs.NArg() == 0 {
+		return "", usageError{errors.New("documento não informado")}
+	}
+	documento := fs.Arg(0)
+	if err := fs.Parse(fs.Args()[1:]); err != nil {
+		return "", usageError{err}
+	}
+	if fs.NArg() > 0 {
+		return "", usageError{fmt.Errorf("argumentos inesperados: %s", strings.Join(fs.Args(), " "))}
+	}
+	return documento, nil
+}
+
+// lookup resolves the configuration, runs the given lookup with the configured
+// timeout and prints its result.
+func (a *app) lookup(flags *configFlags, fn func(ctx context.Context, client soawebservices.Client) (resultado, error)) error {
+	cfg, err := flags.resolve(a.getenv)
+	if err != nil {
+		return usageError{err}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Timeout)
+	defer cancel()
+	client, err := a.newClient(cfg)
+	if err != nil {
+		return usageError{err}
+	}
+	r, err := fn(ctx, client)
+	if err != nil {
+		return err
+	}
+	return formatters[cfg.Formato](a.stdout, r)
+}
+
+func (a *app) cep(args []string) error {
+	fs := flag.NewFlagSet("cep", flag.ContinueOnError)
+	flags := newConfigFlags(fs)
+	cep, err := a.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	return a.lookup(flags, func(ctx context.Context, client soawebservices.Client) (resultado, error) {
+		return consultar(ctx, client, entrada{Tipo: tipoCEP, Documento: cep})
+	})
+}
+
+func (a *app) cpf(args []string) error {
+	fs := flag.NewFlagSet("cpf", flag.ContinueOnError)
+	flags := newConfigFlags(fs)
+	nascimento := fs.String("nascimento", "", "data de nascimento no formato dd/mm/aaaa")
+	cpf, err := a.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if *nascimento == "" {
+		return usageError{errors.New("data de nascimento não informada: use -nascimento dd/mm/aaaa")}
+	}
+	e := entrada{Tipo: tipoCPF, Documento: cpf, Nascimento: *nascimento}
+	if _, err = e.dataNascimento(); err != nil {
+		return usageError{err}
+	}
+	return a.lookup(flags, func(ctx context.Context, client soawebservices.Client) (resultado, error) {
+		return consultar(ctx, client, e)
+	})
+}
+
+func (a *app) cnpj(args []string) error {
+	fs := flag.NewFlagSet("cnpj", flag.ContinueOnError)
+	flags := newConfigFlags(fs)
+	cnpj, err := a.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	return a.lookup(flags, func(ctx context.Context, client soawebservices.Client) (resultado, error) {
+		return consultar(ctx, client, entrada{Tipo: tipoCNPJ, Documento: cnpj})
+	})
+}
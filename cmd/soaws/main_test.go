@@ -0,0 +1,182 @@
+package main
+
+import (
+	"bytes"
+	"github.com/diegohordi/soawebservices"
+	"github.com/diegohordi/soawebservices/soawebservicestest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestApp(fake *soawebservicestest.FakeClient, env map[string]string) (*app, *bytes.Buffer, *bytes.Buffer, *config) {
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	used := &config{}
+	if env == nil {
+		env = map[string]string{envEmail: "test@test.com", envSenha: "test", envConfig: os.DevNull}
+	}
+	return &app{
+		stdout: stdout,
+		stderr: stderr,
+		getenv: func(key string) string { return env[key] },
+		newClient: func(cfg config) (soawebservices.Client, error) {
+			*used = cfg
+			return fake, nil
+		},
+	}, stdout, stderr, used
+}
+
+func Test_app_run(t *testing.T) {
+	fake := soawebservicestest.NewFakeClient()
+	fake.OnCEP("01001000").Return(soawebservices.CEP{CEP: "01001000", UF: "SP", Cidade: "SAO PAULO"})
+	fake.OnCEP("99999999").Return(soawebservices.CEP{})
+	fake.OnCPF("52998224725").Return(soawebservices.PessoaFisica{
+		Documento:      "52998224725",
+		Nome:           "JOSE DA SILVA",
+		DataNascimento: time.Date(1990, 1, 2, 0, 0, 0, 0, time.UTC),
+		Status:         soawebservices.Regular,
+	})
+	fake.OnCPF("11111111111").ReturnErr(soawebservices.ErrCPFInvalido)
+	fake.OnCNPJ("11222333000181").ReturnErr(soawebservices.ErrCredenciaisInvalidas)
+	fake.OnCNPJ("39621470000109").ReturnErr(soawebservices.ErrCEPServicoIndisponivel)
+	fake.OnCNPJ("11444777000161").ReturnErr(soawebservices.NewStatusError(soawebservices.StatusVeiculoNaoEncontrado, "Veiculo nao foi encontrado"))
+	fake.OnCNPJ("12345678000195").ReturnErr(soawebservices.NewStatusError(soawebservices.StatusSemCreditos, "Usuario sem creditos"))
+	fake.OnCNPJ("04252011000110").ReturnErr(soawebservices.NewStatusError("G999M999", "Erro desconhecido"))
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantCode   int
+		wantStdout string
+	}{
+		{
+			name:       "should print a CEP as a table",
+			args:       []string{"cep", "01001-000"},
+			wantCode:   exitSucesso,
+			wantStdout: "SAO PAULO\n",
+		},
+		{
+			name:       "should print a CEP as YAML",
+			args:       []string{"cep", "-formato", "yaml", "01001-000"},
+			wantCode:   exitSucesso,
+			wantStdout: "cidade: \"SAO PAULO\"\n",
+		},
+		{
+			name:     "should fail due to a CEP not found",
+			args:     []string{"cep", "99999-999"},
+			wantCode: exitNaoEncontrado,
+		},
+		{
+			name:       "should print a CPF as JSON, with flags after the document",
+			args:       []string{"cpf", "529.982.247-25", "-nascimento", "02/01/1990", "-formato", "json"},
+			wantCode:   exitSucesso,
+			wantStdout: `"Nome": "JOSE DA SILVA"`,
+		},
+		{
+			name:     "should fail due to a missing data de nascimento",
+			args:     []string{"cpf", "529.982.247-25"},
+			wantCode: exitUso,
+		},
+		{
+			name:     "should fail due to an invalid CPF",
+			args:     []string{"cpf", "-nascimento", "02/01/1990", "111.111.111-11"},
+			wantCode: exitDadosInvalidos,
+		},
+		{
+			name:     "should fail due to the wrong credentials",
+			args:     []string{"cnpj", "11.222.333/0001-81"},
+			wantCode: exitCredenciais,
+		},
+		{
+			name:     "should fail due to service unavailability",
+			args:     []string{"cnpj", "39.621.470/0001-09"},
+			wantCode: exitIndisponivel,
+		},
+		{
+			name:     "should fail by the category of the CodigoStatus",
+			args:     []string{"cnpj", "11.444.777/0001-61"},
+			wantCode: exitNaoEncontrado,
+		},
+		{
+			name:     "should fail due to the lack of credits",
+			args:     []string{"cnpj", "12.345.678/0001-95"},
+			wantCode: exitSaldoInsuficiente,
+		},
+		{
+			name:     "should fail due to an unknown CodigoStatus",
+			args:     []string{"cnpj", "04.252.011/0001-10"},
+			wantCode: exitErro,
+		},
+		{
+			name:     "should fail due to an unknown ambiente",
+			args:     []string{"cnpj", "-ambiente", "homologacao", "11.222.333/0001-81"},
+			wantCode: exitUso,
+		},
+		{
+			name:     "should fail due to an unknown command",
+			args:     []string{"placa", "ABC1234"},
+			wantCode: exitUso,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			a, stdout, stderr, _ := newTestApp(fake, nil)
+			if got := a.run(tt.args); got != tt.wantCode {
+				t.Fatalf("run() = %d, want %d (stderr: %s)", got, tt.wantCode, stderr)
+			}
+			if !strings.Contains(stdout.String(), tt.wantStdout) {
+				t.Errorf("expected the output to contain %q, got %q", tt.wantStdout, stdout)
+			}
+		})
+	}
+}
+
+func Test_configFlags_resolve(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.json")
+	content := `{"email": "file@test.com", "senha": "file", "ambiente": "test-drive", "base_url": "http://localhost:8080"}`
+	if err := os.WriteFile(configFile, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		args []string
+		env  map[string]string
+		want config
+	}{
+		{
+			name: "should read the configuration file",
+			args: []string{"cep", "-config", configFile, "01001000"},
+			env:  map[string]string{},
+			want: config{Email: "file@test.com", Senha: "file", Ambiente: soawebservices.TestDrive, BaseURL: "http://localhost:8080"},
+		},
+		{
+			name: "should prefer the environment variables over the configuration file",
+			args: []string{"cep", "01001000"},
+			env:  map[string]string{envConfig: configFile, envEmail: "env@test.com", envSenha: "env", envAmbiente: "producao"},
+			want: config{Email: "env@test.com", Senha: "env", Ambiente: soawebservices.Producao, BaseURL: "http://localhost:8080"},
+		},
+		{
+			name: "should prefer the flags over the environment variables",
+			args: []string{"cep", "-email", "flag@test.com", "-senha", "flag", "01001000"},
+			env:  map[string]string{envConfig: configFile, envEmail: "env@test.com", envSenha: "env"},
+			want: config{Email: "flag@test.com", Senha: "flag", Ambiente: soawebservices.TestDrive, BaseURL: "http://localhost:8080"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			a, _, stderr, used := newTestApp(soawebservicestest.NewFakeClient(), tt.env)
+			if code := a.run(tt.args); code != exitSucesso {
+				t.Fatalf("run() = %d (stderr: %s)", code, stderr)
+			}
+			got := *used
+			got.Timeout, got.Formato = 0, ""
+			if got != tt.want {
+				t.Errorf("want %+v but got %+v", tt.want, got)
+			}
+		})
+	}
+}
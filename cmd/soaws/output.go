@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/diegohordi/soawebservices"
+	"io"
+	"text/tabwriter"
+	"time"
+)
+
+const (
+	formatoTabela = "tabela"
+	formatoJSON   = "json"
+	formatoYAML   = "yaml"
+)
+
+type campo struct {
+	chave string
+	valor string
+}
+
+// resultado is the output of a lookup: the value printed as JSON and the same
+// value flattened as key/value pairs for the other formats.
+type resultado struct {
+	valor  interface{}
+	campos []campo
+}
+
+type formatter func(w io.Writer, r resultado) error
+
+var formatters = map[string]formatter{
+	formatoTabela: formatTabela,
+	formatoJSON:   formatJSON,
+	formatoYAML:   formatYAML,
+}
+
+func formatTabela(w io.Writer, r resultado) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, c := range r.campos {
+		if _, err := fmt.Fprintf(tw, "%s\t%s\n", c.chave, c.valor); err != nil {
+			return err
+		}
+	}
+	return tw.Flush()
+}
+
+func formatJSON(w io.Writer, r resultado) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r.valor)
+}
+
+func formatYAML(w io.Writer, r resultado) error {
+	for _, c := range r.campos {
+		if _, err := fmt.Fprintf(w, "%s: %q\n", c.chave, c.valor); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func formatData(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("02/01/2006")
+}
+
+func formatBool(b bool) string {
+	if b {
+		return "sim"
+	}
+	return "não"
+}
+
+func resultadoCEP(cep soawebservices.CEP) resultado {
+	return resultado{
+		valor: cep,
+		campos: []campo{
+			{"cep", cep.CEP},
+			{"uf", cep.UF},
+			{"cidade", cep.Cidade},
+			{"bairro", cep.Bairro},
+			{"tipo_logradouro", cep.TipoLogradouro},
+			{"logradouro", cep.LogradouroCompleto},
+			{"complemento", cep.LogradouroComplemento},
+			{"codigo_ibge", cep.CodigoIBGE},
+		},
+	}
+}
+
+func resultadoPessoaFisica(pf soawebservices.PessoaFisica) resultado {
+	return resultado{
+		valor: pf,
+		campos: []campo{
+			{"documento", pf.Documento},
+			{"nome", pf.Nome},
+			{"nome_social", pf.NomeSocial},
+			{"data_nascimento", formatData(pf.DataNascimento)},
+			{"situacao_cadastral", pf.Status.String()},
+		},
+	}
+}
+
+func resultadoPessoaJuridica(pj soawebservices.PessoaJuridica) resultado {
+	return resultado{
+		valor: pj,
+		campos: []campo{
+			{"documento", pj.Documento},
+			{"razao_social", pj.RazaoSocial},
+			{"nome_fantasia", pj.NomeFantasia},
+			{"data_fundacao", formatData(pj.DataFundacao)},
+			{"matriz", formatBool(pj.Matriz)},
+			{"cnae", pj.CNAE.Codigo},
+			{"cnae_descricao", pj.CNAE.Descricao},
+			{"natureza_juridica", pj.NaturezaJuridica.Codigo},
+			{"natureza_juridica_descricao", pj.NaturezaJuridica.Descricao},
+			{"email", pj.Email},
+			{"telefone", pj.Telefone},
+		},
+	}
+}
@@ -0,0 +1,102 @@
+package soawebservices
+
+import (
+	"context"
+	"fmt"
+)
+
+const (
+	urlPEP     = "compliance/pep.ashx"
+	urlSancoes = "compliance/sancoes.ashx"
+)
+
+// parsePeriodo reads the period of the i-th record of a response.
+func parsePeriodo(datas *leitorDatas, i int, inicio, fim Data) Periodo {
+	return Periodo{
+		Inicio: datas.ler(fmt.Sprintf("Registros[%d].DataInicial", i), inicio),
+		Fim:    datas.ler(fmt.Sprintf("Registros[%d].DataFinal", i), fim),
+	}
+}
+
+func parsePEPResult(result *pepResult, datas *leitorDatas) (PEP, error) {
+	if err := parseDocumentoTransacao(result.Status, result.Transacao, ErrCPFInvalido); err != nil {
+		return PEP{}, err
+	}
+	pep := PEP{Documento: result.Documento, Nome: result.Nome}
+	for i, r := range result.Registros {
+		pep.Registros = append(pep.Registros, RegistroPEP{
+			Periodo: parsePeriodo(datas, i, r.DataInicial, r.DataFinal),
+			Cargo:   r.Cargo,
+			Orgao:   r.Orgao,
+			Fonte:   r.Fonte,
+		})
+	}
+	if datas.err != nil {
+		return PEP{}, datas.err
+	}
+	pep.Avisos = datas.avisos
+	return pep, nil
+}
+
+func parseSancoesResult(result *sancoesResult, errDocumento error, datas *leitorDatas) (Sancoes, error) {
+	if err := parseDocumentoTransacao(result.Status, result.Transacao, errDocumento); err != nil {
+		return Sancoes{}, err
+	}
+	sancoes := Sancoes{Documento: result.Documento}
+	for i, r := range result.Registros {
+		sancoes.Registros = append(sancoes.Registros, Sancao{
+			Periodo:       parsePeriodo(datas, i, r.DataInicial, r.DataFinal),
+			Lista:         ListaSancoes(r.Lista),
+			Tipo:          r.Tipo,
+			Orgao:         r.Orgao,
+			Fundamentacao: r.Fundamentacao,
+			Processo:      r.Processo,
+		})
+	}
+	if datas.err != nil {
+		return Sancoes{}, datas.err
+	}
+	sancoes.Avisos = datas.avisos
+	return sancoes, nil
+}
+
+// ConsultarPEP returns the roles that make the given CPF a Politically Exposed
+// Person. A CPF that was never exposed is not an error, but a PEP without
+// Registros.
+func (d *defaultClient) ConsultarPEP(ctx context.Context, cpf string) (PEP, error) {
+	credenciais, err := d.credenciais(ctx)
+	if err != nil {
+		return PEP{}, err
+	}
+	custo, err := d.usage.reservar(ServicoPEP)
+	if err != nil {
+		return PEP{}, err
+	}
+	pep := PEP{}
+	result, err := d.consultarDocumento(ctx, credenciais, urlPEP, cpf, func() interface{} { return &pepResult{} })
+	if err == nil {
+		pep, err = parsePEPResult(result.(*pepResult), &leitorDatas{estrito: d.datasEstritas})
+	}
+	d.usage.registrar(ctx, ServicoPEP, custo, err)
+	return pep, err
+}
+
+// ConsultarSancoes returns the sanctions of the given CPF or CNPJ in the CEIS
+// and CNEP lists, in force or not.
+func (d *defaultClient) ConsultarSancoes(ctx context.Context, doc string) (Sancoes, error) {
+	credenciais, err := d.credenciais(ctx)
+	if err != nil {
+		return Sancoes{}, err
+	}
+	custo, err := d.usage.reservar(ServicoSancoes)
+	if err != nil {
+		return Sancoes{}, err
+	}
+	sancoes := Sancoes{}
+	result, err := d.consultarDocumento(ctx, credenciais, urlSancoes, doc, func() interface{} { return &sancoesResult{} })
+	if err == nil {
+		sancoes, err = parseSancoesResult(result.(*sancoesResult), errDocumentoInvalido(doc), &leitorDatas{estrito: d.datasEstritas})
+	}
+	d.usage.registrar(ctx, ServicoSancoes, custo, err)
+	return sancoes, err
+}
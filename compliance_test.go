@@ -0,0 +1,142 @@
+package soawebservices_test
+
+import (
+	"context"
+	"errors"
+	"github.com/diegohordi/soawebservices"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_defaultClient_ConsultarPEP(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		want    soawebservices.PEP
+		wantErr error
+	}{
+		{
+			name: "should return the roles of the person",
+			file: "consultapep_success.json",
+			want: soawebservices.PEP{
+				Documento: "52998224725",
+				Nome:      "JOSE DA SILVA",
+				Registros: []soawebservices.RegistroPEP{{
+					Periodo: soawebservices.Periodo{
+						Inicio: time.Date(2013, 1, 1, 0, 0, 0, 0, soawebservices.SaoPaulo),
+						Fim:    time.Date(2016, 12, 31, 0, 0, 0, 0, soawebservices.SaoPaulo),
+					},
+					Cargo: "SECRETARIO MUNICIPAL",
+					Orgao: "PREFEITURA MUNICIPAL DE TESTES",
+					Fonte: "CGU - PEP",
+				}},
+			},
+		},
+		{
+			name:    "should fail due to the given invalid CPF",
+			file:    "consultacpf_invalid_cpf.json",
+			wantErr: soawebservices.ErrCPFInvalido,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			client := MustCreateClient(fixtureClient(t, tt.file))
+			result, err := client.ConsultarPEP(context.TODO(), "529.982.247-25")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if !reflect.DeepEqual(result, tt.want) {
+				t.Error("want ", tt.want, " but got ", result)
+			}
+		})
+	}
+}
+
+func TestPEP_Exposta(t *testing.T) {
+	pep := soawebservices.PEP{Registros: []soawebservices.RegistroPEP{{
+		Periodo: soawebservices.Periodo{
+			Inicio: time.Date(2013, 1, 1, 0, 0, 0, 0, time.UTC),
+			Fim:    time.Date(2016, 12, 31, 0, 0, 0, 0, time.UTC),
+		},
+	}}}
+	tests := []struct {
+		data time.Time
+		want bool
+	}{
+		{data: time.Date(2012, 12, 31, 0, 0, 0, 0, time.UTC), want: false},
+		{data: time.Date(2015, 6, 1, 0, 0, 0, 0, time.UTC), want: true},
+		{data: time.Date(2021, 12, 31, 0, 0, 0, 0, time.UTC), want: true},
+		{data: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC), want: false},
+	}
+	for _, tt := range tests {
+		if got := pep.Exposta(tt.data); got != tt.want {
+			t.Errorf("Exposta(%s) got = %v, want %v", tt.data.Format("02/01/2006"), got, tt.want)
+		}
+	}
+}
+
+func TestPeriodo_Vigente(t *testing.T) {
+	periodo := soawebservices.Periodo{
+		Inicio: time.Date(2019, 6, 15, 0, 0, 0, 0, soawebservices.SaoPaulo),
+		Fim:    time.Date(2021, 6, 14, 0, 0, 0, 0, soawebservices.SaoPaulo),
+	}
+	tests := []struct {
+		name string
+		data time.Time
+		want bool
+	}{
+		{name: "should not include the day before Inicio", data: time.Date(2019, 6, 14, 23, 59, 0, 0, soawebservices.SaoPaulo), want: false},
+		{name: "should include the start of Inicio", data: time.Date(2019, 6, 15, 0, 0, 0, 0, soawebservices.SaoPaulo), want: true},
+		{name: "should include the whole day of Fim", data: time.Date(2021, 6, 14, 15, 0, 0, 0, soawebservices.SaoPaulo), want: true},
+		{name: "should include the day of Fim in UTC", data: time.Date(2021, 6, 15, 2, 59, 0, 0, time.UTC), want: true},
+		{name: "should not include the day after Fim", data: time.Date(2021, 6, 15, 0, 0, 0, 0, soawebservices.SaoPaulo), want: false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := periodo.Vigente(tt.data); got != tt.want {
+				t.Errorf("Vigente(%s) got = %v, want %v", tt.data, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_defaultClient_ConsultarSancoes(t *testing.T) {
+	client := MustCreateClient(fixtureClient(t, "consultasancoes_success.json"))
+	result, err := client.ConsultarSancoes(context.TODO(), "99.999.999/9999-62")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Registros) != 2 || result.Registros[0].Lista != soawebservices.ListaCEIS || result.Registros[1].Lista != soawebservices.ListaCNEP {
+		t.Fatalf("unexpected sanctions %+v", result)
+	}
+	vigentes := result.Vigentes(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC))
+	if len(vigentes) != 1 || vigentes[0].Processo != "00190.000002/2021-02" {
+		t.Errorf("unexpected sanctions in force %+v", vigentes)
+	}
+	if _, err = MustCreateClient(fixtureClient(t, "consultacnpj_invalid_cnpj.json")).ConsultarSancoes(context.TODO(), "39621470000109"); !errors.Is(err, soawebservices.ErrCNPJInvalido) {
+		t.Errorf("expected error %v, got %v", soawebservices.ErrCNPJInvalido, err)
+	}
+}
+
+func Test_defaultClient_ConsultarSancoes_datas(t *testing.T) {
+	result, err := MustCreateClient(fixtureClient(t, "consultasancoes_invalid_datas.json")).ConsultarSancoes(context.TODO(), "99.999.999/9999-62")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{`Registros[0].DataFinal: data inválida: "31/06/2021"`}; !reflect.DeepEqual(result.Avisos, want) {
+		t.Errorf("Avisos got = %q, want %q", result.Avisos, want)
+	}
+	credenciais := soawebservices.Credenciais{Email: "test@test.com", Senha: "test"}
+	client, err := soawebservices.NewClient(fixtureClient(t, "consultasancoes_invalid_datas.json"), "https://soawebservices.com.br", soawebservices.TestDrive, credenciais, soawebservices.WithDatasEstritas())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.ConsultarSancoes(context.TODO(), "99.999.999/9999-62"); !errors.Is(err, soawebservices.ErrDataInvalida) {
+		t.Errorf("expected error %v, got %v", soawebservices.ErrDataInvalida, err)
+	}
+}
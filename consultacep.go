@@ -19,15 +19,8 @@ const (
 	ErrCEPServicoIndisponivel = Error("serviço indisponível no momento (P016M010)")
 )
 
-const (
-	StatusCEPInvalido            = "P016M001"
-	StatusCEPFalhaProcessamento  = "P016M009"
-	StatusCEPServicoIndisponivel = "P016M010"
-	StatusCEPNaoEncontrado       = "P016M002"
-)
-
-func (d *defaultClient) buildConsultaCEPRequestBody(cep string) (io.Reader, error) {
-	consultaCep := newConsultaCEPEstendida(d.credenciais, cep)
+func (d *defaultClient) buildConsultaCEPRequestBody(credenciais Credenciais, cep string) (io.Reader, error) {
+	consultaCep := newConsultaCEPEstendida(credenciais, cep)
 	buf, err := xml.Marshal(newRequestEnvelope(consultaCep))
 	if err != nil {
 		return nil, fmt.Errorf("an error occurred while build the request body: %w", err)
@@ -35,6 +28,18 @@ func (d *defaultClient) buildConsultaCEPRequestBody(cep string) (io.Reader, erro
 	return bytes.NewBuffer(buf), err
 }
 
+// parseCEPTransacao maps the status of the CEP operations to their errors,
+// reporting whether any address was found.
+func parseCEPTransacao(status bool, t transacao) (bool, error) {
+	if t.CodigoStatus == StatusCEPNaoEncontrado {
+		return false, nil
+	}
+	if err := parseTransacao(status, t); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 func (d *defaultClient) parseConsultaCEPResponseBody(resp *http.Response) (CEP, error) {
 	defer func(Body io.ReadCloser) {
 		_ = Body.Close()
@@ -53,23 +58,9 @@ func (d *defaultClient) parseConsultaCEPResponseBody(resp *http.Response) (CEP,
 		return CEP{}, err
 	}
 	result := respBody.Body.Response.Result
-	if result.Transacao.CodigoStatus == StatusCEPInvalido {
-		return CEP{}, ErrCEPInvalido
-	}
-	if result.Transacao.CodigoStatus == StatusCEPFalhaProcessamento {
-		return CEP{}, ErrCEPFalhaTransacao
-	}
-	if result.Transacao.CodigoStatus == StatusCEPServicoIndisponivel {
-		return CEP{}, ErrCEPServicoIndisponivel
-	}
-	if result.Transacao.CodigoStatus == StatusCredenciaisInvalidas {
-		return CEP{}, ErrCredenciaisInvalidas
-	}
-	if result.Transacao.CodigoStatus == StatusCEPNaoEncontrado {
-		return CEP{}, nil
-	}
-	if !result.Status {
-		return CEP{}, fmt.Errorf("%s: %s", result.Transacao.CodigoStatus, result.Transacao.CodigoStatusDescricao)
+	encontrado, err := parseCEPTransacao(result.Status, result.Transacao)
+	if err != nil || !encontrado {
+		return CEP{}, err
 	}
 	return CEP{
 		CEP:                   result.Cep,
@@ -84,9 +75,23 @@ func (d *defaultClient) parseConsultaCEPResponseBody(resp *http.Response) (CEP,
 }
 
 func (d *defaultClient) ConsultarCEP(ctx context.Context, cep string) (CEP, error) {
+	credenciais, err := d.credenciais(ctx)
+	if err != nil {
+		return CEP{}, err
+	}
+	custo, err := d.usage.reservar(ServicoCEP)
+	if err != nil {
+		return CEP{}, err
+	}
+	result, err := d.consultarCEP(ctx, credenciais, cep)
+	d.usage.registrar(ctx, ServicoCEP, custo, err)
+	return result, err
+}
+
+func (d *defaultClient) consultarCEP(ctx context.Context, credenciais Credenciais, cep string) (CEP, error) {
 	errChan := make(chan error, 1)
 	resultChan := make(chan CEP, 1)
-	requestBody, err := d.buildConsultaCEPRequestBody(cep)
+	requestBody, err := d.buildConsultaCEPRequestBody(credenciais, cep)
 	if err != nil {
 		return CEP{}, err
 	}
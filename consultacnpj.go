@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"time"
 )
 
 const (
@@ -18,8 +17,8 @@ const (
 	ErrCNPJInvalido = Error("o cnpj informado é inválido (G000M003)")
 )
 
-func (d *defaultClient) buildConsultaCNPJRequestBody(cnpj string) (io.Reader, error) {
-	consultaCNPJ := newConsultaPessoaJuridicaNFe(d.credenciais, cnpj)
+func (d *defaultClient) buildConsultaCNPJRequestBody(credenciais Credenciais, cnpj string) (io.Reader, error) {
+	consultaCNPJ := newConsultaPessoaJuridicaNFe(credenciais, cnpj)
 	buf, err := json.Marshal(consultaCNPJ)
 	if err != nil {
 		return nil, fmt.Errorf("an error occurred while build the request body: %w", err)
@@ -35,21 +34,15 @@ func (d *defaultClient) parseConsultaCNPJResponseBody(resp *http.Response) (Pess
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		return PessoaJuridica{}, err
 	}
-	if result.Transacao.CodigoStatus == StatusDocumentoInvalido {
-		return PessoaJuridica{}, ErrCNPJInvalido
-	}
-	if result.Transacao.CodigoStatus == StatusCredenciaisInvalidas {
-		return PessoaJuridica{}, ErrCredenciaisInvalidas
-	}
-	if !result.Status {
-		return PessoaJuridica{}, fmt.Errorf("%s: %s", result.Transacao.CodigoStatus, result.Transacao.CodigoStatusDescricao)
+	if err := parseDocumentoTransacao(result.Status, result.Transacao, ErrCNPJInvalido); err != nil {
+		return PessoaJuridica{}, err
 	}
-	dataFundacao, _ := time.Parse("02/01/2006", result.DataFundacao)
-	return PessoaJuridica{
+	datas := leitorDatas{estrito: d.datasEstritas}
+	pj := PessoaJuridica{
 		Documento:    result.Documento,
 		RazaoSocial:  result.RazaoSocial,
 		NomeFantasia: result.NomeFantasia,
-		DataFundacao: dataFundacao,
+		DataFundacao: datas.ler("DataFundacao", result.DataFundacao),
 		Matriz:       result.MatrizFilial == "MATRIZ",
 		CNAE: CNAE{
 			Codigo:    result.CodigoAtividadeEconomica,
@@ -59,15 +52,62 @@ func (d *defaultClient) parseConsultaCNPJResponseBody(resp *http.Response) (Pess
 			Codigo:    result.CodigoNaturezaJuridica,
 			Descricao: result.CodigoNaturezaJuridicaDescricao,
 		},
-		Email:    result.Email,
-		Telefone: result.Telefone,
-	}, nil
+		SituacaoRFB:                   result.SituacaoRFB,
+		DataSituacaoRFB:               datas.ler("DataSituacaoRFB", result.DataSituacaoRFB),
+		MotivoSituacaoRFB:             result.MotivoSituacaoRFB,
+		DataConsultaRFB:               datas.ler("DataConsultaRFB", result.DataConsultaRFB),
+		DataMotivoEspecialSituacaoRFB: datas.ler("DataMotivoEspecialSituacaoRFB", result.DataMotivoEspecialSituacaoRFB),
+		Email:                         result.Email,
+		Telefone:                      result.Telefone,
+		QSA:                           parseQSA(result.QSA),
+		Avisos:                        datas.avisos,
+	}
+	if datas.err != nil {
+		return PessoaJuridica{}, datas.err
+	}
+	return pj, nil
+}
+
+func parseQSA(result qsaResult) QSA {
+	qsa := QSA{}
+	for _, s := range result.Socios {
+		qsa.Socios = append(qsa.Socios, Socio{
+			Pessoa:    TipoPessoa(s.Pessoa),
+			Documento: s.Documento,
+			Nome:      s.Nome,
+		})
+	}
+	for _, a := range result.Administradores {
+		qsa.Administradores = append(qsa.Administradores, Administrador{
+			Socio: Socio{
+				Pessoa:    TipoPessoa(a.Pessoa),
+				Documento: a.Documento,
+				Nome:      a.Nome,
+			},
+			Cargo: a.Cargo,
+		})
+	}
+	return qsa
 }
 
 func (d *defaultClient) ConsultarCNPJ(ctx context.Context, cnpj string) (PessoaJuridica, error) {
+	credenciais, err := d.credenciais(ctx)
+	if err != nil {
+		return PessoaJuridica{}, err
+	}
+	custo, err := d.usage.reservar(ServicoCNPJ)
+	if err != nil {
+		return PessoaJuridica{}, err
+	}
+	result, err := d.consultarCNPJ(ctx, credenciais, cnpj)
+	d.usage.registrar(ctx, ServicoCNPJ, custo, err)
+	return result, err
+}
+
+func (d *defaultClient) consultarCNPJ(ctx context.Context, credenciais Credenciais, cnpj string) (PessoaJuridica, error) {
 	errChan := make(chan error, 1)
 	resultChan := make(chan PessoaJuridica, 1)
-	requestBody, err := d.buildConsultaCNPJRequestBody(cnpj)
+	requestBody, err := d.buildConsultaCNPJRequestBody(credenciais, cnpj)
 	if err != nil {
 		return PessoaJuridica{}, err
 	}
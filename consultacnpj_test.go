@@ -44,7 +44,7 @@ func Test_defaultClient_ConsultarCNPJ(t *testing.T) {
 				Documento:    "99999999999962",
 				RazaoSocial:  "DOCUMENTO CNPJ DE TESTES",
 				NomeFantasia: "EMPRESA DE TESTES",
-				DataFundacao: time.Date(2007, 05, 02, 0, 0, 0, 0, time.UTC),
+				DataFundacao: time.Date(2007, 05, 02, 0, 0, 0, 0, soawebservices.SaoPaulo),
 				Matriz:       true,
 				CNAE: soawebservices.CNAE{
 					Codigo:    "82.91-1-00",
@@ -54,8 +54,27 @@ func Test_defaultClient_ConsultarCNPJ(t *testing.T) {
 					Codigo:    "206-2",
 					Descricao: "SOCIEDADE EMPRESARIA LIMITADA",
 				},
-				Email:    "email@email.com",
-				Telefone: "1199999999",
+				SituacaoRFB:                   "ATIVA",
+				DataSituacaoRFB:               time.Date(2021, 12, 6, 0, 0, 0, 0, soawebservices.SaoPaulo),
+				DataConsultaRFB:               time.Date(2021, 12, 6, 18, 55, 19, 0, soawebservices.SaoPaulo),
+				DataMotivoEspecialSituacaoRFB: time.Date(2021, 12, 6, 0, 0, 0, 0, soawebservices.SaoPaulo),
+				Email:                         "email@email.com",
+				Telefone:                      "1199999999",
+				QSA: soawebservices.QSA{
+					Socios: []soawebservices.Socio{{
+						Pessoa:    soawebservices.TipoPessoaFisica,
+						Documento: "99999999999",
+						Nome:      "NOME DO SOCIO",
+					}},
+					Administradores: []soawebservices.Administrador{{
+						Socio: soawebservices.Socio{
+							Pessoa:    soawebservices.TipoPessoaFisica,
+							Documento: "99999999999",
+							Nome:      "NOME DO PRESIDENTE",
+						},
+						Cargo: "PRESIDENTE",
+					}},
+				},
 			},
 		},
 		{
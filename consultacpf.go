@@ -4,9 +4,11 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"time"
 )
 
@@ -14,19 +16,14 @@ const (
 	urlCPF = "cdc/pessoafisicanfe.ashx"
 )
 
-const (
-	StatusDataNascimentoObrigatoria = "P009M001"
-	StatusDataNascimentoInvalida    = "P009M002"
-)
-
 const (
 	ErrDataNascimentoObrigatoria = Error("data de nascimento obrigatória (P009M001)")
 	ErrDataNascimentoInvalida    = Error("data de nascimento inválida (P009M002)")
 	ErrCPFInvalido               = Error("o cnpj informado é inválido (G000M003)")
 )
 
-func (d *defaultClient) buildConsultaCPFRequestBody(cpf string, dataNascimento time.Time) (io.Reader, error) {
-	consultaCPF := newConsultaPessoaFisicaNFe(d.credenciais, cpf, dataNascimento.Format("02/01/2006"))
+func (d *defaultClient) buildConsultaCPFRequestBody(credenciais Credenciais, cpf string, dataNascimento time.Time) (io.Reader, error) {
+	consultaCPF := newConsultaPessoaFisicaNFe(credenciais, cpf, dataNascimento.Format("02/01/2006"))
 	buf, err := json.Marshal(consultaCPF)
 	if err != nil {
 		return nil, fmt.Errorf("an error occurred while build the request body: %w", err)
@@ -42,35 +39,59 @@ func (d *defaultClient) parseConsultaCPFResponseBody(resp *http.Response) (Pesso
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		return PessoaFisica{}, err
 	}
-	if result.Transacao.CodigoStatus == StatusDataNascimentoInvalida {
-		return PessoaFisica{}, ErrDataNascimentoInvalida
+	if err := parseDocumentoTransacao(result.Status, result.Transacao, ErrCPFInvalido); err != nil {
+		return PessoaFisica{}, err
 	}
-	if result.Transacao.CodigoStatus == StatusDataNascimentoObrigatoria {
-		return PessoaFisica{}, ErrDataNascimentoObrigatoria
+	status, errStatus := ParsePessoaFisicaStatus(result.CodigoSituacaoCadastral)
+	if errStatus != nil {
+		status = PessoaFisicaStatus(strings.TrimSpace(result.CodigoSituacaoCadastral))
 	}
-	if result.Transacao.CodigoStatus == StatusDocumentoInvalido {
-		return PessoaFisica{}, ErrCPFInvalido
+	datas := leitorDatas{estrito: d.datasEstritas}
+	pf := PessoaFisica{
+		Documento:       result.Documento,
+		Nome:            result.Nome,
+		NomeSocial:      result.NomeSocial,
+		DataNascimento:  datas.ler("DataNascimento", result.DataNascimento),
+		DataInscricao:   datas.ler("DataInscricao", result.DataInscricao),
+		Status:          status,
+		SituacaoRFB:     result.SituacaoRFB,
+		DataConsultaRFB: datas.ler("DataConsultaRFB", result.DataConsultaRFB),
+		AnoObito:        datas.lerInteiro("AnoObito", result.AnoObito),
+		MensagemObito:   result.MensagemObito,
 	}
-	if result.Transacao.CodigoStatus == StatusCredenciaisInvalidas {
-		return PessoaFisica{}, ErrCredenciaisInvalidas
+	if datas.err != nil {
+		return PessoaFisica{}, datas.err
 	}
-	if !result.Status {
-		return PessoaFisica{}, fmt.Errorf("%s: %s", result.Transacao.CodigoStatus, result.Transacao.CodigoStatusDescricao)
+	if pf.AnoObito == 0 && strings.TrimSpace(result.AnoObito) != "" {
+		pf.AnoObitoTexto = result.AnoObito
 	}
-	dataNascimento, _ := time.Parse("02/01/2006", result.DataNascimento)
-	return PessoaFisica{
-		Documento:      result.Documento,
-		Nome:           result.Nome,
-		NomeSocial:     result.NomeSocial,
-		DataNascimento: dataNascimento,
-		Status:         PessoaFisicaStatus(result.CodigoSituacaoCadastral),
-	}, nil
+	pf.Avisos = datas.avisos
+	return pf, errStatus
 }
 
+// ConsultarCPF returns the person of the given CPF. A situação cadastral
+// unknown to this package fails with ErrPessoaFisicaStatusDesconhecido, but
+// the PessoaFisica, already billed, is returned along with the error, holding
+// the code received as its Status.
 func (d *defaultClient) ConsultarCPF(ctx context.Context, cpf string, dataNascimento time.Time) (PessoaFisica, error) {
+	credenciais, err := d.credenciais(ctx)
+	if err != nil {
+		return PessoaFisica{}, err
+	}
+	custo, err := d.usage.reservar(ServicoCPF)
+	if err != nil {
+		return PessoaFisica{}, err
+	}
+	result, err := d.consultarCPF(ctx, credenciais, cpf, dataNascimento)
+	d.usage.registrar(ctx, ServicoCPF, custo, err)
+	return result, err
+}
+
+func (d *defaultClient) consultarCPF(ctx context.Context, credenciais Credenciais, cpf string, dataNascimento time.Time) (PessoaFisica, error) {
 	errChan := make(chan error, 1)
 	resultChan := make(chan PessoaFisica, 1)
-	requestBody, err := d.buildConsultaCPFRequestBody(cpf, dataNascimento)
+	var errStatus error
+	requestBody, err := d.buildConsultaCPFRequestBody(credenciais, cpf, dataNascimento)
 	if err != nil {
 		return PessoaFisica{}, err
 	}
@@ -83,10 +104,11 @@ func (d *defaultClient) ConsultarCPF(ctx context.Context, cpf string, dataNascim
 			return
 		}
 		result, err := d.parseConsultaCPFResponseBody(resp)
-		if err != nil {
+		if err != nil && !errors.Is(err, ErrPessoaFisicaStatusDesconhecido) {
 			errChan <- err
 			return
 		}
+		errStatus = err
 		resultChan <- result
 	}()
 	select {
@@ -95,6 +117,6 @@ func (d *defaultClient) ConsultarCPF(ctx context.Context, cpf string, dataNascim
 	case <-ctx.Done():
 		return PessoaFisica{}, ctx.Err()
 	case result := <-resultChan:
-		return result, nil
+		return result, errStatus
 	}
 }
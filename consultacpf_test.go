@@ -2,6 +2,7 @@ package soawebservices_test
 
 import (
 	"context"
+	"errors"
 	"github.com/diegohordi/soawebservices"
 	"net/http"
 	"net/http/httptest"
@@ -18,10 +19,11 @@ func Test_defaultClient_ConsultarCPF(t *testing.T) {
 		dataNascimento time.Time
 	}
 	tests := []struct {
-		name    string
-		args    args
-		want    soawebservices.PessoaFisica
-		wantErr bool
+		name      string
+		args      args
+		want      soawebservices.PessoaFisica
+		wantErr   bool
+		wantErrIs error
 	}{
 		{
 			name: "should return a Pessoa Física",
@@ -49,6 +51,59 @@ func Test_defaultClient_ConsultarCPF(t *testing.T) {
 				DataNascimento: time.Time{},
 			},
 		},
+		{
+			name: "should return the death records of a Pessoa Física",
+			args: args{
+				httpClient: func() *http.Client {
+					return &http.Client{
+						Transport: RoundTripFunc(func(req *http.Request) *http.Response {
+							resp := httptest.NewRecorder()
+							resp.Body.Write(MustLoadTestDataFile(t, "consultacpf_falecido.json"))
+							return resp.Result()
+						}),
+						Timeout: 5 * time.Second,
+					}
+				},
+				ctx: func() (context.Context, context.CancelFunc) {
+					return context.TODO(), nil
+				},
+				cpf:            "999.999.999-99",
+				dataNascimento: time.Date(1940, 1, 1, 0, 0, 0, 0, time.UTC),
+			},
+			want: soawebservices.PessoaFisica{
+				Documento:      "99999999999",
+				Nome:           "DOCUMENTO CPF DE TESTE",
+				DataNascimento: time.Date(1940, 1, 1, 0, 0, 0, 0, soawebservices.SaoPaulo),
+				DataInscricao:  time.Date(1970, 1, 1, 0, 0, 0, 0, soawebservices.SaoPaulo),
+				Status:         soawebservices.TitularFalecido,
+				SituacaoRFB:    "TITULAR FALECIDO",
+				AnoObito:       2019,
+				MensagemObito:  "Titular falecido no ano de 2019",
+			},
+		},
+		{
+			name: "should return the Pessoa Física along with the error of an unknown situação cadastral",
+			args: args{
+				httpClient: func() *http.Client {
+					return &http.Client{
+						Transport: RoundTripFunc(func(req *http.Request) *http.Response {
+							resp := httptest.NewRecorder()
+							resp.Body.Write(MustLoadTestDataFile(t, "consultacpf_unknown_status.json"))
+							return resp.Result()
+						}),
+						Timeout: 5 * time.Second,
+					}
+				},
+				ctx: func() (context.Context, context.CancelFunc) {
+					return context.TODO(), nil
+				},
+				cpf:            "999.999.999-99",
+				dataNascimento: time.Date(1940, 1, 1, 0, 0, 0, 0, time.UTC),
+			},
+			want:      soawebservices.PessoaFisica{Documento: "77777777777", Status: "14"},
+			wantErr:   true,
+			wantErrIs: soawebservices.ErrPessoaFisicaStatusDesconhecido,
+		},
 		{
 			name: "should fail due to the given invalid data de nascimento",
 			args: args{
@@ -192,7 +247,10 @@ func Test_defaultClient_ConsultarCPF(t *testing.T) {
 				t.Errorf("ConsultarCPF() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if !tt.wantErr && !reflect.DeepEqual(result, tt.want) {
+			if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+				t.Errorf("ConsultarCPF() error = %v, want %v", err, tt.wantErrIs)
+			}
+			if (!tt.wantErr || tt.wantErrIs != nil) && !reflect.DeepEqual(result, tt.want) {
 				t.Error("want ", tt.want, " but got ", result)
 			}
 		})
@@ -0,0 +1,206 @@
+package soawebservices
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"time"
+)
+
+const (
+	urlSaldo   = "conta/saldo.ashx"
+	urlConsumo = "conta/consumo.ashx"
+)
+
+const (
+	ErrSaldoInsuficiente = Error("saldo insuficiente")
+)
+
+func (d *defaultClient) buildConsultaContaRequestBody(credenciais Credenciais, inicio, fim time.Time) (io.Reader, error) {
+	consulta := newConsultaConta(credenciais, inicio, fim)
+	buf, err := json.Marshal(consulta)
+	if err != nil {
+		return nil, fmt.Errorf("an error occurred while build the request body: %w", err)
+	}
+	return bytes.NewBuffer(buf), err
+}
+
+func (d *defaultClient) parseConsultaSaldoResponseBody(resp *http.Response) (Saldo, error) {
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+	result := saldoResult{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return Saldo{}, err
+	}
+	if err := parseTransacao(result.Status, result.Transacao); err != nil {
+		return Saldo{}, err
+	}
+	disponivel, err := ParseValor(result.Saldo)
+	if err != nil {
+		return Saldo{}, err
+	}
+	return Saldo{Disponivel: disponivel}, nil
+}
+
+func (d *defaultClient) parseConsultaConsumoResponseBody(resp *http.Response) ([]Consumo, error) {
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+	result := consumoResult{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	if err := parseTransacao(result.Status, result.Transacao); err != nil {
+		return nil, err
+	}
+	consumos := make([]Consumo, 0, len(result.Consumos))
+	for i, c := range result.Consumos {
+		datas := leitorDatas{estrito: d.datasEstritas}
+		data := datas.ler(fmt.Sprintf("Consumos[%d].Data", i), c.Data)
+		if datas.err != nil {
+			return nil, datas.err
+		}
+		valor, err := ParseValor(c.Valor)
+		if err != nil {
+			return nil, err
+		}
+		consumos = append(consumos, Consumo{
+			Data:       data,
+			Produto:    c.Produto,
+			Quantidade: c.Quantidade,
+			Valor:      valor,
+			Avisos:     datas.avisos,
+		})
+	}
+	return consumos, nil
+}
+
+func (d *defaultClient) ConsultarSaldo(ctx context.Context) (Saldo, error) {
+	errChan := make(chan error, 1)
+	resultChan := make(chan Saldo, 1)
+	credenciais, err := d.credenciais(ctx)
+	if err != nil {
+		return Saldo{}, err
+	}
+	requestBody, err := d.buildConsultaContaRequestBody(credenciais, time.Time{}, time.Time{})
+	if err != nil {
+		return Saldo{}, err
+	}
+	go func() {
+		serviceURL := fmt.Sprintf("%s/restservices/%s/%s", d.baseURL, d.ambiente, urlSaldo)
+		resp, err := d.httpClient.Post(serviceURL, "application/json", requestBody)
+		if err != nil {
+			errChan <- err
+			return
+		}
+		result, err := d.parseConsultaSaldoResponseBody(resp)
+		if err != nil {
+			errChan <- err
+			return
+		}
+		resultChan <- result
+	}()
+	select {
+	case err = <-errChan:
+		return Saldo{}, err
+	case <-ctx.Done():
+		return Saldo{}, ctx.Err()
+	case result := <-resultChan:
+		return result, nil
+	}
+}
+
+func (d *defaultClient) ConsultarConsumo(ctx context.Context, inicio, fim time.Time) ([]Consumo, error) {
+	if fim.Before(inicio) {
+		return nil, fmt.Errorf("the end of the period (%s) is before its start (%s)", fim.Format("02/01/2006"), inicio.Format("02/01/2006"))
+	}
+	errChan := make(chan error, 1)
+	resultChan := make(chan []Consumo, 1)
+	credenciais, err := d.credenciais(ctx)
+	if err != nil {
+		return nil, err
+	}
+	requestBody, err := d.buildConsultaContaRequestBody(credenciais, inicio, fim)
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		serviceURL := fmt.Sprintf("%s/restservices/%s/%s", d.baseURL, d.ambiente, urlConsumo)
+		resp, err := d.httpClient.Post(serviceURL, "application/json", requestBody)
+		if err != nil {
+			errChan <- err
+			return
+		}
+		result, err := d.parseConsultaConsumoResponseBody(resp)
+		if err != nil {
+			errChan <- err
+			return
+		}
+		resultChan <- result
+	}()
+	select {
+	case err = <-errChan:
+		return nil, err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case result := <-resultChan:
+		return result, nil
+	}
+}
+
+// TabelaPrecos holds the price of a call to each service.
+type TabelaPrecos map[Servico]Valor
+
+// EstimarCusto returns the cost of the given number of calls to each service,
+// failing if the price of any of them is unknown.
+func (t TabelaPrecos) EstimarCusto(quantidades map[Servico]int) (Valor, error) {
+	servicos := make([]Servico, 0, len(quantidades))
+	for servico := range quantidades {
+		servicos = append(servicos, servico)
+	}
+	sort.Slice(servicos, func(i, j int) bool { return servicos[i] < servicos[j] })
+	custo := Valor(0)
+	for _, servico := range servicos {
+		quantidade := quantidades[servico]
+		if quantidade == 0 {
+			continue
+		}
+		preco, ok := t[servico]
+		if !ok {
+			return 0, fmt.Errorf("the price of the %s service is unknown", servico)
+		}
+		custo += preco * Valor(quantidade)
+	}
+	return custo, nil
+}
+
+// Estimativa is the result of VerificarSaldo.
+type Estimativa struct {
+	Custo Valor
+	Saldo Valor
+}
+
+// VerificarSaldo estimates the cost of the given number of calls to each
+// service and checks it against the account balance, failing with
+// ErrSaldoInsuficiente if the balance does not cover it. It is meant to be
+// called before starting a batch, so it does not fail halfway through.
+func VerificarSaldo(ctx context.Context, conta ContaService, tabela TabelaPrecos, quantidades map[Servico]int) (Estimativa, error) {
+	custo, err := tabela.EstimarCusto(quantidades)
+	if err != nil {
+		return Estimativa{}, err
+	}
+	saldo, err := conta.ConsultarSaldo(ctx)
+	if err != nil {
+		return Estimativa{}, err
+	}
+	estimativa := Estimativa{Custo: custo, Saldo: saldo.Disponivel}
+	if custo > saldo.Disponivel {
+		return estimativa, fmt.Errorf("%w: custo estimado de %s, saldo de %s", ErrSaldoInsuficiente, custo, saldo.Disponivel)
+	}
+	return estimativa, nil
+}
@@ -0,0 +1,145 @@
+package soawebservices_test
+
+import (
+	"context"
+	"errors"
+	"github.com/diegohordi/soawebservices"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func fixtureClient(t *testing.T, fileName string) *http.Client {
+	return &http.Client{
+		Transport: RoundTripFunc(func(req *http.Request) *http.Response {
+			resp := httptest.NewRecorder()
+			resp.Body.Write(MustLoadTestDataFile(t, fileName))
+			return resp.Result()
+		}),
+		Timeout: 5 * time.Second,
+	}
+}
+
+func Test_defaultClient_ConsultarSaldo(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		want    soawebservices.Saldo
+		wantErr error
+	}{
+		{
+			name: "should return the balance",
+			file: "consultasaldo_success.json",
+			want: soawebservices.Saldo{Disponivel: soawebservices.Reais(1234, 56)},
+		},
+		{
+			name:    "should fail due to wrong credentials",
+			file:    "consultasaldo_wrong_credentials.json",
+			wantErr: soawebservices.ErrCredenciaisInvalidas,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			client := MustCreateClient(fixtureClient(t, tt.file))
+			result, err := client.ConsultarSaldo(context.TODO())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if !reflect.DeepEqual(result, tt.want) {
+				t.Error("want ", tt.want, " but got ", result)
+			}
+		})
+	}
+}
+
+func Test_defaultClient_ConsultarConsumo(t *testing.T) {
+	inicio := time.Date(2021, 12, 1, 0, 0, 0, 0, soawebservices.SaoPaulo)
+	fim := time.Date(2021, 12, 31, 0, 0, 0, 0, soawebservices.SaoPaulo)
+	var body string
+	client := MustCreateClient(&http.Client{
+		Transport: RoundTripFunc(func(req *http.Request) *http.Response {
+			buf, _ := io.ReadAll(req.Body)
+			body = string(buf)
+			resp := httptest.NewRecorder()
+			resp.Body.Write(MustLoadTestDataFile(t, "consultaconsumo_success.json"))
+			return resp.Result()
+		}),
+	})
+	result, err := client.ConsultarConsumo(context.TODO(), inicio, fim)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(body, `"DataInicial":"01/12/2021"`) || !strings.Contains(body, `"DataFinal":"31/12/2021"`) {
+		t.Errorf("unexpected request body %s", body)
+	}
+	want := []soawebservices.Consumo{
+		{Data: inicio, Produto: "PESSOA FISICA NFE", Quantidade: 120, Valor: soawebservices.Reais(60, 0)},
+		{Data: inicio, Produto: "CEP ESTENDIDO", Quantidade: 1500, Valor: soawebservices.Reais(150, 0)},
+	}
+	if !reflect.DeepEqual(result, want) {
+		t.Error("want ", want, " but got ", result)
+	}
+	if _, err = client.ConsultarConsumo(context.TODO(), fim, inicio); err == nil {
+		t.Error("expected an error due to the inverted period")
+	}
+}
+
+type saldoFixo soawebservices.Valor
+
+func (s saldoFixo) ConsultarSaldo(_ context.Context) (soawebservices.Saldo, error) {
+	return soawebservices.Saldo{Disponivel: soawebservices.Valor(s)}, nil
+}
+
+func (s saldoFixo) ConsultarConsumo(_ context.Context, _, _ time.Time) ([]soawebservices.Consumo, error) {
+	return nil, nil
+}
+
+func TestVerificarSaldo(t *testing.T) {
+	tabela := soawebservices.TabelaPrecos{
+		soawebservices.ServicoCEP: soawebservices.Reais(0, 10),
+		soawebservices.ServicoCPF: soawebservices.Reais(0, 50),
+	}
+	tests := []struct {
+		name        string
+		saldo       soawebservices.Valor
+		quantidades map[soawebservices.Servico]int
+		wantCusto   soawebservices.Valor
+		wantErr     error
+	}{
+		{
+			name:        "should accept a batch covered by the balance",
+			saldo:       soawebservices.Reais(100, 0),
+			quantidades: map[soawebservices.Servico]int{soawebservices.ServicoCEP: 100, soawebservices.ServicoCPF: 100},
+			wantCusto:   soawebservices.Reais(60, 0),
+		},
+		{
+			name:        "should refuse a batch not covered by the balance",
+			saldo:       soawebservices.Reais(59, 99),
+			quantidades: map[soawebservices.Servico]int{soawebservices.ServicoCEP: 100, soawebservices.ServicoCPF: 100},
+			wantCusto:   soawebservices.Reais(60, 0),
+			wantErr:     soawebservices.ErrSaldoInsuficiente,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			estimativa, err := soawebservices.VerificarSaldo(context.TODO(), saldoFixo(tt.saldo), tabela, tt.quantidades)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if estimativa.Custo != tt.wantCusto || estimativa.Saldo != tt.saldo {
+				t.Errorf("unexpected estimativa %+v", estimativa)
+			}
+		})
+	}
+	if _, err := tabela.EstimarCusto(map[soawebservices.Servico]int{soawebservices.ServicoCNPJ: 1}); err == nil {
+		t.Error("expected an error due to the unknown price")
+	}
+}
@@ -0,0 +1,95 @@
+package soawebservices
+
+import (
+	"context"
+	"github.com/diegohordi/soawebservices/internal/documento"
+	"strings"
+)
+
+const (
+	urlTelefones = "contato/telefones.ashx"
+	urlEmails    = "contato/emails.ashx"
+)
+
+// errDocumentoInvalido returns the error of an invalid CPF or CNPJ, telling
+// them apart by the length of the given document.
+func errDocumentoInvalido(doc string) error {
+	if len(documento.SomenteDigitos(doc)) == documento.TamanhoCPF {
+		return ErrCPFInvalido
+	}
+	return ErrCNPJInvalido
+}
+
+func parseTelefonesResult(result *telefonesResult, errDocumento error) ([]Telefone, error) {
+	if err := parseDocumentoTransacao(result.Status, result.Transacao, errDocumento); err != nil {
+		return nil, err
+	}
+	telefones := make([]Telefone, 0, len(result.Telefones))
+	for _, r := range result.Telefones {
+		t, err := ParseTelefone(r.DDD + r.Numero)
+		if err != nil {
+			continue
+		}
+		if tipo := TipoTelefone(strings.ToUpper(r.Tipo)); tipo == TelefoneMovel || tipo == TelefoneFixo {
+			t.Tipo = tipo
+		}
+		t.Operadora = r.Operadora
+		telefones = append(telefones, t)
+	}
+	return telefones, nil
+}
+
+func parseEmailsResult(result *emailsResult, errDocumento error) ([]string, error) {
+	if err := parseDocumentoTransacao(result.Status, result.Transacao, errDocumento); err != nil {
+		return nil, err
+	}
+	emails := make([]string, 0, len(result.Emails))
+	for _, r := range result.Emails {
+		email := strings.ToLower(strings.TrimSpace(r.Email))
+		if ValidarEmail(email) == nil {
+			emails = append(emails, email)
+		}
+	}
+	return emails, nil
+}
+
+// ConsultarTelefones returns the phones of the given CPF or CNPJ, normalized
+// with ParseTelefone. Numbers that are not valid Brazilian phones are left
+// out.
+func (d *defaultClient) ConsultarTelefones(ctx context.Context, doc string) ([]Telefone, error) {
+	credenciais, err := d.credenciais(ctx)
+	if err != nil {
+		return nil, err
+	}
+	custo, err := d.usage.reservar(ServicoTelefones)
+	if err != nil {
+		return nil, err
+	}
+	var telefones []Telefone
+	result, err := d.consultarDocumento(ctx, credenciais, urlTelefones, doc, func() interface{} { return &telefonesResult{} })
+	if err == nil {
+		telefones, err = parseTelefonesResult(result.(*telefonesResult), errDocumentoInvalido(doc))
+	}
+	d.usage.registrar(ctx, ServicoTelefones, custo, err)
+	return telefones, err
+}
+
+// ConsultarEmails returns the e-mail addresses of the given CPF or CNPJ in
+// lower case. Addresses that are not valid are left out.
+func (d *defaultClient) ConsultarEmails(ctx context.Context, doc string) ([]string, error) {
+	credenciais, err := d.credenciais(ctx)
+	if err != nil {
+		return nil, err
+	}
+	custo, err := d.usage.reservar(ServicoEmails)
+	if err != nil {
+		return nil, err
+	}
+	var emails []string
+	result, err := d.consultarDocumento(ctx, credenciais, urlEmails, doc, func() interface{} { return &emailsResult{} })
+	if err == nil {
+		emails, err = parseEmailsResult(result.(*emailsResult), errDocumentoInvalido(doc))
+	}
+	d.usage.registrar(ctx, ServicoEmails, custo, err)
+	return emails, err
+}
@@ -0,0 +1,66 @@
+package soawebservices_test
+
+import (
+	"context"
+	"errors"
+	"github.com/diegohordi/soawebservices"
+	"reflect"
+	"testing"
+)
+
+func Test_defaultClient_ConsultarTelefones(t *testing.T) {
+	tests := []struct {
+		name      string
+		file      string
+		documento string
+		want      []soawebservices.Telefone
+		wantErr   error
+	}{
+		{
+			name:      "should return the valid phones",
+			file:      "consultatelefones_success.json",
+			documento: "529.982.247-25",
+			want: []soawebservices.Telefone{
+				{DDD: "11", Numero: "912345678", Tipo: soawebservices.TelefoneMovel, Operadora: "VIVO"},
+				{DDD: "11", Numero: "31234567", Tipo: soawebservices.TelefoneFixo, Operadora: "TELEFONICA"},
+			},
+		},
+		{
+			name:      "should fail due to the given invalid CPF",
+			file:      "consultacpf_invalid_cpf.json",
+			documento: "529.982.247-24",
+			wantErr:   soawebservices.ErrCPFInvalido,
+		},
+		{
+			name:      "should fail due to the given invalid CNPJ",
+			file:      "consultacnpj_invalid_cnpj.json",
+			documento: "39.621.470/0001-09",
+			wantErr:   soawebservices.ErrCNPJInvalido,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			client := MustCreateClient(fixtureClient(t, tt.file))
+			result, err := client.ConsultarTelefones(context.TODO(), tt.documento)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if !reflect.DeepEqual(result, tt.want) {
+				t.Error("want ", tt.want, " but got ", result)
+			}
+		})
+	}
+}
+
+func Test_defaultClient_ConsultarEmails(t *testing.T) {
+	client := MustCreateClient(fixtureClient(t, "consultaemails_success.json"))
+	result, err := client.ConsultarEmails(context.TODO(), "529.982.247-25")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"jose.silva@empresa.com.br"}; !reflect.DeepEqual(result, want) {
+		t.Error("want ", want, " but got ", result)
+	}
+}
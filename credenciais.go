@@ -0,0 +1,201 @@
+package soawebservices
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+const (
+	ErrEmailObrigatorio    = Error("e-mail das credenciais não informado")
+	ErrSenhaObrigatoria    = Error("senha das credenciais não informada")
+	ErrTenantAusente       = Error("tenant não informado no contexto")
+	ErrTenantDesconhecido  = Error("tenant desconhecido")
+	ErrCredenciaisAusentes = Error("credenciais não encontradas")
+)
+
+// redacted replaces the Senha whenever Credenciais are formatted, logged or
+// marshalled.
+const redacted = "[REDACTED]"
+
+func redact(value string) string {
+	if value == "" {
+		return ""
+	}
+	return redacted
+}
+
+// Validar checks that both the Email and the Senha are informed.
+func (c Credenciais) Validar() error {
+	if strings.TrimSpace(c.Email) == "" {
+		return ErrEmailObrigatorio
+	}
+	if strings.TrimSpace(c.Senha) == "" {
+		return ErrSenhaObrigatoria
+	}
+	return nil
+}
+
+// String returns the Credenciais with the Senha redacted.
+func (c Credenciais) String() string {
+	return fmt.Sprintf("%+v", c)
+}
+
+// Format implements fmt.Formatter, so the Senha is redacted with every verb,
+// including %+v and %#v.
+func (c Credenciais) Format(f fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && f.Flag('#'):
+		_, _ = fmt.Fprintf(f, "soawebservices.Credenciais{Email:%q, Senha:%q}", c.Email, redact(c.Senha))
+	case verb == 'v' && f.Flag('+'):
+		_, _ = fmt.Fprintf(f, "{Email:%s Senha:%s}", c.Email, redact(c.Senha))
+	default:
+		_, _ = fmt.Fprintf(f, "{%s %s}", c.Email, redact(c.Senha))
+	}
+}
+
+// MarshalJSON implements json.Marshaler, redacting the Senha. The requests
+// to SOA WebServices do not use it.
+func (c Credenciais) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Email string
+		Senha string
+	}{Email: c.Email, Senha: redact(c.Senha)})
+}
+
+// CredenciaisProvider resolves the Credenciais of each call, so a single
+// Client may serve several tenants and have its credentials rotated.
+type CredenciaisProvider interface {
+	Credenciais(ctx context.Context) (Credenciais, error)
+}
+
+type tenantKey struct{}
+
+// WithTenant returns a copy of the given context carrying the given tenant,
+// used to resolve the Credenciais of the calls and to attribute their usage.
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFromContext returns the tenant carried by the given context, if any.
+func TenantFromContext(ctx context.Context) (string, bool) {
+	tenant, ok := ctx.Value(tenantKey{}).(string)
+	return tenant, ok && tenant != ""
+}
+
+type staticCredenciaisProvider struct {
+	credenciais Credenciais
+}
+
+// StaticCredenciais returns a CredenciaisProvider that always resolves the
+// given Credenciais, regardless of the tenant.
+func StaticCredenciais(credenciais Credenciais) CredenciaisProvider {
+	return staticCredenciaisProvider{credenciais: credenciais}
+}
+
+func (p staticCredenciaisProvider) Credenciais(_ context.Context) (Credenciais, error) {
+	return p.credenciais, nil
+}
+
+func (p staticCredenciaisProvider) Format(f fmt.State, _ rune) {
+	_, _ = fmt.Fprintf(f, "StaticCredenciais(%+v)", p.credenciais)
+}
+
+// FileCredenciaisProvider resolves the Credenciais of the tenant in the
+// context from a JSON file mapping each tenant to its credentials, as in
+// {"tenant": {"email": "...", "senha": "..."}}. The file is reloaded whenever
+// it changes, so credentials can be added and rotated without rebuilding the
+// Client. If a reload fails, e.g. while the file is being written, the
+// previously loaded credentials are kept.
+type FileCredenciaisProvider struct {
+	path    string
+	mu      sync.Mutex
+	modTime time.Time
+	size    int64
+	tenants map[string]Credenciais
+}
+
+func NewFileCredenciaisProvider(path string) (*FileCredenciaisProvider, error) {
+	p := &FileCredenciaisProvider{path: path}
+	if err := p.reload(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// reload must be called with the lock held, except by the constructor.
+func (p *FileCredenciaisProvider) reload() error {
+	info, err := os.Stat(p.path)
+	if err != nil {
+		return err
+	}
+	if p.tenants != nil && info.ModTime().Equal(p.modTime) && info.Size() == p.size {
+		return nil
+	}
+	buf, err := os.ReadFile(p.path)
+	if err != nil {
+		return err
+	}
+	tenants := make(map[string]Credenciais)
+	if err = json.Unmarshal(buf, &tenants); err != nil {
+		return fmt.Errorf("invalid credentials file %s: %w", p.path, err)
+	}
+	p.tenants, p.modTime, p.size = tenants, info.ModTime(), info.Size()
+	return nil
+}
+
+func (p *FileCredenciaisProvider) Credenciais(ctx context.Context) (Credenciais, error) {
+	tenant, ok := TenantFromContext(ctx)
+	if !ok {
+		return Credenciais{}, ErrTenantAusente
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	// The previous credentials are kept when the file cannot be reloaded.
+	_ = p.reload()
+	credenciais, ok := p.tenants[tenant]
+	if !ok {
+		return Credenciais{}, fmt.Errorf("%w: %s", ErrTenantDesconhecido, tenant)
+	}
+	return credenciais, nil
+}
+
+func (p *FileCredenciaisProvider) Format(f fmt.State, _ rune) {
+	_, _ = fmt.Fprintf(f, "FileCredenciaisProvider(%s)", p.path)
+}
+
+// EnvCredenciaisProvider resolves the Credenciais from environment variables
+// read on every call. The credentials of a tenant are read from
+// <prefix>_<TENANT>_EMAIL and <prefix>_<TENANT>_SENHA, where TENANT is the
+// tenant in upper case with every character other than letters and digits
+// replaced by an underscore. Without a tenant in the context, they are read
+// from <prefix>_EMAIL and <prefix>_SENHA.
+type EnvCredenciaisProvider struct {
+	prefix string
+}
+
+func NewEnvCredenciaisProvider(prefix string) *EnvCredenciaisProvider {
+	return &EnvCredenciaisProvider{prefix: prefix}
+}
+
+func (p *EnvCredenciaisProvider) Credenciais(ctx context.Context) (Credenciais, error) {
+	prefix := p.prefix
+	if tenant, ok := TenantFromContext(ctx); ok {
+		prefix += "_" + strings.Map(func(r rune) rune {
+			if r > unicode.MaxASCII || !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+				return '_'
+			}
+			return unicode.ToUpper(r)
+		}, tenant)
+	}
+	credenciais := Credenciais{Email: os.Getenv(prefix + "_EMAIL"), Senha: os.Getenv(prefix + "_SENHA")}
+	if credenciais.Email == "" || credenciais.Senha == "" {
+		return Credenciais{}, fmt.Errorf("%w: %s_EMAIL e %s_SENHA", ErrCredenciaisAusentes, prefix, prefix)
+	}
+	return credenciais, nil
+}
@@ -0,0 +1,12 @@
+//go:build go1.21
+
+package soawebservices
+
+import (
+	"log/slog"
+)
+
+// LogValue implements slog.LogValuer, redacting the Senha.
+func (c Credenciais) LogValue() slog.Value {
+	return slog.GroupValue(slog.String("email", c.Email), slog.String("senha", redact(c.Senha)))
+}
@@ -0,0 +1,20 @@
+//go:build go1.21
+
+package soawebservices_test
+
+import (
+	"bytes"
+	"github.com/diegohordi/soawebservices"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestCredenciais_LogValue(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := slog.New(slog.NewJSONHandler(buf, nil))
+	logger.Info("consulta", "credenciais", soawebservices.Credenciais{Email: "test@test.com", Senha: "segredo"})
+	if strings.Contains(buf.String(), "segredo") || !strings.Contains(buf.String(), `"senha":"[REDACTED]"`) {
+		t.Errorf("expected a redacted senha, got %s", buf)
+	}
+}
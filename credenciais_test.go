@@ -0,0 +1,259 @@
+package soawebservices_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/diegohordi/soawebservices"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFileCredenciaisProvider_Credenciais(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credenciais.json")
+	if err := os.WriteFile(path, []byte(`{"unidade-a": {"email": "a@test.com", "senha": "a"}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	provider, err := soawebservices.NewFileCredenciaisProvider(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		want    soawebservices.Credenciais
+		wantErr error
+	}{
+		{
+			name: "should return the credenciais of the tenant",
+			ctx:  soawebservices.WithTenant(context.TODO(), "unidade-a"),
+			want: soawebservices.Credenciais{Email: "a@test.com", Senha: "a"},
+		},
+		{
+			name:    "should fail due to an unknown tenant",
+			ctx:     soawebservices.WithTenant(context.TODO(), "unidade-b"),
+			wantErr: soawebservices.ErrTenantDesconhecido,
+		},
+		{
+			name:    "should fail due to a missing tenant",
+			ctx:     context.TODO(),
+			wantErr: soawebservices.ErrTenantAusente,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := provider.Credenciais(tt.ctx)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Credenciais() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFileCredenciaisProvider_reload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credenciais.json")
+	write := func(content string, modTime time.Time) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	now := time.Now()
+	write(`{"unidade-a": {"email": "a@test.com", "senha": "antiga"}}`, now.Add(-time.Minute))
+	provider, err := soawebservices.NewFileCredenciaisProvider(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := soawebservices.WithTenant(context.TODO(), "unidade-a")
+
+	write(`{"unidade-a": {"email": "a@test.com", "senha": "nova"}}`, now)
+	if got, err := provider.Credenciais(ctx); err != nil || got.Senha != "nova" {
+		t.Errorf("expected the rotated senha, got %v (%v)", got.Senha, err)
+	}
+	write(`{"unidade-a": `, now.Add(time.Minute))
+	if got, err := provider.Credenciais(ctx); err != nil || got.Senha != "nova" {
+		t.Errorf("expected the previous senha to be kept, got %v (%v)", got.Senha, err)
+	}
+}
+
+func TestEnvCredenciaisProvider_Credenciais(t *testing.T) {
+	t.Setenv("SOAWS_EMAIL", "padrao@test.com")
+	t.Setenv("SOAWS_SENHA", "padrao")
+	t.Setenv("SOAWS_UNIDADE_A_EMAIL", "a@test.com")
+	t.Setenv("SOAWS_UNIDADE_A_SENHA", "a")
+	provider := soawebservices.NewEnvCredenciaisProvider("SOAWS")
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		want    soawebservices.Credenciais
+		wantErr error
+	}{
+		{
+			name: "should return the credenciais of the tenant",
+			ctx:  soawebservices.WithTenant(context.TODO(), "unidade-a"),
+			want: soawebservices.Credenciais{Email: "a@test.com", Senha: "a"},
+		},
+		{
+			name: "should return the credenciais without a tenant",
+			ctx:  context.TODO(),
+			want: soawebservices.Credenciais{Email: "padrao@test.com", Senha: "padrao"},
+		},
+		{
+			name:    "should fail due to a tenant without credenciais",
+			ctx:     soawebservices.WithTenant(context.TODO(), "unidade-b"),
+			wantErr: soawebservices.ErrCredenciaisAusentes,
+		},
+	}
+	for _, tt := range tests {
+		got, err := provider.Credenciais(tt.ctx)
+		if !errors.Is(err, tt.wantErr) {
+			t.Fatalf("%s: expected error %v, got %v", tt.name, tt.wantErr, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Credenciais() got = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+type tenantProvider map[string]soawebservices.Credenciais
+
+func (p tenantProvider) Credenciais(ctx context.Context) (soawebservices.Credenciais, error) {
+	tenant, _ := soawebservices.TenantFromContext(ctx)
+	credenciais, ok := p[tenant]
+	if !ok {
+		return soawebservices.Credenciais{}, soawebservices.ErrTenantDesconhecido
+	}
+	return credenciais, nil
+}
+
+func TestNewClientWithProvider(t *testing.T) {
+	httpClient := &http.Client{
+		Transport: RoundTripFunc(func(req *http.Request) *http.Response {
+			body := struct{ Credenciais soawebservices.Credenciais }{}
+			_ = json.NewDecoder(req.Body).Decode(&body)
+			resp := httptest.NewRecorder()
+			if body.Credenciais.Email == "a@test.com" {
+				resp.Body.Write(MustLoadTestDataFile(t, "consultacnpj_success.json"))
+			} else {
+				resp.Body.Write(MustLoadTestDataFile(t, "consultacnpj_wrong_credentials.json"))
+			}
+			return resp.Result()
+		}),
+	}
+	provider := tenantProvider{
+		"unidade-a": {Email: "a@test.com", Senha: "a"},
+		"unidade-b": {Email: "b@test.com", Senha: "b"},
+	}
+	client := soawebservices.NewClientWithProvider(httpClient, "https://soawebservices.com.br", soawebservices.TestDrive, provider)
+
+	ctxA := soawebservices.WithTenant(context.TODO(), "unidade-a")
+	ctxB := soawebservices.WithTenant(context.TODO(), "unidade-b")
+	for i := 0; i < 2; i++ {
+		if _, err := client.ConsultarCNPJ(ctxA, "99999999999962"); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+	}
+	if _, err := client.ConsultarCNPJ(ctxB, "99999999999962"); !errors.Is(err, soawebservices.ErrCredenciaisInvalidas) {
+		t.Fatalf("expected %v, got %v", soawebservices.ErrCredenciaisInvalidas, err)
+	}
+	if _, err := client.ConsultarCNPJ(soawebservices.WithTenant(context.TODO(), "unidade-c"), "99999999999962"); !errors.Is(err, soawebservices.ErrTenantDesconhecido) {
+		t.Fatalf("expected %v, got %v", soawebservices.ErrTenantDesconhecido, err)
+	}
+
+	want := []soawebservices.Usage{
+		{Tenant: "unidade-a", Servico: soawebservices.ServicoCNPJ, Consultas: 2},
+		{Tenant: "unidade-b", Servico: soawebservices.ServicoCNPJ, Consultas: 1, Falhas: 1},
+	}
+	if got := client.(soawebservices.UsageReporter).Usage(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Usage() got = %+v, want %+v", got, want)
+	}
+}
+
+func TestCredenciais_redaction(t *testing.T) {
+	credenciais := soawebservices.Credenciais{Email: "test@test.com", Senha: "segredo"}
+	client, err := soawebservices.NewClient(http.DefaultClient, "https://soawebservices.com.br", soawebservices.TestDrive, credenciais)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf, err := json.Marshal(credenciais)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		got  string
+	}{
+		{name: "should redact %v", got: fmt.Sprintf("%v", credenciais)},
+		{name: "should redact %+v", got: fmt.Sprintf("%+v", credenciais)},
+		{name: "should redact %#v", got: fmt.Sprintf("%#v", credenciais)},
+		{name: "should redact %s", got: fmt.Sprintf("%s", credenciais)},
+		{name: "should redact String", got: credenciais.String()},
+		{name: "should redact a pointer", got: fmt.Sprintf("%+v", &credenciais)},
+		{name: "should redact a nested struct", got: fmt.Sprintf("%+v", struct{ C soawebservices.Credenciais }{credenciais})},
+		{name: "should redact the JSON", got: string(buf)},
+		{name: "should redact the client", got: fmt.Sprintf("%+v %#v", client, client)},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if strings.Contains(tt.got, "segredo") {
+				t.Errorf("the senha was not redacted: %s", tt.got)
+			}
+			if !strings.Contains(tt.got, "[REDACTED]") {
+				t.Errorf("expected a redacted senha, got %s", tt.got)
+			}
+		})
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	tests := []struct {
+		name        string
+		credenciais soawebservices.Credenciais
+		wantErr     error
+	}{
+		{
+			name:        "should create a client",
+			credenciais: soawebservices.Credenciais{Email: "test@test.com", Senha: "test"},
+		},
+		{
+			name:        "should fail due to an empty email",
+			credenciais: soawebservices.Credenciais{Senha: "test"},
+			wantErr:     soawebservices.ErrEmailObrigatorio,
+		},
+		{
+			name:        "should fail due to an empty senha",
+			credenciais: soawebservices.Credenciais{Email: "test@test.com", Senha: " "},
+			wantErr:     soawebservices.ErrSenhaObrigatoria,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			client, err := soawebservices.NewClient(http.DefaultClient, "https://soawebservices.com.br", soawebservices.TestDrive, tt.credenciais)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if (client == nil) != (tt.wantErr != nil) {
+				t.Errorf("unexpected client %v", client)
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package soawebservices
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+const (
+	urlScoreCPF       = "credito/scorepf.ashx"
+	urlScoreCNPJ      = "credito/scorepj.ashx"
+	urlRestricoesCPF  = "credito/restricoespf.ashx"
+	urlRestricoesCNPJ = "credito/restricoespj.ashx"
+)
+
+func (d *defaultClient) buildConsultaDocumentoRequestBody(credenciais Credenciais, documento string) (io.Reader, error) {
+	buf, err := json.Marshal(newConsultaDocumento(credenciais, documento))
+	if err != nil {
+		return nil, fmt.Errorf("an error occurred while build the request body: %w", err)
+	}
+	return bytes.NewBuffer(buf), err
+}
+
+// consultarDocumento posts a query of the given document to the given REST
+// service and decodes its response into a new value of the type returned by
+// newResult.
+func (d *defaultClient) consultarDocumento(ctx context.Context, credenciais Credenciais, url, documento string, newResult func() interface{}) (interface{}, error) {
+	errChan := make(chan error, 1)
+	resultChan := make(chan interface{}, 1)
+	requestBody, err := d.buildConsultaDocumentoRequestBody(credenciais, documento)
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		serviceURL := fmt.Sprintf("%s/restservices/%s/%s", d.baseURL, d.ambiente, url)
+		resp, err := d.httpClient.Post(serviceURL, "application/json", requestBody)
+		if err != nil {
+			errChan <- err
+			return
+		}
+		defer func(Body io.ReadCloser) {
+			_ = Body.Close()
+		}(resp.Body)
+		result := newResult()
+		if err = json.NewDecoder(resp.Body).Decode(result); err != nil {
+			errChan <- err
+			return
+		}
+		resultChan <- result
+	}()
+	select {
+	case err = <-errChan:
+		return nil, err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case result := <-resultChan:
+		return result, nil
+	}
+}
+
+func parseScoreResult(result *scoreResult, errDocumento error, datas *leitorDatas) (Score, error) {
+	if err := parseDocumentoTransacao(result.Status, result.Transacao, errDocumento); err != nil {
+		return Score{}, err
+	}
+	score := Score{
+		Documento:                  result.Documento,
+		Pontuacao:                  result.Score,
+		Faixa:                      result.Faixa,
+		ProbabilidadeInadimplencia: datas.lerDecimal("ProbabilidadeInadimplencia", result.ProbabilidadeInadimplencia),
+		Data:                       datas.ler("DataConsulta", result.DataConsulta),
+	}
+	if datas.err != nil {
+		return Score{}, datas.err
+	}
+	score.Avisos = datas.avisos
+	return score, nil
+}
+
+func parseRestricoesResult(result *restricoesResult, errDocumento error, datas *leitorDatas) (Restricoes, error) {
+	if err := parseDocumentoTransacao(result.Status, result.Transacao, errDocumento); err != nil {
+		return Restricoes{}, err
+	}
+	restricoes := Restricoes{Documento: result.Documento}
+	for i, p := range result.Protestos {
+		valor, err := ParseValor(p.Valor)
+		if err != nil {
+			return Restricoes{}, err
+		}
+		data := datas.ler(fmt.Sprintf("Protestos[%d].Data", i), p.Data)
+		restricoes.Protestos = append(restricoes.Protestos, Protesto{
+			Cartorio: p.Cartorio,
+			Cidade:   p.Cidade,
+			UF:       p.UF,
+			Valor:    valor,
+			Data:     data,
+		})
+	}
+	for i, c := range result.ChequesSemFundo {
+		data := datas.ler(fmt.Sprintf("ChequesSemFundo[%d].DataUltimaOcorrencia", i), c.DataUltimaOcorrencia)
+		restricoes.ChequesSemFundo = append(restricoes.ChequesSemFundo, ChequeSemFundo{
+			Banco:            c.Banco,
+			Agencia:          c.Agencia,
+			Quantidade:       c.Quantidade,
+			UltimaOcorrencia: data,
+		})
+	}
+	for i, p := range result.Pendencias {
+		valor, err := ParseValor(p.Valor)
+		if err != nil {
+			return Restricoes{}, err
+		}
+		data := datas.ler(fmt.Sprintf("Pendencias[%d].Data", i), p.Data)
+		restricoes.Pendencias = append(restricoes.Pendencias, Pendencia{
+			Tipo:     p.Tipo,
+			Credor:   p.Credor,
+			Contrato: p.Contrato,
+			Valor:    valor,
+			Data:     data,
+		})
+	}
+	if datas.err != nil {
+		return Restricoes{}, datas.err
+	}
+	restricoes.Avisos = datas.avisos
+	return restricoes, nil
+}
+
+func (d *defaultClient) consultarScore(ctx context.Context, servico Servico, url, documento string, errDocumento error) (Score, error) {
+	credenciais, err := d.credenciais(ctx)
+	if err != nil {
+		return Score{}, err
+	}
+	custo, err := d.usage.reservar(servico)
+	if err != nil {
+		return Score{}, err
+	}
+	score := Score{}
+	result, err := d.consultarDocumento(ctx, credenciais, url, documento, func() interface{} { return &scoreResult{} })
+	if err == nil {
+		score, err = parseScoreResult(result.(*scoreResult), errDocumento, &leitorDatas{estrito: d.datasEstritas})
+	}
+	d.usage.registrar(ctx, servico, custo, err)
+	return score, err
+}
+
+func (d *defaultClient) consultarRestricoes(ctx context.Context, servico Servico, url, documento string, errDocumento error) (Restricoes, error) {
+	credenciais, err := d.credenciais(ctx)
+	if err != nil {
+		return Restricoes{}, err
+	}
+	custo, err := d.usage.reservar(servico)
+	if err != nil {
+		return Restricoes{}, err
+	}
+	restricoes := Restricoes{}
+	result, err := d.consultarDocumento(ctx, credenciais, url, documento, func() interface{} { return &restricoesResult{} })
+	if err == nil {
+		restricoes, err = parseRestricoesResult(result.(*restricoesResult), errDocumento, &leitorDatas{estrito: d.datasEstritas})
+	}
+	d.usage.registrar(ctx, servico, custo, err)
+	return restricoes, err
+}
+
+func (d *defaultClient) ConsultarScoreCPF(ctx context.Context, cpf string) (Score, error) {
+	return d.consultarScore(ctx, ServicoScoreCPF, urlScoreCPF, cpf, ErrCPFInvalido)
+}
+
+func (d *defaultClient) ConsultarScoreCNPJ(ctx context.Context, cnpj string) (Score, error) {
+	return d.consultarScore(ctx, ServicoScoreCNPJ, urlScoreCNPJ, cnpj, ErrCNPJInvalido)
+}
+
+// ConsultarRestricoesCPF returns the protests, bounced checks and negative
+// records of the given CPF. A CPF without restrictions is not an error, but
+// an empty Restricoes.
+func (d *defaultClient) ConsultarRestricoesCPF(ctx context.Context, cpf string) (Restricoes, error) {
+	return d.consultarRestricoes(ctx, ServicoRestricoesCPF, urlRestricoesCPF, cpf, ErrCPFInvalido)
+}
+
+// ConsultarRestricoesCNPJ returns the protests, bounced checks and negative
+// records of the given CNPJ. A CNPJ without restrictions is not an error, but
+// an empty Restricoes.
+func (d *defaultClient) ConsultarRestricoesCNPJ(ctx context.Context, cnpj string) (Restricoes, error) {
+	return d.consultarRestricoes(ctx, ServicoRestricoesCNPJ, urlRestricoesCNPJ, cnpj, ErrCNPJInvalido)
+}
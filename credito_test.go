@@ -0,0 +1,114 @@
+package soawebservices_test
+
+import (
+	"context"
+	"errors"
+	"github.com/diegohordi/soawebservices"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_defaultClient_ConsultarScoreCPF(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		want    soawebservices.Score
+		wantErr error
+	}{
+		{
+			name: "should return the score",
+			file: "consultascore_success.json",
+			want: soawebservices.Score{
+				Documento:                  "52998224725",
+				Pontuacao:                  742,
+				Faixa:                      "B",
+				ProbabilidadeInadimplencia: 3.25,
+				Data:                       time.Date(2022, 1, 10, 0, 0, 0, 0, soawebservices.SaoPaulo),
+			},
+		},
+		{
+			name:    "should fail due to the given invalid CPF",
+			file:    "consultacpf_invalid_cpf.json",
+			wantErr: soawebservices.ErrCPFInvalido,
+		},
+		{
+			name:    "should fail due to wrong credentials",
+			file:    "consultascore_wrong_credentials.json",
+			wantErr: soawebservices.ErrCredenciaisInvalidas,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			client := MustCreateClient(fixtureClient(t, tt.file))
+			result, err := client.ConsultarScoreCPF(context.TODO(), "529.982.247-25")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if !reflect.DeepEqual(result, tt.want) {
+				t.Error("want ", tt.want, " but got ", result)
+			}
+		})
+	}
+}
+
+func Test_defaultClient_ConsultarRestricoesCNPJ(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		want    soawebservices.Restricoes
+		wantErr error
+	}{
+		{
+			name: "should return the restrictions",
+			file: "consultarestricoes_success.json",
+			want: soawebservices.Restricoes{
+				Documento: "99999999999962",
+				Protestos: []soawebservices.Protesto{{
+					Cartorio: "1 TABELIAO DE PROTESTO DE LETRAS E TITULOS",
+					Cidade:   "SAO PAULO",
+					UF:       "SP",
+					Valor:    soawebservices.Reais(1500, 0),
+					Data:     time.Date(2021, 3, 15, 0, 0, 0, 0, soawebservices.SaoPaulo),
+				}},
+				ChequesSemFundo: []soawebservices.ChequeSemFundo{{
+					Banco:            "001",
+					Agencia:          "1234",
+					Quantidade:       2,
+					UltimaOcorrencia: time.Date(2021, 7, 20, 0, 0, 0, 0, soawebservices.SaoPaulo),
+				}},
+				Pendencias: []soawebservices.Pendencia{{
+					Tipo:     "PEFIN",
+					Credor:   "BANCO DE TESTES S.A.",
+					Contrato: "000123456",
+					Valor:    soawebservices.Reais(350, 75),
+					Data:     time.Date(2021, 11, 1, 0, 0, 0, 0, soawebservices.SaoPaulo),
+				}},
+			},
+		},
+		{
+			name:    "should fail due to the given invalid CNPJ",
+			file:    "consultarestricoes_invalid_document.json",
+			wantErr: soawebservices.ErrCNPJInvalido,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			client := MustCreateClient(fixtureClient(t, tt.file))
+			result, err := client.ConsultarRestricoesCNPJ(context.TODO(), "99.999.999/9999-62")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if !reflect.DeepEqual(result, tt.want) {
+				t.Error("want ", tt.want, " but got ", result)
+			}
+			if tt.wantErr == nil && (!result.Possui() || result.ValorTotal() != soawebservices.Reais(1850, 75)) {
+				t.Errorf("unexpected totals of %+v", result)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package soawebservices
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ErrDataInvalida   = Error("data inválida")
+	ErrNumeroInvalido = Error("número inválido")
+)
+
+// SaoPaulo is the location of the dates returned by the SOA WebServices. It
+// falls back to the fixed UTC-3 offset when the tz database is unavailable.
+var SaoPaulo = carregarSaoPaulo()
+
+func carregarSaoPaulo() *time.Location {
+	loc, err := time.LoadLocation("America/Sao_Paulo")
+	if err != nil {
+		return time.FixedZone("-03", -3*60*60)
+	}
+	return loc
+}
+
+// layoutsData are the formats of the dates returned by the SOA WebServices.
+var layoutsData = []string{
+	"02/01/2006",
+	"02/01/2006 15:04:05",
+	"02/01/2006 15:04",
+	"2006-01-02",
+	"2006-01-02T15:04:05",
+	time.RFC3339Nano,
+}
+
+// ParseData parses a date in any of the formats returned by the SOA
+// WebServices, as "02/01/2006", "02/01/2006 15:04:05", "2006-01-02" or
+// RFC3339, in the SaoPaulo location unless the date has an offset. An empty
+// string parses as the zero time, and any other value in an unknown format
+// fails with ErrDataInvalida.
+func ParseData(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, nil
+	}
+	for _, layout := range layoutsData {
+		if t, err := time.ParseInLocation(layout, s, SaoPaulo); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%w: %q", ErrDataInvalida, s)
+}
+
+// Data is a date of a response of the SOA WebServices. Decoding never fails
+// on malformed dates so the whole response is not lost: Texto keeps the value
+// received and Err tells whether it could be parsed.
+type Data struct {
+	time.Time
+	Texto string
+	err   error
+}
+
+// Err returns the error of parsing the date, if any.
+func (d Data) Err() error {
+	return d.err
+}
+
+func (d *Data) UnmarshalJSON(b []byte) error {
+	var s *string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	*d = Data{}
+	if s != nil {
+		d.Texto = *s
+		d.Time, d.err = ParseData(*s)
+	}
+	return nil
+}
+
+// leitorDatas reads the dates and the numbers of a response. In strict mode
+// the first malformed field fails the response, otherwise the malformed
+// fields are read as the zero value and reported as Avisos.
+type leitorDatas struct {
+	estrito bool
+	avisos  []string
+	err     error
+}
+
+func (l *leitorDatas) ler(campo string, d Data) time.Time {
+	if d.err != nil {
+		l.invalido(campo, d.err)
+		return time.Time{}
+	}
+	return d.Time
+}
+
+// lerDecimal reads a decimal number, with a comma or a dot as the decimal
+// separator. An empty field reads as 0.
+func (l *leitorDatas) lerDecimal(campo, s string) float64 {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0
+	}
+	f, err := strconv.ParseFloat(strings.Replace(s, ",", ".", 1), 64)
+	if err != nil {
+		l.invalido(campo, fmt.Errorf("%w: %q", ErrNumeroInvalido, s))
+		return 0
+	}
+	return f
+}
+
+// lerInteiro reads an integer number, as a year. An empty field reads as 0.
+func (l *leitorDatas) lerInteiro(campo, s string) int {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0
+	}
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		l.invalido(campo, fmt.Errorf("%w: %q", ErrNumeroInvalido, s))
+		return 0
+	}
+	return i
+}
+
+func (l *leitorDatas) invalido(campo string, err error) {
+	if l.estrito {
+		if l.err == nil {
+			l.err = fmt.Errorf("invalid %s: %w", campo, err)
+		}
+		return
+	}
+	l.avisos = append(l.avisos, fmt.Sprintf("%s: %v", campo, err))
+}
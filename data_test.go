@@ -0,0 +1,254 @@
+package soawebservices_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/diegohordi/soawebservices"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseData(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    time.Time
+		wantErr error
+	}{
+		{
+			name: "should parse a date",
+			s:    "02/05/2007",
+			want: time.Date(2007, 5, 2, 0, 0, 0, 0, soawebservices.SaoPaulo),
+		},
+		{
+			name: "should parse a date and time",
+			s:    "06/12/2021 18:55:19",
+			want: time.Date(2021, 12, 6, 18, 55, 19, 0, soawebservices.SaoPaulo),
+		},
+		{
+			name: "should parse an ISO date",
+			s:    "2021-12-06",
+			want: time.Date(2021, 12, 6, 0, 0, 0, 0, soawebservices.SaoPaulo),
+		},
+		{
+			name: "should parse a RFC3339 date and time",
+			s:    "2021-12-06T18:55:19.7417597-03:00",
+			want: time.Date(2021, 12, 6, 18, 55, 19, 741759700, soawebservices.SaoPaulo),
+		},
+		{
+			name: "should parse an empty date as the zero time",
+			s:    " ",
+		},
+		{
+			name:    "should fail due to a date that does not exist",
+			s:       "31/02/2007",
+			wantErr: soawebservices.ErrDataInvalida,
+		},
+		{
+			name:    "should fail due to an unknown format",
+			s:       "06-12-2021 18h55",
+			wantErr: soawebservices.ErrDataInvalida,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := soawebservices.ParseData(tt.s)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestData_UnmarshalJSON(t *testing.T) {
+	var result struct {
+		Valida   soawebservices.Data
+		Invalida soawebservices.Data
+		Nula     soawebservices.Data
+	}
+	err := json.Unmarshal([]byte(`{"Valida": "02/05/2007", "Invalida": "31/02/2007", "Nula": null}`), &result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2007, 5, 2, 0, 0, 0, 0, soawebservices.SaoPaulo); !result.Valida.Equal(want) || result.Valida.Err() != nil {
+		t.Errorf("Valida got = %v (%v), want %v", result.Valida.Time, result.Valida.Err(), want)
+	}
+	if !result.Invalida.IsZero() || result.Invalida.Texto != "31/02/2007" || !errors.Is(result.Invalida.Err(), soawebservices.ErrDataInvalida) {
+		t.Errorf("Invalida got = %v %q (%v)", result.Invalida.Time, result.Invalida.Texto, result.Invalida.Err())
+	}
+	if !result.Nula.IsZero() || result.Nula.Err() != nil {
+		t.Errorf("Nula got = %v (%v)", result.Nula.Time, result.Nula.Err())
+	}
+}
+
+func Test_defaultClient_ConsultarCNPJ_datas(t *testing.T) {
+	httpClient := &http.Client{
+		Transport: RoundTripFunc(func(req *http.Request) *http.Response {
+			resp := httptest.NewRecorder()
+			resp.Body.Write(MustLoadTestDataFile(t, "consultacnpj_invalid_datas.json"))
+			return resp.Result()
+		}),
+		Timeout: 5 * time.Second,
+	}
+	credenciais := soawebservices.Credenciais{Email: "test@test.com", Senha: "test"}
+	tests := []struct {
+		name       string
+		opts       []soawebservices.ClientOption
+		wantAvisos []string
+		wantErr    error
+	}{
+		{
+			name: "should report the malformed dates as avisos",
+			wantAvisos: []string{
+				`DataFundacao: data inválida: "31/02/2007"`,
+				`DataConsultaRFB: data inválida: "06-12-2021 18h55"`,
+			},
+		},
+		{
+			name:    "should fail due to the malformed dates in strict mode",
+			opts:    []soawebservices.ClientOption{soawebservices.WithDatasEstritas()},
+			wantErr: soawebservices.ErrDataInvalida,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			client, err := soawebservices.NewClient(httpClient, "https://soawebservices.com.br", soawebservices.TestDrive, credenciais, tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			pj, err := client.ConsultarCNPJ(context.TODO(), "88.888.888/8888-88")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if !reflect.DeepEqual(pj.Avisos, tt.wantAvisos) {
+				t.Errorf("Avisos got = %q, want %q", pj.Avisos, tt.wantAvisos)
+			}
+			if err == nil && !pj.DataFundacao.IsZero() {
+				t.Errorf("DataFundacao got = %v, want the zero time", pj.DataFundacao)
+			}
+		})
+	}
+}
+
+func Test_defaultClient_datas(t *testing.T) {
+	tests := []struct {
+		name       string
+		file       string
+		consultar  func(client soawebservices.Client) ([]string, error)
+		wantAvisos []string
+		wantErr    error
+	}{
+		{
+			name: "should report the malformed date of the score",
+			file: "consultascore_invalid_datas.json",
+			consultar: func(client soawebservices.Client) ([]string, error) {
+				score, err := client.ConsultarScoreCPF(context.TODO(), "529.982.247-25")
+				return score.Avisos, err
+			},
+			wantAvisos: []string{`DataConsulta: data inválida: "10/13/2022"`},
+			wantErr:    soawebservices.ErrDataInvalida,
+		},
+		{
+			name: "should report the malformed probability of default of the score",
+			file: "consultascore_invalid_probabilidade.json",
+			consultar: func(client soawebservices.Client) ([]string, error) {
+				score, err := client.ConsultarScoreCPF(context.TODO(), "529.982.247-25")
+				if score.ProbabilidadeInadimplencia != 0 {
+					return nil, fmt.Errorf("unexpected ProbabilidadeInadimplencia %v", score.ProbabilidadeInadimplencia)
+				}
+				return score.Avisos, err
+			},
+			wantAvisos: []string{`ProbabilidadeInadimplencia: número inválido: "3,2%5"`},
+			wantErr:    soawebservices.ErrNumeroInvalido,
+		},
+		{
+			name: "should report the malformed year of death",
+			file: "consultacpf_invalid_ano_obito.json",
+			consultar: func(client soawebservices.Client) ([]string, error) {
+				pf, err := client.ConsultarCPF(context.TODO(), "529.982.247-25", time.Time{})
+				if err == nil && pf.AnoObitoTexto != "20l9" {
+					return nil, fmt.Errorf("unexpected AnoObitoTexto %q", pf.AnoObitoTexto)
+				}
+				return pf.Avisos, err
+			},
+			wantAvisos: []string{`AnoObito: número inválido: "20l9"`},
+			wantErr:    soawebservices.ErrNumeroInvalido,
+		},
+		{
+			name: "should report the malformed year of the vehicle",
+			file: "consultaveiculo_invalid_anos.json",
+			consultar: func(client soawebservices.Client) ([]string, error) {
+				veiculo, err := client.ConsultarVeiculo(context.TODO(), "ABC1D23")
+				return veiculo.Avisos, err
+			},
+			wantAvisos: []string{`AnoModelo: número inválido: "20/20"`},
+			wantErr:    soawebservices.ErrNumeroInvalido,
+		},
+		{
+			name: "should report the malformed date of the Simples Nacional",
+			file: "consultasimplesnacional_invalid_datas.json",
+			consultar: func(client soawebservices.Client) ([]string, error) {
+				simples, err := client.ConsultarSimplesNacional(context.TODO(), "99.999.999/9999-62")
+				return simples.Avisos, err
+			},
+			wantAvisos: []string{`Periodos[1].DataFinal: data inválida: "31/12/19"`},
+			wantErr:    soawebservices.ErrDataInvalida,
+		},
+		{
+			name: "should report the malformed date of the Inscrição Estadual",
+			file: "consultainscricaoestadual_invalid_datas.json",
+			consultar: func(client soawebservices.Client) ([]string, error) {
+				ie, err := client.ConsultarInscricaoEstadual(context.TODO(), "SP", "110.042.490.114")
+				return ie.Avisos, err
+			},
+			wantAvisos: []string{`DataSituacao: data inválida: "05.02.2010"`},
+			wantErr:    soawebservices.ErrDataInvalida,
+		},
+		{
+			name: "should report the malformed date of the consumption",
+			file: "consultaconsumo_invalid_datas.json",
+			consultar: func(client soawebservices.Client) ([]string, error) {
+				consumos, err := client.ConsultarConsumo(context.TODO(), time.Time{}, time.Time{})
+				if len(consumos) == 0 {
+					return nil, err
+				}
+				return consumos[0].Avisos, err
+			},
+			wantAvisos: []string{`Consumos[0].Data: data inválida: "2021/12/01"`},
+			wantErr:    soawebservices.ErrDataInvalida,
+		},
+	}
+	credenciais := soawebservices.Credenciais{Email: "test@test.com", Senha: "test"}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			avisos, err := tt.consultar(MustCreateClient(fixtureClient(t, tt.file)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(avisos, tt.wantAvisos) {
+				t.Errorf("Avisos got = %q, want %q", avisos, tt.wantAvisos)
+			}
+			client, err := soawebservices.NewClient(fixtureClient(t, tt.file), "https://soawebservices.com.br", soawebservices.TestDrive, credenciais, soawebservices.WithDatasEstritas())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err = tt.consultar(client); !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -1,5 +1,10 @@
 package soawebservices
 
+import (
+	"fmt"
+	"sort"
+)
+
 type Error string
 
 func (e Error) Error() string {
@@ -8,9 +13,302 @@ func (e Error) Error() string {
 
 const (
 	StatusCredenciaisInvalidas = "G000M000"
+	StatusSucesso              = "G000M001"
+	StatusSemCreditos          = "G000M002"
 	StatusDocumentoInvalido    = "G000M003"
+	StatusFalhaTransacao       = "G000M004"
+	StatusServicoIndisponivel  = "G000M005"
+	StatusProdutoNaoHabilitado = "G000M006"
+	StatusContaBloqueada       = "G000M007"
+)
+
+const (
+	StatusPeriodoConsumoInvalido            = "P001M001"
+	StatusDataNascimentoObrigatoria         = "P009M001"
+	StatusDataNascimentoInvalida            = "P009M002"
+	StatusSimplesNacionalFalhaProcessamento = "P011M009"
+	StatusSimplesNacionalFonteIndisponivel  = "P011M010"
+	StatusUFInvalida                        = "P012M001"
+	StatusSintegraFalhaProcessamento        = "P012M009"
+	StatusSintegraFonteIndisponivel         = "P012M010"
+	StatusCEPInvalido                       = "P016M001"
+	StatusCEPNaoEncontrado                  = "P016M002"
+	StatusCEPFalhaProcessamento             = "P016M009"
+	StatusCEPServicoIndisponivel            = "P016M010"
+	StatusPlacaInvalida                     = "P021M001"
+	StatusVeiculoNaoEncontrado              = "P021M002"
+	StatusCreditoFalhaProcessamento         = "P030M009"
+	StatusCreditoFonteIndisponivel          = "P030M010"
+	StatusComplianceFalhaProcessamento      = "P040M009"
+	StatusComplianceFonteIndisponivel       = "P040M010"
+	StatusContatoFalhaProcessamento         = "P050M009"
+	StatusContatoFonteIndisponivel          = "P050M010"
 )
 
 const (
 	ErrCredenciaisInvalidas = Error("credenciais inválidas (G000M000)")
+	ErrContaBloqueada       = Error("conta bloqueada por pendência financeira (G000M007)")
+)
+
+// Categoria classifies the CodigoStatus of the SOA WebServices by what the
+// caller should do about them.
+type Categoria string
+
+const (
+	CategoriaSucesso         Categoria = "sucesso"
+	CategoriaEntrada         Categoria = "entrada"
+	CategoriaAutenticacao    Categoria = "autenticacao"
+	CategoriaDisponibilidade Categoria = "disponibilidade"
+	CategoriaCobranca        Categoria = "cobranca"
+	CategoriaNaoEncontrado   Categoria = "nao_encontrado"
+	CategoriaDesconhecida    Categoria = "desconhecida"
 )
+
+// StatusInfo describes a CodigoStatus of the SOA WebServices. Retentavel
+// tells whether the same request may succeed if retried later, and Gratuito
+// whether the calls failed with it are known not to be billed.
+type StatusInfo struct {
+	Codigo     string
+	Descricao  string
+	Categoria  Categoria
+	Retentavel bool
+	Gratuito   bool
+	err        error
+}
+
+// catalogo holds the CodigoStatus returned by the SOA WebServices: the
+// general G codes, shared by every service, and the P codes of each product.
+// The errors of the codes whose meaning depends on the service, such as
+// G000M003, are given by the service.
+var catalogo = map[string]StatusInfo{
+	StatusCredenciaisInvalidas: {
+		Descricao: "credenciais de acesso (usuário e/ou senha) inválidas",
+		Categoria: CategoriaAutenticacao,
+		Gratuito:  true,
+		err:       ErrCredenciaisInvalidas,
+	},
+	StatusSucesso: {
+		Descricao: "transação realizada com sucesso",
+		Categoria: CategoriaSucesso,
+	},
+	StatusSemCreditos: {
+		Descricao: "usuário sem créditos para realizar a consulta",
+		Categoria: CategoriaCobranca,
+		Gratuito:  true,
+		err:       ErrSaldoInsuficiente,
+	},
+	StatusDocumentoInvalido: {
+		Descricao: "documento inválido para consulta",
+		Categoria: CategoriaEntrada,
+	},
+	StatusFalhaTransacao: {
+		Descricao:  "não foi possível realizar a transação",
+		Categoria:  CategoriaDisponibilidade,
+		Retentavel: true,
+	},
+	StatusServicoIndisponivel: {
+		Descricao:  "serviço temporariamente indisponível",
+		Categoria:  CategoriaDisponibilidade,
+		Retentavel: true,
+	},
+	StatusProdutoNaoHabilitado: {
+		Descricao: "produto não habilitado para o usuário",
+		Categoria: CategoriaAutenticacao,
+		Gratuito:  true,
+	},
+	StatusContaBloqueada: {
+		Descricao: "conta bloqueada por pendência financeira",
+		Categoria: CategoriaCobranca,
+		Gratuito:  true,
+		err:       ErrContaBloqueada,
+	},
+	StatusPeriodoConsumoInvalido: {
+		Descricao: "período de consumo inválido",
+		Categoria: CategoriaEntrada,
+	},
+	StatusDataNascimentoObrigatoria: {
+		Descricao: "data de nascimento obrigatória",
+		Categoria: CategoriaEntrada,
+		err:       ErrDataNascimentoObrigatoria,
+	},
+	StatusDataNascimentoInvalida: {
+		Descricao: "data de nascimento inválida",
+		Categoria: CategoriaEntrada,
+		err:       ErrDataNascimentoInvalida,
+	},
+	StatusSimplesNacionalFalhaProcessamento: {
+		Descricao:  "ocorreu um erro e não foi possível realizar a consulta",
+		Categoria:  CategoriaDisponibilidade,
+		Retentavel: true,
+	},
+	StatusSimplesNacionalFonteIndisponivel: {
+		Descricao:  "fonte do simples nacional indisponível no momento",
+		Categoria:  CategoriaDisponibilidade,
+		Retentavel: true,
+	},
+	StatusUFInvalida: {
+		Descricao: "uf inválida",
+		Categoria: CategoriaEntrada,
+	},
+	StatusSintegraFalhaProcessamento: {
+		Descricao:  "ocorreu um erro e não foi possível realizar a consulta",
+		Categoria:  CategoriaDisponibilidade,
+		Retentavel: true,
+	},
+	StatusSintegraFonteIndisponivel: {
+		Descricao:  "sintegra da uf indisponível no momento",
+		Categoria:  CategoriaDisponibilidade,
+		Retentavel: true,
+	},
+	StatusCEPInvalido: {
+		Descricao: "tamanho do cep informado é inválido",
+		Categoria: CategoriaEntrada,
+		err:       ErrCEPInvalido,
+	},
+	StatusCEPNaoEncontrado: {
+		Descricao: "cep não foi encontrado",
+		Categoria: CategoriaNaoEncontrado,
+	},
+	StatusCEPFalhaProcessamento: {
+		Descricao:  "ocorreu um erro e não foi possível realizar a consulta",
+		Categoria:  CategoriaDisponibilidade,
+		Retentavel: true,
+		err:        ErrCEPFalhaTransacao,
+	},
+	StatusCEPServicoIndisponivel: {
+		Descricao:  "serviço dos correios indisponível no momento",
+		Categoria:  CategoriaDisponibilidade,
+		Retentavel: true,
+		err:        ErrCEPServicoIndisponivel,
+	},
+	StatusPlacaInvalida: {
+		Descricao: "placa inválida",
+		Categoria: CategoriaEntrada,
+		err:       ErrPlacaInvalida,
+	},
+	StatusVeiculoNaoEncontrado: {
+		Descricao: "veículo não foi encontrado",
+		Categoria: CategoriaNaoEncontrado,
+	},
+	StatusCreditoFalhaProcessamento: {
+		Descricao:  "ocorreu um erro e não foi possível realizar a consulta",
+		Categoria:  CategoriaDisponibilidade,
+		Retentavel: true,
+	},
+	StatusCreditoFonteIndisponivel: {
+		Descricao:  "fonte de crédito indisponível no momento",
+		Categoria:  CategoriaDisponibilidade,
+		Retentavel: true,
+	},
+	StatusComplianceFalhaProcessamento: {
+		Descricao:  "ocorreu um erro e não foi possível realizar a consulta",
+		Categoria:  CategoriaDisponibilidade,
+		Retentavel: true,
+	},
+	StatusComplianceFonteIndisponivel: {
+		Descricao:  "fonte de compliance indisponível no momento",
+		Categoria:  CategoriaDisponibilidade,
+		Retentavel: true,
+	},
+	StatusContatoFalhaProcessamento: {
+		Descricao:  "ocorreu um erro e não foi possível realizar a consulta",
+		Categoria:  CategoriaDisponibilidade,
+		Retentavel: true,
+	},
+	StatusContatoFonteIndisponivel: {
+		Descricao:  "fonte de contatos indisponível no momento",
+		Categoria:  CategoriaDisponibilidade,
+		Retentavel: true,
+	},
+}
+
+// ConsultarStatus returns the description of the given CodigoStatus.
+func ConsultarStatus(codigo string) (StatusInfo, bool) {
+	info, ok := catalogo[codigo]
+	info.Codigo = codigo
+	return info, ok
+}
+
+// Status returns the descriptions of every CodigoStatus known, sorted by code.
+func Status() []StatusInfo {
+	status := make([]StatusInfo, 0, len(catalogo))
+	for codigo := range catalogo {
+		info, _ := ConsultarStatus(codigo)
+		status = append(status, info)
+	}
+	sort.Slice(status, func(i, j int) bool {
+		return status[i].Codigo < status[j].Codigo
+	})
+	return status
+}
+
+// StatusError is the error of a transaction that failed with a CodigoStatus.
+// Descricao is the description returned by the SOA WebServices. Codes missing
+// from the catalog have CategoriaDesconhecida. It unwraps to the error of the
+// code, if any, so errors.Is(err, ErrCredenciaisInvalidas) still holds.
+type StatusError struct {
+	Codigo     string
+	Descricao  string
+	Categoria  Categoria
+	Retentavel bool
+	err        error
+}
+
+func (e *StatusError) Error() string {
+	if e.err != nil {
+		return e.err.Error()
+	}
+	return fmt.Sprintf("%s: %s", e.Codigo, e.Descricao)
+}
+
+func (e *StatusError) Unwrap() error {
+	return e.err
+}
+
+// NewStatusError returns the StatusError of the given CodigoStatus, as the
+// client does, so fakes can fail like the SOA WebServices.
+func NewStatusError(codigo, descricao string) *StatusError {
+	return newStatusError(transacao{CodigoStatus: codigo, CodigoStatusDescricao: descricao}, nil)
+}
+
+// newStatusError returns the StatusError of the given transaction, unwrapping
+// to err or, if nil, to the error of the code in the catalog.
+func newStatusError(t transacao, err error) *StatusError {
+	info, ok := catalogo[t.CodigoStatus]
+	if !ok {
+		info.Categoria = CategoriaDesconhecida
+	}
+	if err == nil {
+		err = info.err
+	}
+	return &StatusError{
+		Codigo:     t.CodigoStatus,
+		Descricao:  t.CodigoStatusDescricao,
+		Categoria:  info.Categoria,
+		Retentavel: info.Retentavel,
+		err:        err,
+	}
+}
+
+// parseTransacao maps the status of an operation to its StatusError. Known
+// codes other than success always fail, while unknown ones fail only if the
+// operation did.
+func parseTransacao(status bool, t transacao) error {
+	info, ok := catalogo[t.CodigoStatus]
+	switch {
+	case ok && info.Categoria == CategoriaSucesso:
+		return nil
+	case ok, !status:
+		return newStatusError(t, nil)
+	}
+	return nil
+}
+
+// parseDocumentoTransacao maps the status of the operations on a CPF or a
+// CNPJ to their errors, failing with errDocumento if the document is invalid.
+func parseDocumentoTransacao(status bool, t transacao, errDocumento error) error {
+	if t.CodigoStatus == StatusDocumentoInvalido {
+		return newStatusError(t, errDocumento)
+	}
+	return parseTransacao(status, t)
+}
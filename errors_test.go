@@ -0,0 +1,173 @@
+package soawebservices_test
+
+import (
+	"context"
+	"errors"
+	"github.com/diegohordi/soawebservices"
+	"testing"
+	"time"
+)
+
+func TestStatus(t *testing.T) {
+	status := soawebservices.Status()
+	if len(status) == 0 {
+		t.Fatal("expected a catalog of CodigoStatus")
+	}
+	for i, s := range status {
+		if i > 0 && status[i-1].Codigo >= s.Codigo {
+			t.Errorf("expected the catalog sorted by code, got %s after %s", s.Codigo, status[i-1].Codigo)
+		}
+		if s.Descricao == "" || s.Categoria == "" {
+			t.Errorf("expected %s to be described and categorized, got %+v", s.Codigo, s)
+		}
+		if got, ok := soawebservices.ConsultarStatus(s.Codigo); !ok || got != s {
+			t.Errorf("ConsultarStatus(%s) got = %+v, want %+v", s.Codigo, got, s)
+		}
+	}
+	if _, ok := soawebservices.ConsultarStatus("G999M999"); ok {
+		t.Error("expected G999M999 not to be catalogued")
+	}
+}
+
+func TestStatus_categorias(t *testing.T) {
+	categorias := map[soawebservices.Categoria]int{}
+	for _, s := range soawebservices.Status() {
+		categorias[s.Categoria]++
+	}
+	// CategoriaDesconhecida is the category of the codes missing from the
+	// catalog, so no code in it has it.
+	tests := []struct {
+		categoria soawebservices.Categoria
+		want      bool
+	}{
+		{categoria: soawebservices.CategoriaSucesso, want: true},
+		{categoria: soawebservices.CategoriaEntrada, want: true},
+		{categoria: soawebservices.CategoriaAutenticacao, want: true},
+		{categoria: soawebservices.CategoriaDisponibilidade, want: true},
+		{categoria: soawebservices.CategoriaCobranca, want: true},
+		{categoria: soawebservices.CategoriaNaoEncontrado, want: true},
+		{categoria: soawebservices.CategoriaDesconhecida, want: false},
+	}
+	for _, tt := range tests {
+		if got := categorias[tt.categoria] > 0; got != tt.want {
+			t.Errorf("expected the category %s to have codes: %v, got %d codes", tt.categoria, tt.want, categorias[tt.categoria])
+		}
+	}
+}
+
+func TestStatusError(t *testing.T) {
+	tests := []struct {
+		name           string
+		consultar      func(client soawebservices.Client) error
+		file           string
+		wantCodigo     string
+		wantCategoria  soawebservices.Categoria
+		wantRetentavel bool
+		wantErr        error
+	}{
+		{
+			name: "should map the wrong credentials of the CNPJ",
+			consultar: func(client soawebservices.Client) error {
+				_, err := client.ConsultarCNPJ(context.TODO(), "99.999.999/9999-62")
+				return err
+			},
+			file:          "consultacnpj_wrong_credentials.json",
+			wantCodigo:    soawebservices.StatusCredenciaisInvalidas,
+			wantCategoria: soawebservices.CategoriaAutenticacao,
+			wantErr:       soawebservices.ErrCredenciaisInvalidas,
+		},
+		{
+			name: "should map the invalid CPF",
+			consultar: func(client soawebservices.Client) error {
+				_, err := client.ConsultarCPF(context.TODO(), "999.999.999-99", time.Now())
+				return err
+			},
+			file:          "consultacpf_invalid_cpf.json",
+			wantCodigo:    soawebservices.StatusDocumentoInvalido,
+			wantCategoria: soawebservices.CategoriaEntrada,
+			wantErr:       soawebservices.ErrCPFInvalido,
+		},
+		{
+			name: "should map the required data de nascimento",
+			consultar: func(client soawebservices.Client) error {
+				_, err := client.ConsultarCPF(context.TODO(), "999.999.999-99", time.Now())
+				return err
+			},
+			file:          "consultacpf_required_data_nascimento.json",
+			wantCodigo:    soawebservices.StatusDataNascimentoObrigatoria,
+			wantCategoria: soawebservices.CategoriaEntrada,
+			wantErr:       soawebservices.ErrDataNascimentoObrigatoria,
+		},
+		{
+			name: "should map the unavailable CEP service as retryable",
+			consultar: func(client soawebservices.Client) error {
+				_, err := client.ConsultarCEP(context.TODO(), "01001-000")
+				return err
+			},
+			file:           "consultacep_service_unavailable.xml",
+			wantCodigo:     soawebservices.StatusCEPServicoIndisponivel,
+			wantCategoria:  soawebservices.CategoriaDisponibilidade,
+			wantRetentavel: true,
+			wantErr:        soawebservices.ErrCEPServicoIndisponivel,
+		},
+		{
+			name: "should map the wrong credentials of the balance",
+			consultar: func(client soawebservices.Client) error {
+				_, err := client.ConsultarSaldo(context.TODO())
+				return err
+			},
+			file:          "consultasaldo_wrong_credentials.json",
+			wantCodigo:    soawebservices.StatusCredenciaisInvalidas,
+			wantCategoria: soawebservices.CategoriaAutenticacao,
+			wantErr:       soawebservices.ErrCredenciaisInvalidas,
+		},
+		{
+			name: "should map the lack of credits",
+			consultar: func(client soawebservices.Client) error {
+				_, err := client.ConsultarScoreCPF(context.TODO(), "999.999.999-99")
+				return err
+			},
+			file:          "consultascore_sem_creditos.json",
+			wantCodigo:    soawebservices.StatusSemCreditos,
+			wantCategoria: soawebservices.CategoriaCobranca,
+			wantErr:       soawebservices.ErrSaldoInsuficiente,
+		},
+		{
+			name: "should map an unknown CodigoStatus",
+			consultar: func(client soawebservices.Client) error {
+				_, err := client.ConsultarCEP(context.TODO(), "01001-000")
+				return err
+			},
+			file:          "consultacep_unknown_server_error.xml",
+			wantCodigo:    "XXXXXXXXX",
+			wantCategoria: soawebservices.CategoriaDesconhecida,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.consultar(MustCreateClient(fixtureClient(t, tt.file)))
+			var statusErr *soawebservices.StatusError
+			if !errors.As(err, &statusErr) {
+				t.Fatalf("expected a StatusError, got %v", err)
+			}
+			if statusErr.Codigo != tt.wantCodigo || statusErr.Categoria != tt.wantCategoria || statusErr.Retentavel != tt.wantRetentavel {
+				t.Errorf("unexpected StatusError %+v", statusErr)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestNewStatusError(t *testing.T) {
+	err := soawebservices.NewStatusError(soawebservices.StatusPlacaInvalida, "Placa invalida")
+	if !errors.Is(err, soawebservices.ErrPlacaInvalida) || err.Categoria != soawebservices.CategoriaEntrada {
+		t.Errorf("unexpected StatusError %+v", err)
+	}
+	if got := soawebservices.NewStatusError("G999M999", "Erro desconhecido").Error(); got != "G999M999: Erro desconhecido" {
+		t.Errorf("Error() got = %q", got)
+	}
+}
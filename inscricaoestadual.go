@@ -0,0 +1,134 @@
+package soawebservices
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/diegohordi/soawebservices/internal/documento"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	urlInscricaoEstadual = "sintegra/sintegra.ashx"
+)
+
+const (
+	ErrInscricaoEstadualInvalida = Error("a inscrição estadual informada é inválida")
+)
+
+// InscricaoEstadualValida reports whether the given Inscrição Estadual,
+// formatted or not, has valid check digits for the given UF, following the
+// algorithm of each state. The ones of the produtores rurais of SP start with
+// a P.
+func InscricaoEstadualValida(uf, ie string) bool {
+	return documento.InscricaoEstadualValida(uf, ie)
+}
+
+func (d *defaultClient) buildConsultaInscricaoEstadualRequestBody(credenciais Credenciais, uf, doc string) (io.Reader, error) {
+	buf, err := json.Marshal(newConsultaInscricaoEstadual(credenciais, uf, doc))
+	if err != nil {
+		return nil, fmt.Errorf("an error occurred while build the request body: %w", err)
+	}
+	return bytes.NewBuffer(buf), err
+}
+
+func (d *defaultClient) parseConsultaInscricaoEstadualResponseBody(resp *http.Response, errDocumento error) (InscricaoEstadual, error) {
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+	result := inscricaoEstadualResult{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return InscricaoEstadual{}, err
+	}
+	if err := parseDocumentoTransacao(result.Status, result.Transacao, errDocumento); err != nil {
+		return InscricaoEstadual{}, err
+	}
+	datas := leitorDatas{estrito: d.datasEstritas}
+	ie := InscricaoEstadual{
+		UF:                  result.UF,
+		Inscricao:           result.InscricaoEstadual,
+		CNPJ:                result.CNPJ,
+		RazaoSocial:         result.RazaoSocial,
+		Situacao:            SituacaoIE(result.Situacao),
+		RegimeApuracao:      result.RegimeApuracao,
+		DataInicioAtividade: datas.ler("DataInicioAtividade", result.DataInicioAtividade),
+		DataHabilitacao:     datas.ler("DataHabilitacao", result.DataHabilitacao),
+		DataSituacao:        datas.ler("DataSituacao", result.DataSituacao),
+		Avisos:              datas.avisos,
+	}
+	if datas.err != nil {
+		return InscricaoEstadual{}, datas.err
+	}
+	return ie, nil
+}
+
+// ConsultarInscricaoEstadual returns the Inscrição Estadual in the given UF of
+// the given document, either the Inscrição Estadual itself or the CNPJ of the
+// company. Documents that are neither a valid Inscrição Estadual of the UF nor
+// a valid CNPJ fail with ErrInscricaoEstadualInvalida without calling the
+// service. The Inscrição Estadual of a PessoaJuridica is looked up by passing
+// its Documento.
+func (d *defaultClient) ConsultarInscricaoEstadual(ctx context.Context, uf, doc string) (InscricaoEstadual, error) {
+	uf = strings.ToUpper(strings.TrimSpace(uf))
+	if !UFValida(uf) {
+		return InscricaoEstadual{}, ErrUFInvalida
+	}
+	errDocumento := error(ErrInscricaoEstadualInvalida)
+	switch {
+	case InscricaoEstadualValida(uf, doc):
+		prefixo := ""
+		if strings.HasPrefix(strings.ToUpper(strings.TrimSpace(doc)), "P") {
+			prefixo = "P"
+		}
+		doc = prefixo + documento.SomenteDigitos(doc)
+	case documento.CNPJValido(doc):
+		doc, errDocumento = documento.SomenteDigitos(doc), ErrCNPJInvalido
+	default:
+		return InscricaoEstadual{}, ErrInscricaoEstadualInvalida
+	}
+	credenciais, err := d.credenciais(ctx)
+	if err != nil {
+		return InscricaoEstadual{}, err
+	}
+	custo, err := d.usage.reservar(ServicoInscricaoEstadual)
+	if err != nil {
+		return InscricaoEstadual{}, err
+	}
+	result, err := d.consultarInscricaoEstadual(ctx, credenciais, uf, doc, errDocumento)
+	d.usage.registrar(ctx, ServicoInscricaoEstadual, custo, err)
+	return result, err
+}
+
+func (d *defaultClient) consultarInscricaoEstadual(ctx context.Context, credenciais Credenciais, uf, doc string, errDocumento error) (InscricaoEstadual, error) {
+	errChan := make(chan error, 1)
+	resultChan := make(chan InscricaoEstadual, 1)
+	requestBody, err := d.buildConsultaInscricaoEstadualRequestBody(credenciais, uf, doc)
+	if err != nil {
+		return InscricaoEstadual{}, err
+	}
+	go func() {
+		serviceURL := fmt.Sprintf("%s/restservices/%s/%s", d.baseURL, d.ambiente, urlInscricaoEstadual)
+		resp, err := d.httpClient.Post(serviceURL, "application/json", requestBody)
+		if err != nil {
+			errChan <- err
+			return
+		}
+		result, err := d.parseConsultaInscricaoEstadualResponseBody(resp, errDocumento)
+		if err != nil {
+			errChan <- err
+			return
+		}
+		resultChan <- result
+	}()
+	select {
+	case err = <-errChan:
+		return InscricaoEstadual{}, err
+	case <-ctx.Done():
+		return InscricaoEstadual{}, ctx.Err()
+	case result := <-resultChan:
+		return result, nil
+	}
+}
@@ -0,0 +1,104 @@
+package soawebservices_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/diegohordi/soawebservices"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_defaultClient_ConsultarInscricaoEstadual(t *testing.T) {
+	habilitada := soawebservices.InscricaoEstadual{
+		UF:                  "SP",
+		Inscricao:           "110042490114",
+		CNPJ:                "99999999999962",
+		RazaoSocial:         "EMPRESA DE TESTES LTDA",
+		Situacao:            soawebservices.IEHabilitada,
+		RegimeApuracao:      "NORMAL - REGIME PERIÓDICO DE APURAÇÃO",
+		DataInicioAtividade: time.Date(2010, 2, 5, 0, 0, 0, 0, soawebservices.SaoPaulo),
+		DataHabilitacao:     time.Date(2010, 2, 5, 0, 0, 0, 0, soawebservices.SaoPaulo),
+		DataSituacao:        time.Date(2010, 2, 5, 0, 0, 0, 0, soawebservices.SaoPaulo),
+	}
+	tests := []struct {
+		name          string
+		file          string
+		uf            string
+		documento     string
+		wantDocumento string
+		want          soawebservices.InscricaoEstadual
+		wantErr       error
+	}{
+		{
+			name:          "should return the Inscrição Estadual by itself",
+			file:          "consultainscricaoestadual_success.json",
+			uf:            "sp",
+			documento:     "110.042.490.114",
+			wantDocumento: "110042490114",
+			want:          habilitada,
+		},
+		{
+			name:          "should return the Inscrição Estadual by the CNPJ",
+			file:          "consultainscricaoestadual_success.json",
+			uf:            "SP",
+			documento:     "99.999.999/9999-62",
+			wantDocumento: "99999999999962",
+			want:          habilitada,
+		},
+		{
+			name:      "should fail due to the given invalid document",
+			file:      "consultainscricaoestadual_success.json",
+			uf:        "SP",
+			documento: "110.042.490.115",
+			wantErr:   soawebservices.ErrInscricaoEstadualInvalida,
+		},
+		{
+			name:      "should fail due to the given invalid UF",
+			file:      "consultainscricaoestadual_success.json",
+			uf:        "XX",
+			documento: "110.042.490.114",
+			wantErr:   soawebservices.ErrUFInvalida,
+		},
+		{
+			name:          "should fail due to wrong credentials",
+			file:          "consultacnpj_wrong_credentials.json",
+			uf:            "SP",
+			documento:     "110042490114",
+			wantDocumento: "110042490114",
+			wantErr:       soawebservices.ErrCredenciaisInvalidas,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var documento string
+			client := MustCreateClient(&http.Client{
+				Transport: RoundTripFunc(func(req *http.Request) *http.Response {
+					body := struct{ Documento string }{}
+					buf, _ := io.ReadAll(req.Body)
+					_ = json.Unmarshal(buf, &body)
+					documento = body.Documento
+					resp := httptest.NewRecorder()
+					resp.Body.Write(MustLoadTestDataFile(t, tt.file))
+					return resp.Result()
+				}),
+			})
+			result, err := client.ConsultarInscricaoEstadual(context.TODO(), tt.uf, tt.documento)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if documento != tt.wantDocumento {
+				t.Errorf("expected the document %q to be sent, got %q", tt.wantDocumento, documento)
+			}
+			if !reflect.DeepEqual(result, tt.want) {
+				t.Error("want ", tt.want, " but got ", result)
+			}
+		})
+	}
+}
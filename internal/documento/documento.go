@@ -0,0 +1,87 @@
+// Package documento implements the normalization and check digit rules of
+// the Brazilian CPF and CNPJ documents.
+package documento
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	TamanhoCPF  = 11
+	TamanhoCNPJ = 14
+)
+
+var (
+	pesosCNPJ = []int{6, 5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2}
+)
+
+func SomenteDigitos(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, s)
+}
+
+// DigitosCPF returns the two check digits of the given 9 digit CPF base.
+func DigitosCPF(base string) string {
+	primeiro := digitoCPF(base)
+	return primeiro + digitoCPF(base+primeiro)
+}
+
+// DigitosCNPJ returns the two check digits of the given 12 digit CNPJ base.
+func DigitosCNPJ(base string) string {
+	primeiro := digitoCNPJ(base)
+	return primeiro + digitoCNPJ(base+primeiro)
+}
+
+// CPFValido reports whether the given CPF, formatted or not, has valid check
+// digits. Sequences of the same digit are rejected.
+func CPFValido(cpf string) bool {
+	cpf = SomenteDigitos(cpf)
+	if len(cpf) != TamanhoCPF || repetido(cpf) {
+		return false
+	}
+	return DigitosCPF(cpf[:9]) == cpf[9:]
+}
+
+// CNPJValido reports whether the given CNPJ, formatted or not, has valid check
+// digits. Sequences of the same digit are rejected.
+func CNPJValido(cnpj string) bool {
+	cnpj = SomenteDigitos(cnpj)
+	if len(cnpj) != TamanhoCNPJ || repetido(cnpj) {
+		return false
+	}
+	return DigitosCNPJ(cnpj[:12]) == cnpj[12:]
+}
+
+func digitoCPF(base string) string {
+	soma := 0
+	for i, r := range base {
+		soma += int(r-'0') * (len(base) + 1 - i)
+	}
+	return modulo11(soma)
+}
+
+func digitoCNPJ(base string) string {
+	pesos := pesosCNPJ[len(pesosCNPJ)-len(base):]
+	soma := 0
+	for i, r := range base {
+		soma += int(r-'0') * pesos[i]
+	}
+	return modulo11(soma)
+}
+
+func modulo11(soma int) string {
+	resto := soma % 11
+	if resto < 2 {
+		return "0"
+	}
+	return string(rune('0' + 11 - resto))
+}
+
+func repetido(s string) bool {
+	return strings.Count(s, s[:1]) == len(s)
+}
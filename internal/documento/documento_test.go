@@ -0,0 +1,53 @@
+package documento_test
+
+import (
+	"github.com/diegohordi/soawebservices/internal/documento"
+	"testing"
+)
+
+func TestCPFValido(t *testing.T) {
+	tests := []struct {
+		cpf  string
+		want bool
+	}{
+		{cpf: "529.982.247-25", want: true},
+		{cpf: "52998224725", want: true},
+		{cpf: "529.982.247-24", want: false},
+		{cpf: "999.999.999-99", want: false},
+		{cpf: "5299822472", want: false},
+		{cpf: "", want: false},
+	}
+	for _, tt := range tests {
+		if got := documento.CPFValido(tt.cpf); got != tt.want {
+			t.Errorf("CPFValido(%q) = %v, want %v", tt.cpf, got, tt.want)
+		}
+	}
+}
+
+func TestCNPJValido(t *testing.T) {
+	tests := []struct {
+		cnpj string
+		want bool
+	}{
+		{cnpj: "39.621.470/0001-09", want: false},
+		{cnpj: "11222333000181", want: true},
+		{cnpj: "11.222.333/0001-80", want: false},
+		{cnpj: "99.999.999/9999-62", want: true},
+		{cnpj: "00000000000000", want: false},
+		{cnpj: "", want: false},
+	}
+	for _, tt := range tests {
+		if got := documento.CNPJValido(tt.cnpj); got != tt.want {
+			t.Errorf("CNPJValido(%q) = %v, want %v", tt.cnpj, got, tt.want)
+		}
+	}
+}
+
+func TestDigitos(t *testing.T) {
+	if got := documento.DigitosCPF("529982247"); got != "25" {
+		t.Errorf("DigitosCPF() = %s, want 25", got)
+	}
+	if got := documento.DigitosCNPJ("112223330001"); got != "81" {
+		t.Errorf("DigitosCNPJ() = %s, want 81", got)
+	}
+}
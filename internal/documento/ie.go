@@ -0,0 +1,336 @@
+package documento
+
+import (
+	"strconv"
+	"strings"
+)
+
+// regrasIE holds the check digit rules of the Inscrição Estadual of each UF,
+// as published by SINTEGRA. They receive the Inscrição Estadual with digits
+// only, except for the P of the produtores rurais of SP.
+var regrasIE = map[string]func(ie string) bool{
+	"AC": ieAC,
+	"AL": ieAL,
+	"AP": ieAP,
+	"AM": ieMod11(9, ""),
+	"BA": ieBA,
+	"CE": ieMod11(9, ""),
+	"DF": ieDF,
+	"ES": ieMod11(9, ""),
+	"GO": ieGO,
+	"MA": ieMod11(9, "12"),
+	"MT": ieMT,
+	"MS": ieMS,
+	"MG": ieMG,
+	"PA": ieMod11(9, "15"),
+	"PB": ieMod11(9, ""),
+	"PR": iePR,
+	"PE": iePE,
+	"PI": ieMod11(9, ""),
+	"RJ": ieRJ,
+	"RN": ieRN,
+	"RS": ieRS,
+	"RO": ieRO,
+	"RR": ieRR,
+	"SC": ieMod11(9, ""),
+	"SP": ieSP,
+	"SE": ieMod11(9, ""),
+	"TO": ieTO,
+}
+
+// InscricaoEstadualValida reports whether the given Inscrição Estadual,
+// formatted or not, has valid check digits for the given UF. Sequences of the
+// same digit are rejected.
+func InscricaoEstadualValida(uf, ie string) bool {
+	regra, ok := regrasIE[strings.ToUpper(strings.TrimSpace(uf))]
+	if !ok {
+		return false
+	}
+	rural := strings.HasPrefix(strings.ToUpper(strings.TrimSpace(ie)), "P")
+	ie = SomenteDigitos(ie)
+	if ie == "" || repetido(ie) {
+		return false
+	}
+	if rural {
+		if strings.ToUpper(strings.TrimSpace(uf)) != "SP" {
+			return false
+		}
+		ie = "P" + ie
+	}
+	return regra(ie)
+}
+
+// ponderar returns the sum of the digits of s multiplied by the given weights.
+func ponderar(s string, pesos ...int) int {
+	soma := 0
+	for i, r := range s {
+		soma += int(r-'0') * pesos[i]
+	}
+	return soma
+}
+
+// decrescentes returns the weights from de down to 2, as in 9, 8, ..., 2.
+func decrescentes(de int) []int {
+	pesos := make([]int, 0, de-1)
+	for p := de; p >= 2; p-- {
+		pesos = append(pesos, p)
+	}
+	return pesos
+}
+
+// dv11 returns 11 minus the remainder of soma by 11, or 0 when it is 10 or 11.
+func dv11(soma int) int {
+	dv := 11 - soma%11
+	if dv >= 10 {
+		return 0
+	}
+	return dv
+}
+
+func digito(s string, i int) int {
+	return int(s[i] - '0')
+}
+
+// ieMod11 returns the rule of the UFs whose Inscrição Estadual has the given
+// length and prefix and a single check digit over the decreasing weights.
+func ieMod11(tamanho int, prefixo string) func(string) bool {
+	return func(ie string) bool {
+		if len(ie) != tamanho || !strings.HasPrefix(ie, prefixo) {
+			return false
+		}
+		return dv11(ponderar(ie[:tamanho-1], decrescentes(tamanho)...)) == digito(ie, tamanho-1)
+	}
+}
+
+func ieAC(ie string) bool {
+	if len(ie) != 13 || !strings.HasPrefix(ie, "01") {
+		return false
+	}
+	return ieDoisDigitos(ie)
+}
+
+func ieDF(ie string) bool {
+	if len(ie) != 13 || !strings.HasPrefix(ie, "07") {
+		return false
+	}
+	return ieDoisDigitos(ie)
+}
+
+// ieDoisDigitos checks the two check digits of the 13 digit Inscrições
+// Estaduais of AC and DF.
+func ieDoisDigitos(ie string) bool {
+	primeiro := dv11(ponderar(ie[:11], 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2))
+	segundo := dv11(ponderar(ie[:12], 5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2))
+	return primeiro == digito(ie, 11) && segundo == digito(ie, 12)
+}
+
+func ieAL(ie string) bool {
+	if len(ie) != 9 || !strings.HasPrefix(ie, "24") || !strings.ContainsRune("03578", rune(ie[2])) {
+		return false
+	}
+	dv := ponderar(ie[:8], decrescentes(9)...) * 10 % 11
+	if dv == 10 {
+		dv = 0
+	}
+	return dv == digito(ie, 8)
+}
+
+func ieAP(ie string) bool {
+	if len(ie) != 9 || !strings.HasPrefix(ie, "03") {
+		return false
+	}
+	base, _ := strconv.Atoi(ie[:8])
+	p, d := 0, 0
+	switch {
+	case base <= 3017000:
+		p, d = 5, 0
+	case base <= 3019022:
+		p, d = 9, 1
+	}
+	dv := 11 - (p+ponderar(ie[:8], decrescentes(9)...))%11
+	switch dv {
+	case 10:
+		dv = 0
+	case 11:
+		dv = d
+	}
+	return dv == digito(ie, 8)
+}
+
+func ieBA(ie string) bool {
+	if len(ie) != 8 && len(ie) != 9 {
+		return false
+	}
+	modulo := 10
+	if strings.ContainsRune("679", rune(ie[len(ie)-8])) {
+		modulo = 11
+	}
+	dv := func(soma int) int {
+		if modulo == 11 {
+			return dv11(soma)
+		}
+		dv := 10 - soma%10
+		if dv == 10 {
+			return 0
+		}
+		return dv
+	}
+	n := len(ie) - 2
+	segundo := dv(ponderar(ie[:n], decrescentes(n+1)...))
+	primeiro := dv(ponderar(ie[:n]+strconv.Itoa(segundo), decrescentes(n+2)...))
+	return primeiro == digito(ie, n) && segundo == digito(ie, n+1)
+}
+
+func ieGO(ie string) bool {
+	if len(ie) != 9 || !strings.HasPrefix(ie, "10") && !strings.HasPrefix(ie, "11") && !strings.HasPrefix(ie, "15") && ie[0] != '2' {
+		return false
+	}
+	resto := ponderar(ie[:8], decrescentes(9)...) % 11
+	dv := 11 - resto
+	switch resto {
+	case 0:
+		dv = 0
+	case 1:
+		dv = 0
+		if base, _ := strconv.Atoi(ie[:8]); base >= 10103105 && base <= 10119997 {
+			dv = 1
+		}
+	}
+	return dv == digito(ie, 8)
+}
+
+func ieMT(ie string) bool {
+	if len(ie) > 11 {
+		return false
+	}
+	ie = strings.Repeat("0", 11-len(ie)) + ie
+	return dv11(ponderar(ie[:10], 3, 2, 9, 8, 7, 6, 5, 4, 3, 2)) == digito(ie, 10)
+}
+
+func ieMS(ie string) bool {
+	if len(ie) != 9 || !strings.HasPrefix(ie, "28") && !strings.HasPrefix(ie, "50") {
+		return false
+	}
+	return dv11(ponderar(ie[:8], decrescentes(9)...)) == digito(ie, 8)
+}
+
+func ieMG(ie string) bool {
+	if len(ie) != 13 {
+		return false
+	}
+	base := ie[:3] + "0" + ie[3:11]
+	soma := 0
+	for i := range base {
+		for _, r := range strconv.Itoa(digito(base, i) * (1 + i%2)) {
+			soma += int(r - '0')
+		}
+	}
+	primeiro := (10 - soma%10) % 10
+	segundo := dv11(ponderar(ie[:11]+strconv.Itoa(primeiro), 3, 2, 11, 10, 9, 8, 7, 6, 5, 4, 3, 2))
+	return primeiro == digito(ie, 11) && segundo == digito(ie, 12)
+}
+
+func iePR(ie string) bool {
+	if len(ie) != 10 {
+		return false
+	}
+	primeiro := dv11(ponderar(ie[:8], 3, 2, 7, 6, 5, 4, 3, 2))
+	segundo := dv11(ponderar(ie[:9], 4, 3, 2, 7, 6, 5, 4, 3, 2))
+	return primeiro == digito(ie, 8) && segundo == digito(ie, 9)
+}
+
+func iePE(ie string) bool {
+	switch len(ie) {
+	case 9:
+		primeiro := dv11(ponderar(ie[:7], decrescentes(8)...))
+		segundo := dv11(ponderar(ie[:8], decrescentes(9)...))
+		return primeiro == digito(ie, 7) && segundo == digito(ie, 8)
+	case 14:
+		dv := 11 - ponderar(ie[:13], 5, 4, 3, 2, 1, 9, 8, 7, 6, 5, 4, 3, 2)%11
+		if dv > 9 {
+			dv -= 10
+		}
+		return dv == digito(ie, 13)
+	}
+	return false
+}
+
+func ieRJ(ie string) bool {
+	if len(ie) != 8 {
+		return false
+	}
+	return dv11(ponderar(ie[:7], 2, 7, 6, 5, 4, 3, 2)) == digito(ie, 7)
+}
+
+func ieRN(ie string) bool {
+	if len(ie) != 9 && len(ie) != 10 || !strings.HasPrefix(ie, "20") {
+		return false
+	}
+	n := len(ie) - 1
+	dv := ponderar(ie[:n], decrescentes(n+1)...) * 10 % 11
+	if dv == 10 {
+		dv = 0
+	}
+	return dv == digito(ie, n)
+}
+
+func ieRS(ie string) bool {
+	if len(ie) != 10 {
+		return false
+	}
+	return dv11(ponderar(ie[:9], 2, 9, 8, 7, 6, 5, 4, 3, 2)) == digito(ie, 9)
+}
+
+func ieRO(ie string) bool {
+	if len(ie) != 14 {
+		return false
+	}
+	dv := 11 - ponderar(ie[:13], 6, 5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2)%11
+	if dv > 9 {
+		dv -= 10
+	}
+	return dv == digito(ie, 13)
+}
+
+func ieRR(ie string) bool {
+	if len(ie) != 9 || !strings.HasPrefix(ie, "24") {
+		return false
+	}
+	return ponderar(ie[:8], 1, 2, 3, 4, 5, 6, 7, 8)%9 == digito(ie, 8)
+}
+
+// ieSP checks the Inscrições Estaduais of SP, the ones of the produtores
+// rurais starting with a P.
+func ieSP(ie string) bool {
+	dv := func(s string, pesos ...int) int {
+		return ponderar(s, pesos...) % 11 % 10
+	}
+	if strings.HasPrefix(ie, "P") {
+		ie = ie[1:]
+		return len(ie) == 12 && dv(ie[:8], 1, 3, 4, 5, 6, 7, 8, 10) == digito(ie, 8)
+	}
+	if len(ie) != 12 {
+		return false
+	}
+	primeiro := dv(ie[:8], 1, 3, 4, 5, 6, 7, 8, 10)
+	segundo := dv(ie[:11], 3, 2, 10, 9, 8, 7, 6, 5, 4, 3, 2)
+	return primeiro == digito(ie, 8) && segundo == digito(ie, 11)
+}
+
+// ieTO checks the Inscrições Estaduais of TO, with 9 digits or with the 11
+// digits of the old format, whose 3rd and 4th digits are not weighted.
+func ieTO(ie string) bool {
+	switch len(ie) {
+	case 9:
+	case 11:
+		switch ie[2:4] {
+		case "01", "02", "03", "99":
+			ie = ie[:2] + ie[4:]
+		default:
+			return false
+		}
+	default:
+		return false
+	}
+	return dv11(ponderar(ie[:8], decrescentes(9)...)) == digito(ie, 8)
+}
@@ -0,0 +1,82 @@
+package documento_test
+
+import (
+	"github.com/diegohordi/soawebservices/internal/documento"
+	"testing"
+)
+
+func TestInscricaoEstadualValida(t *testing.T) {
+	validas := []struct {
+		uf string
+		ie string
+	}{
+		{uf: "AC", ie: "01.004.823/001-12"},
+		{uf: "AL", ie: "240000048"},
+		{uf: "AP", ie: "030123459"},
+		{uf: "AM", ie: "99.999.999-0"},
+		{uf: "BA", ie: "123456-63"},
+		{uf: "BA", ie: "612345-57"},
+		{uf: "BA", ie: "1000003-06"},
+		{uf: "CE", ie: "06000001-5"},
+		{uf: "DF", ie: "07300001001-09"},
+		{uf: "ES", ie: "999999990"},
+		{uf: "GO", ie: "10.987.654-7"},
+		{uf: "MA", ie: "12000038-5"},
+		{uf: "MT", ie: "0013000001-9"},
+		{uf: "MT", ie: "130000019"},
+		{uf: "MS", ie: "280000006"},
+		{uf: "MG", ie: "062.307.904/0081"},
+		{uf: "PA", ie: "15-999999-5"},
+		{uf: "PB", ie: "06000001-5"},
+		{uf: "PR", ie: "123.45678-50"},
+		{uf: "PE", ie: "0321418-40"},
+		{uf: "PE", ie: "18.1.001.0000004-9"},
+		{uf: "PI", ie: "012345679"},
+		{uf: "RJ", ie: "99.999.99-3"},
+		{uf: "RN", ie: "20.040.040-1"},
+		{uf: "RN", ie: "20.0.040.040-0"},
+		{uf: "RS", ie: "224/3658792"},
+		{uf: "RO", ie: "0000000062521-3"},
+		{uf: "RR", ie: "24006628-1"},
+		{uf: "SC", ie: "251.040.852"},
+		{uf: "SP", ie: "110.042.490.114"},
+		{uf: "SP", ie: "P-01100424.3/002"},
+		{uf: "SE", ie: "27123456-3"},
+		{uf: "TO", ie: "29.01.022783-6"},
+	}
+	for _, tt := range validas {
+		tt := tt
+		t.Run(tt.uf+" "+tt.ie, func(t *testing.T) {
+			t.Parallel()
+			if !documento.InscricaoEstadualValida(tt.uf, tt.ie) {
+				t.Errorf("InscricaoEstadualValida(%s, %s) = false, want true", tt.uf, tt.ie)
+			}
+			digitos := documento.SomenteDigitos(tt.ie)
+			ultimo := digitos[len(digitos)-1]
+			errada := digitos[:len(digitos)-1] + string('0'+(ultimo-'0'+1)%10)
+			if tt.ie[0] == 'P' {
+				errada = "P" + digitos[:8] + string('0'+(digitos[8]-'0'+1)%10) + digitos[9:]
+			}
+			if documento.InscricaoEstadualValida(tt.uf, errada) {
+				t.Errorf("InscricaoEstadualValida(%s, %s) = true, want false", tt.uf, errada)
+			}
+		})
+	}
+
+	invalidas := []struct {
+		uf string
+		ie string
+	}{
+		{uf: "XX", ie: "110042490114"},
+		{uf: "RJ", ie: "110042490114"},
+		{uf: "MG", ie: "P-01100424.3/002"},
+		{uf: "SP", ie: "111111111111"},
+		{uf: "SP", ie: "ISENTO"},
+		{uf: "AC", ie: "0200482300112"},
+	}
+	for _, tt := range invalidas {
+		if documento.InscricaoEstadualValida(tt.uf, tt.ie) {
+			t.Errorf("InscricaoEstadualValida(%s, %s) = true, want false", tt.uf, tt.ie)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLimiter_Allow(t *testing.T) {
+	now := time.Date(2021, 12, 6, 0, 0, 0, 0, time.UTC)
+	l := New(2, 2)
+	l.now = func() time.Time { return now }
+	for i := 0; i < 2; i++ {
+		if !l.Allow() {
+			t.Fatalf("expected event %d of the burst to be allowed", i)
+		}
+	}
+	if l.Allow() {
+		t.Fatal("expected the event to be denied once the burst is consumed")
+	}
+	now = now.Add(500 * time.Millisecond)
+	if !l.Allow() {
+		t.Fatal("expected the event to be allowed after the refill")
+	}
+	if l.Allow() {
+		t.Fatal("expected the event to be denied")
+	}
+}
+
+func TestLimiter_Wait(t *testing.T) {
+	l := New(100, 1)
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := l.Wait(context.TODO()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 15*time.Millisecond {
+		t.Errorf("expected the events to be spaced, took %s", elapsed)
+	}
+
+	l = New(0.001, 1)
+	_ = l.Wait(context.TODO())
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Millisecond)
+	defer cancel()
+	if err := l.Wait(ctx); err == nil {
+		t.Error("expected the wait to be cancelled")
+	}
+}
+
+func TestLimiter_Unlimited(t *testing.T) {
+	l := New(0, 1)
+	for i := 0; i < 100; i++ {
+		if !l.Allow() {
+			t.Fatal("expected every event to be allowed")
+		}
+	}
+}
@@ -0,0 +1,37 @@
+// Package texto implements the normalization used to compare names and
+// addresses regardless of case, accents and punctuation.
+package texto
+
+import (
+	"strings"
+	"unicode"
+)
+
+var acentos = strings.NewReplacer(
+	"Á", "A", "À", "A", "Â", "A", "Ã", "A", "Ä", "A",
+	"É", "E", "È", "E", "Ê", "E", "Ë", "E",
+	"Í", "I", "Ì", "I", "Î", "I", "Ï", "I",
+	"Ó", "O", "Ò", "O", "Ô", "O", "Õ", "O", "Ö", "O",
+	"Ú", "U", "Ù", "U", "Û", "U", "Ü", "U",
+	"Ç", "C", "Ñ", "N",
+)
+
+// SemAcentos returns s in upper case without the accents of the Portuguese
+// alphabet.
+func SemAcentos(s string) string {
+	return acentos.Replace(strings.ToUpper(s))
+}
+
+// Tokens returns the words of s without accents, in upper case, splitting it
+// on anything but letters and digits.
+func Tokens(s string) []string {
+	return strings.FieldsFunc(SemAcentos(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// Normalizar returns the tokens of s joined by single spaces, so two strings
+// differing only in case, accents or punctuation are normalized alike.
+func Normalizar(s string) string {
+	return strings.Join(Tokens(s), " ")
+}
@@ -0,0 +1,23 @@
+package texto_test
+
+import (
+	"github.com/diegohordi/soawebservices/internal/texto"
+	"testing"
+)
+
+func TestNormalizar(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "Avenida São João", want: "AVENIDA SAO JOAO"},
+		{value: "  r. da   Conceição, 12 ", want: "R DA CONCEICAO 12"},
+		{value: "PRAÇA DA SÉ", want: "PRACA DA SE"},
+		{value: "", want: ""},
+	}
+	for _, tt := range tests {
+		if got := texto.Normalizar(tt.value); got != tt.want {
+			t.Errorf("Normalizar(%q) got = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
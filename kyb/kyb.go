@@ -0,0 +1,170 @@
+// Package kyb decides whether a CNPJ is acceptable, evaluating declared rules
+// against a PessoaJuridica. Decisions and reasons are the ones of the kyc
+// package.
+package kyb
+
+import (
+	"context"
+	"errors"
+	"github.com/diegohordi/soawebservices"
+	"github.com/diegohordi/soawebservices/internal/documento"
+	"github.com/diegohordi/soawebservices/kyc"
+	"strings"
+	"time"
+)
+
+const (
+	CodigoCNPJInvalido                 kyc.Codigo = "CNPJ_INVALIDO"
+	CodigoSituacaoRFB                  kyc.Codigo = "SITUACAO_RFB"
+	CodigoIdadeMinima                  kyc.Codigo = "IDADE_MINIMA"
+	CodigoDataFundacaoAusente          kyc.Codigo = "DATA_FUNDACAO_AUSENTE"
+	CodigoCNAENaoPermitido             kyc.Codigo = "CNAE_NAO_PERMITIDO"
+	CodigoCNAEBloqueado                kyc.Codigo = "CNAE_BLOQUEADO"
+	CodigoNaturezaJuridicaNaoPermitida kyc.Codigo = "NATUREZA_JURIDICA_NAO_PERMITIDA"
+	CodigoNaturezaJuridicaBloqueada    kyc.Codigo = "NATUREZA_JURIDICA_BLOQUEADA"
+	CodigoFilial                       kyc.Codigo = "FILIAL"
+)
+
+// Regras are the rules of an evaluation, each one rejecting the companies it
+// does not allow. SituacoesRFB lists the situations allowed, and the company
+// must have been founded at least IdadeMinimaMeses months ago, being reviewed
+// when its foundation is unknown. CNAE and NaturezaJuridica codes, formatted or
+// not, match the codes they prefix, so "47" matches the whole retail division;
+// empty lists of allowed codes allow any code. SomenteMatriz rejects the
+// filiais. Versao identifies the rules in the records.
+type Regras struct {
+	Versao              string   `json:"versao"`
+	SituacoesRFB        []string `json:"situacoes_rfb"`
+	IdadeMinimaMeses    int      `json:"idade_minima_meses"`
+	CNAEsPermitidos     []string `json:"cnaes_permitidos"`
+	CNAEsBloqueados     []string `json:"cnaes_bloqueados"`
+	NaturezasPermitidas []string `json:"naturezas_permitidas"`
+	NaturezasBloqueadas []string `json:"naturezas_bloqueadas"`
+	SomenteMatriz       bool     `json:"somente_matriz"`
+}
+
+// DefaultRegras approves the active companies founded at least 6 months ago.
+func DefaultRegras() Regras {
+	return Regras{
+		Versao:           "1",
+		SituacoesRFB:     []string{"ATIVA"},
+		IdadeMinimaMeses: 6,
+	}
+}
+
+// Registro is the record of an evaluation.
+type Registro struct {
+	Documento        string       `json:"documento"`
+	Regras           Regras       `json:"regras"`
+	SituacaoRFB      string       `json:"situacao_rfb"`
+	CNAE             string       `json:"cnae"`
+	NaturezaJuridica string       `json:"natureza_juridica"`
+	Decisao          kyc.Decisao  `json:"decisao"`
+	Motivos          []kyc.Motivo `json:"motivos"`
+	AvaliadoEm       time.Time    `json:"avaliado_em"`
+}
+
+// novoRegistro returns the record of an evaluation of the given CNPJ, approved
+// until a rule rejects it, with an empty list of reasons so its JSON always has
+// the same shape.
+func novoRegistro(cnpj string, r Regras, agora time.Time) Registro {
+	return Registro{
+		Documento:  cnpj,
+		Regras:     r,
+		Decisao:    kyc.Aprovar,
+		Motivos:    []kyc.Motivo{},
+		AvaliadoEm: agora.UTC(),
+	}
+}
+
+func (r *Registro) motivo(codigo kyc.Codigo, descricao string) {
+	r.Motivos = append(r.Motivos, kyc.Motivo{Codigo: codigo, Decisao: kyc.Rejeitar, Descricao: descricao})
+	r.Decisao = r.Decisao.Max(kyc.Rejeitar)
+}
+
+// casa reports whether the given code is prefixed by any of the given codes,
+// comparing their digits only.
+func casa(codigos []string, codigo string) bool {
+	codigo = documento.SomenteDigitos(codigo)
+	for _, c := range codigos {
+		if c = documento.SomenteDigitos(c); c != "" && strings.HasPrefix(codigo, c) {
+			return true
+		}
+	}
+	return false
+}
+
+// Avaliar evaluates the rules against the given PessoaJuridica at the given
+// date.
+func (r Regras) Avaliar(pj soawebservices.PessoaJuridica, agora time.Time) Registro {
+	registro := novoRegistro(pj.Documento, r, agora)
+	registro.SituacaoRFB = pj.SituacaoRFB
+	registro.CNAE = pj.CNAE.Codigo
+	registro.NaturezaJuridica = pj.NaturezaJuridica.Codigo
+	if len(r.SituacoesRFB) > 0 {
+		permitida := false
+		for _, s := range r.SituacoesRFB {
+			permitida = permitida || strings.EqualFold(strings.TrimSpace(s), strings.TrimSpace(pj.SituacaoRFB))
+		}
+		if !permitida {
+			registro.motivo(CodigoSituacaoRFB, "a situação cadastral do CNPJ não é permitida")
+		}
+	}
+	if r.IdadeMinimaMeses > 0 {
+		switch {
+		case pj.DataFundacao.IsZero():
+			registro.Motivos = append(registro.Motivos, kyc.Motivo{Codigo: CodigoDataFundacaoAusente, Decisao: kyc.Revisar, Descricao: "a data de fundação é desconhecida"})
+			registro.Decisao = registro.Decisao.Max(kyc.Revisar)
+		case pj.DataFundacao.AddDate(0, r.IdadeMinimaMeses, 0).After(agora):
+			registro.motivo(CodigoIdadeMinima, "a empresa não tem a idade mínima")
+		}
+	}
+	if len(r.CNAEsPermitidos) > 0 && !casa(r.CNAEsPermitidos, pj.CNAE.Codigo) {
+		registro.motivo(CodigoCNAENaoPermitido, "a atividade econômica não é permitida")
+	}
+	if casa(r.CNAEsBloqueados, pj.CNAE.Codigo) {
+		registro.motivo(CodigoCNAEBloqueado, "a atividade econômica é bloqueada")
+	}
+	if len(r.NaturezasPermitidas) > 0 && !casa(r.NaturezasPermitidas, pj.NaturezaJuridica.Codigo) {
+		registro.motivo(CodigoNaturezaJuridicaNaoPermitida, "a natureza jurídica não é permitida")
+	}
+	if casa(r.NaturezasBloqueadas, pj.NaturezaJuridica.Codigo) {
+		registro.motivo(CodigoNaturezaJuridicaBloqueada, "a natureza jurídica é bloqueada")
+	}
+	if r.SomenteMatriz && !pj.Matriz {
+		registro.motivo(CodigoFilial, "a empresa é uma filial")
+	}
+	return registro
+}
+
+// Client is the subset of the soawebservices.Client used by the Avaliador.
+type Client interface {
+	ConsultarCNPJ(ctx context.Context, cnpj string) (soawebservices.PessoaJuridica, error)
+}
+
+// Avaliador looks up and evaluates CNPJs against its rules.
+type Avaliador struct {
+	client Client
+	regras Regras
+	agora  func() time.Time
+}
+
+func NewAvaliador(client Client, regras Regras) *Avaliador {
+	return &Avaliador{client: client, regras: regras, agora: time.Now}
+}
+
+// Avaliar looks up the given CNPJ and evaluates it. An invalid CNPJ is
+// rejected; any other error of the lookup is returned.
+func (a *Avaliador) Avaliar(ctx context.Context, cnpj string) (Registro, error) {
+	cnpj = documento.SomenteDigitos(cnpj)
+	pj, err := a.client.ConsultarCNPJ(ctx, cnpj)
+	if errors.Is(err, soawebservices.ErrCNPJInvalido) {
+		registro := novoRegistro(cnpj, a.regras, a.agora())
+		registro.motivo(CodigoCNPJInvalido, "o CNPJ informado é inválido")
+		return registro, nil
+	}
+	if err != nil {
+		return Registro{}, err
+	}
+	return a.regras.Avaliar(pj, a.agora()), nil
+}
@@ -0,0 +1,178 @@
+package kyb_test
+
+import (
+	"context"
+	"errors"
+	"github.com/diegohordi/soawebservices"
+	"github.com/diegohordi/soawebservices/kyb"
+	"github.com/diegohordi/soawebservices/kyc"
+	"github.com/diegohordi/soawebservices/soawebservicestest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var agora = time.Date(2022, 6, 15, 12, 0, 0, 0, time.UTC)
+
+func empresa(mudar func(pj *soawebservices.PessoaJuridica)) soawebservices.PessoaJuridica {
+	pj := soawebservices.PessoaJuridica{
+		Documento:        "11222333000181",
+		RazaoSocial:      "EMPRESA DE TESTES LTDA",
+		DataFundacao:     time.Date(2007, 5, 2, 0, 0, 0, 0, time.UTC),
+		Matriz:           true,
+		CNAE:             soawebservices.CNAE{Codigo: "82.91-1-00"},
+		NaturezaJuridica: soawebservices.NaturezaJuridica{Codigo: "206-2"},
+		SituacaoRFB:      "ATIVA",
+	}
+	if mudar != nil {
+		mudar(&pj)
+	}
+	return pj
+}
+
+func TestRegras_Avaliar(t *testing.T) {
+	restritivas := kyb.DefaultRegras()
+	restritivas.CNAEsPermitidos = []string{"82", "47.1"}
+	restritivas.CNAEsBloqueados = []string{"82.91-1"}
+	restritivas.NaturezasBloqueadas = []string{"213-5"}
+	restritivas.SomenteMatriz = true
+	tests := []struct {
+		name        string
+		regras      kyb.Regras
+		pj          soawebservices.PessoaJuridica
+		want        kyc.Decisao
+		wantCodigos []kyc.Codigo
+	}{
+		{
+			name:        "should approve an active company",
+			regras:      kyb.DefaultRegras(),
+			pj:          empresa(nil),
+			want:        kyc.Aprovar,
+			wantCodigos: []kyc.Codigo{},
+		},
+		{
+			name:   "should reject a company that is not active",
+			regras: kyb.DefaultRegras(),
+			pj: empresa(func(pj *soawebservices.PessoaJuridica) {
+				pj.SituacaoRFB = "BAIXADA"
+			}),
+			want:        kyc.Rejeitar,
+			wantCodigos: []kyc.Codigo{kyb.CodigoSituacaoRFB},
+		},
+		{
+			name:   "should reject a company founded recently",
+			regras: kyb.DefaultRegras(),
+			pj: empresa(func(pj *soawebservices.PessoaJuridica) {
+				pj.DataFundacao = time.Date(2022, 1, 16, 0, 0, 0, 0, time.UTC)
+			}),
+			want:        kyc.Rejeitar,
+			wantCodigos: []kyc.Codigo{kyb.CodigoIdadeMinima},
+		},
+		{
+			name:   "should review a company whose foundation is unknown",
+			regras: kyb.DefaultRegras(),
+			pj: empresa(func(pj *soawebservices.PessoaJuridica) {
+				pj.DataFundacao = time.Time{}
+			}),
+			want:        kyc.Revisar,
+			wantCodigos: []kyc.Codigo{kyb.CodigoDataFundacaoAusente},
+		},
+		{
+			name:   "should approve an allowed CNAE",
+			regras: restritivas,
+			pj: empresa(func(pj *soawebservices.PessoaJuridica) {
+				pj.CNAE.Codigo = "4711-3/02"
+			}),
+			want:        kyc.Aprovar,
+			wantCodigos: []kyc.Codigo{},
+		},
+		{
+			name:        "should reject a blocked CNAE",
+			regras:      restritivas,
+			pj:          empresa(nil),
+			want:        kyc.Rejeitar,
+			wantCodigos: []kyc.Codigo{kyb.CodigoCNAEBloqueado},
+		},
+		{
+			name:   "should reject a CNAE not allowed, a blocked natureza jurídica and a filial",
+			regras: restritivas,
+			pj: empresa(func(pj *soawebservices.PessoaJuridica) {
+				pj.CNAE.Codigo = "64.99-9-99"
+				pj.NaturezaJuridica.Codigo = "2135"
+				pj.Matriz = false
+			}),
+			want:        kyc.Rejeitar,
+			wantCodigos: []kyc.Codigo{kyb.CodigoCNAENaoPermitido, kyb.CodigoNaturezaJuridicaBloqueada, kyb.CodigoFilial},
+		},
+		{
+			name: "should reject a natureza jurídica not allowed",
+			regras: kyb.Regras{
+				NaturezasPermitidas: []string{"206-2", "230-5"},
+			},
+			pj: empresa(func(pj *soawebservices.PessoaJuridica) {
+				pj.NaturezaJuridica.Codigo = "213-5"
+			}),
+			want:        kyc.Rejeitar,
+			wantCodigos: []kyc.Codigo{kyb.CodigoNaturezaJuridicaNaoPermitida},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			registro := tt.regras.Avaliar(tt.pj, agora)
+			if registro.Decisao != tt.want {
+				t.Errorf("Decisao got = %v, want %v", registro.Decisao, tt.want)
+			}
+			codigos := []kyc.Codigo{}
+			for _, m := range registro.Motivos {
+				codigos = append(codigos, m.Codigo)
+			}
+			if !reflect.DeepEqual(codigos, tt.wantCodigos) {
+				t.Errorf("Motivos got = %v, want %v", codigos, tt.wantCodigos)
+			}
+		})
+	}
+}
+
+func TestAvaliador_Avaliar(t *testing.T) {
+	fake := soawebservicestest.NewFakeClient()
+	fake.OnCNPJ("11222333000181").Return(empresa(nil))
+	fake.OnCNPJ("11444777000161").ReturnErr(soawebservices.ErrCredenciaisInvalidas)
+	avaliador := kyb.NewAvaliador(fake, kyb.DefaultRegras())
+	tests := []struct {
+		name    string
+		cnpj    string
+		want    kyc.Decisao
+		wantErr error
+	}{
+		{
+			name: "should approve the company looked up",
+			cnpj: "11.222.333/0001-81",
+			want: kyc.Aprovar,
+		},
+		{
+			name: "should reject an invalid CNPJ",
+			cnpj: "11.222.333/0001-82",
+			want: kyc.Rejeitar,
+		},
+		{
+			name:    "should fail due to the failed lookup",
+			cnpj:    "11.444.777/0001-61",
+			wantErr: soawebservices.ErrCredenciaisInvalidas,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			registro, err := avaliador.Avaliar(context.TODO(), tt.cnpj)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if registro.Decisao != tt.want {
+				t.Errorf("Decisao got = %v, want %v", registro.Decisao, tt.want)
+			}
+		})
+	}
+}
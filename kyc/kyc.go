@@ -0,0 +1,310 @@
+// Package kyc decides whether a CPF is acceptable, evaluating declared rules
+// against the result of ConsultarCPF.
+package kyc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"github.com/diegohordi/soawebservices"
+	"github.com/diegohordi/soawebservices/internal/documento"
+	"github.com/diegohordi/soawebservices/matching"
+	"time"
+)
+
+// Decisao is the outcome of an evaluation. Its order is Aprovar, Revisar and
+// Rejeitar, the most severe one prevailing.
+type Decisao string
+
+const (
+	Aprovar  Decisao = "APROVAR"
+	Revisar  Decisao = "REVISAR"
+	Rejeitar Decisao = "REJEITAR"
+)
+
+// Max returns the most severe of d and outra.
+func (d Decisao) Max(outra Decisao) Decisao {
+	if outra.gravidade() > d.gravidade() {
+		return outra
+	}
+	return d
+}
+
+func (d Decisao) gravidade() int {
+	switch d {
+	case Rejeitar:
+		return 2
+	case Revisar:
+		return 1
+	}
+	return 0
+}
+
+// Codigo identifies the reason of a decision.
+type Codigo string
+
+const (
+	CodigoCPFInvalido              Codigo = "CPF_INVALIDO"
+	CodigoDataNascimentoDivergente Codigo = "DATA_NASCIMENTO_DIVERGENTE"
+	CodigoDataNascimentoAusente    Codigo = "DATA_NASCIMENTO_AUSENTE"
+	CodigoStatusNaoPermitido       Codigo = "STATUS_NAO_PERMITIDO"
+	CodigoStatusRevisao            Codigo = "STATUS_REVISAO"
+	CodigoObito                    Codigo = "OBITO"
+	CodigoIdadeMinima              Codigo = "IDADE_MINIMA"
+	CodigoNomeDivergente           Codigo = "NOME_DIVERGENTE"
+)
+
+// Motivo is a reason of a decision, in a machine-readable Codigo and in a
+// Descricao for people.
+type Motivo struct {
+	Codigo    Codigo  `json:"codigo"`
+	Decisao   Decisao `json:"decisao"`
+	Descricao string  `json:"descricao"`
+}
+
+// Regras are the rules of an evaluation. A CPF whose status is in
+// StatusAprovados is approved and one whose status is in StatusRevisao is
+// reviewed, any other status being rejected. IdadeMinima rejects the younger
+// people and SimilaridadeNome, from 0 to 1, sends to review the declared names
+// whose matching.MatchNome score is lower; zero disables both. Any death
+// indicator rejects the CPF. Versao identifies the rules in the audit records.
+type Regras struct {
+	Versao           string                              `json:"versao"`
+	StatusAprovados  []soawebservices.PessoaFisicaStatus `json:"status_aprovados"`
+	StatusRevisao    []soawebservices.PessoaFisicaStatus `json:"status_revisao"`
+	IdadeMinima      int                                 `json:"idade_minima"`
+	SimilaridadeNome float64                             `json:"similaridade_nome"`
+}
+
+// DefaultRegras approves adults whose CPF is regular and whose name is close
+// to the declared one, reviewing the CPFs pending regularization or with
+// incomplete data. Version 2 scores the names with matching.MatchNome instead
+// of the Dice coefficient of version 1.
+func DefaultRegras() Regras {
+	return Regras{
+		Versao:           "2",
+		StatusAprovados:  []soawebservices.PessoaFisicaStatus{soawebservices.Regular},
+		StatusRevisao:    []soawebservices.PessoaFisicaStatus{soawebservices.PendenteRegularizacao, soawebservices.DadosIncompletos},
+		IdadeMinima:      18,
+		SimilaridadeNome: 0.8,
+	}
+}
+
+// Solicitacao holds the data declared by the person being evaluated.
+type Solicitacao struct {
+	CPF            string    `json:"cpf"`
+	Nome           string    `json:"nome"`
+	DataNascimento time.Time `json:"data_nascimento"`
+}
+
+// Registro is the audit record of an evaluation. Its Digest is the SHA-256 of
+// its other fields as JSON, which detects the accidental corruption of a
+// stored record. It does not detect tampering, as whoever edits a record can
+// recompute its Digest: records that must be tamper-evident should be signed
+// or kept in append-only storage.
+type Registro struct {
+	Solicitacao      Solicitacao                       `json:"solicitacao"`
+	Regras           Regras                            `json:"regras"`
+	Status           soawebservices.PessoaFisicaStatus `json:"status"`
+	SituacaoRFB      string                            `json:"situacao_rfb"`
+	Nome             string                            `json:"nome"`
+	SimilaridadeNome float64                           `json:"similaridade_nome"`
+	Decisao          Decisao                           `json:"decisao"`
+	Motivos          []Motivo                          `json:"motivos"`
+	AvaliadoEm       time.Time                         `json:"avaliado_em"`
+	Digest           string                            `json:"digest"`
+}
+
+// conteudo is the content of a Registro hashed by its Digest. It keeps the
+// statuses as their RFB codes, as the records encoded them before
+// PessoaFisicaStatus was encoded by its symbolic name, so unknown statuses
+// are hashed too and the digests stored before still verify.
+type conteudo struct {
+	Solicitacao      Solicitacao    `json:"solicitacao"`
+	Regras           conteudoRegras `json:"regras"`
+	Status           string         `json:"status"`
+	SituacaoRFB      string         `json:"situacao_rfb"`
+	Nome             string         `json:"nome"`
+	SimilaridadeNome float64        `json:"similaridade_nome"`
+	Decisao          Decisao        `json:"decisao"`
+	Motivos          []Motivo       `json:"motivos"`
+	AvaliadoEm       time.Time      `json:"avaliado_em"`
+	Digest           string         `json:"digest"`
+}
+
+type conteudoRegras struct {
+	Versao           string   `json:"versao"`
+	StatusAprovados  []string `json:"status_aprovados"`
+	StatusRevisao    []string `json:"status_revisao"`
+	IdadeMinima      int      `json:"idade_minima"`
+	SimilaridadeNome float64  `json:"similaridade_nome"`
+}
+
+func codigos(status []soawebservices.PessoaFisicaStatus) []string {
+	if status == nil {
+		return nil
+	}
+	c := make([]string, len(status))
+	for i, s := range status {
+		c[i] = string(s)
+	}
+	return c
+}
+
+// digest returns the SHA-256 of the record without its Digest.
+func (r Registro) digest() (string, error) {
+	buf, err := json.Marshal(conteudo{
+		Solicitacao: r.Solicitacao,
+		Regras: conteudoRegras{
+			Versao:           r.Regras.Versao,
+			StatusAprovados:  codigos(r.Regras.StatusAprovados),
+			StatusRevisao:    codigos(r.Regras.StatusRevisao),
+			IdadeMinima:      r.Regras.IdadeMinima,
+			SimilaridadeNome: r.Regras.SimilaridadeNome,
+		},
+		Status:           string(r.Status),
+		SituacaoRFB:      r.SituacaoRFB,
+		Nome:             r.Nome,
+		SimilaridadeNome: r.SimilaridadeNome,
+		Decisao:          r.Decisao,
+		Motivos:          r.Motivos,
+		AvaliadoEm:       r.AvaliadoEm,
+	})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(buf)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Integro reports whether the Digest of the record matches its fields. A
+// record whose digest cannot be computed is never intact.
+func (r Registro) Integro() bool {
+	digest, err := r.digest()
+	return err == nil && r.Digest == digest
+}
+
+// avaliacao accumulates the reasons of an evaluation.
+type avaliacao struct {
+	registro Registro
+}
+
+func (a *avaliacao) motivo(codigo Codigo, decisao Decisao, descricao string) {
+	a.registro.Motivos = append(a.registro.Motivos, Motivo{Codigo: codigo, Decisao: decisao, Descricao: descricao})
+	a.registro.Decisao = a.registro.Decisao.Max(decisao)
+}
+
+func (a *avaliacao) concluir() Registro {
+	if a.registro.Motivos == nil {
+		a.registro.Motivos = []Motivo{}
+	}
+	// A record whose content cannot be encoded, as one with a NaN score, is
+	// left without a Digest and thus is never intact.
+	a.registro.Digest, _ = a.registro.digest()
+	return a.registro
+}
+
+func contem(status []soawebservices.PessoaFisicaStatus, s soawebservices.PessoaFisicaStatus) bool {
+	for _, st := range status {
+		if st == s {
+			return true
+		}
+	}
+	return false
+}
+
+// idade returns the age at the given date of someone born at nascimento. The
+// date is taken in the location of nascimento, so the birthday starts at the
+// same instant wherever the evaluation runs.
+func idade(nascimento, data time.Time) int {
+	data = data.In(nascimento.Location())
+	anos := data.Year() - nascimento.Year()
+	if data.Month() < nascimento.Month() || data.Month() == nascimento.Month() && data.Day() < nascimento.Day() {
+		anos--
+	}
+	return anos
+}
+
+// Avaliar evaluates the rules against the given ConsultarCPF result at the
+// given date.
+func (r Regras) Avaliar(s Solicitacao, pf soawebservices.PessoaFisica, agora time.Time) Registro {
+	a := &avaliacao{registro: Registro{
+		Solicitacao: s,
+		Regras:      r,
+		Status:      pf.Status,
+		SituacaoRFB: pf.SituacaoRFB,
+		Nome:        pf.Nome,
+		Decisao:     Aprovar,
+		AvaliadoEm:  agora.UTC(),
+	}}
+	if pf.Status == soawebservices.TitularFalecido || pf.AnoObito != 0 || pf.AnoObitoTexto != "" || pf.MensagemObito != "" {
+		a.motivo(CodigoObito, Rejeitar, "há registro de óbito do titular")
+	}
+	switch {
+	case contem(r.StatusAprovados, pf.Status):
+	case contem(r.StatusRevisao, pf.Status):
+		a.motivo(CodigoStatusRevisao, Revisar, "a situação cadastral do CPF requer revisão")
+	default:
+		a.motivo(CodigoStatusNaoPermitido, Rejeitar, "a situação cadastral do CPF não é permitida")
+	}
+	if r.IdadeMinima > 0 {
+		nascimento := pf.DataNascimento
+		if nascimento.IsZero() {
+			nascimento = s.DataNascimento
+		}
+		switch {
+		case nascimento.IsZero():
+			a.motivo(CodigoDataNascimentoAusente, Revisar, "a data de nascimento é desconhecida")
+		case idade(nascimento, agora) < r.IdadeMinima:
+			a.motivo(CodigoIdadeMinima, Rejeitar, "o titular não tem a idade mínima")
+		}
+	}
+	if r.SimilaridadeNome > 0 && s.Nome != "" {
+		a.registro.SimilaridadeNome = matching.MatchNome(pf, s.Nome).Score
+		if a.registro.SimilaridadeNome < r.SimilaridadeNome {
+			a.motivo(CodigoNomeDivergente, Revisar, "o nome informado diverge do nome na RFB")
+		}
+	}
+	return a.concluir()
+}
+
+// Client is the subset of the soawebservices.Client used by the Avaliador.
+type Client interface {
+	ConsultarCPF(ctx context.Context, cpf string, dataNascimento time.Time) (soawebservices.PessoaFisica, error)
+}
+
+// Avaliador looks up and evaluates CPFs against its rules.
+type Avaliador struct {
+	client Client
+	regras Regras
+	agora  func() time.Time
+}
+
+func NewAvaliador(client Client, regras Regras) *Avaliador {
+	return &Avaliador{client: client, regras: regras, agora: time.Now}
+}
+
+// Avaliar looks up the CPF of the given Solicitacao and evaluates it. An
+// invalid CPF or a date of birth that does not match the CPF are rejected;
+// any other error of the lookup is returned.
+func (a *Avaliador) Avaliar(ctx context.Context, s Solicitacao) (Registro, error) {
+	s.CPF = documento.SomenteDigitos(s.CPF)
+	pf, err := a.client.ConsultarCPF(ctx, s.CPF, s.DataNascimento)
+	switch {
+	case err == nil:
+		return a.regras.Avaliar(s, pf, a.agora()), nil
+	case errors.Is(err, soawebservices.ErrCPFInvalido):
+		return a.rejeitar(s, CodigoCPFInvalido, "o CPF informado é inválido"), nil
+	case errors.Is(err, soawebservices.ErrDataNascimentoInvalida):
+		return a.rejeitar(s, CodigoDataNascimentoDivergente, "a data de nascimento diverge da RFB"), nil
+	}
+	return Registro{}, err
+}
+
+func (a *Avaliador) rejeitar(s Solicitacao, codigo Codigo, descricao string) Registro {
+	av := &avaliacao{registro: Registro{Solicitacao: s, Regras: a.regras, Decisao: Aprovar, AvaliadoEm: a.agora().UTC()}}
+	av.motivo(codigo, Rejeitar, descricao)
+	return av.concluir()
+}
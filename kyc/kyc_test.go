@@ -0,0 +1,283 @@
+package kyc_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/diegohordi/soawebservices"
+	"github.com/diegohordi/soawebservices/kyc"
+	"github.com/diegohordi/soawebservices/soawebservicestest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var agora = time.Date(2022, 6, 15, 12, 0, 0, 0, time.UTC)
+
+func pessoa(status soawebservices.PessoaFisicaStatus, nascimento time.Time) soawebservices.PessoaFisica {
+	return soawebservices.PessoaFisica{
+		Documento:      "52998224725",
+		Nome:           "JOSÉ DA SILVA SAURO",
+		DataNascimento: nascimento,
+		Status:         status,
+	}
+}
+
+func TestRegras_Avaliar(t *testing.T) {
+	adulto := time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+	falecido := pessoa(soawebservices.Regular, adulto)
+	falecido.AnoObito = 2019
+	obitoIlegivel := pessoa(soawebservices.Regular, adulto)
+	obitoIlegivel.AnoObitoTexto = "20l9"
+	tests := []struct {
+		name        string
+		solicitacao kyc.Solicitacao
+		pf          soawebservices.PessoaFisica
+		want        kyc.Decisao
+		wantCodigos []kyc.Codigo
+	}{
+		{
+			name:        "should approve a regular adult with a matching name",
+			solicitacao: kyc.Solicitacao{CPF: "52998224725", Nome: "Jose Silva Sauro"},
+			pf:          pessoa(soawebservices.Regular, adulto),
+			want:        kyc.Aprovar,
+			wantCodigos: []kyc.Codigo{},
+		},
+		{
+			name:        "should review a CPF pending regularization",
+			solicitacao: kyc.Solicitacao{CPF: "52998224725"},
+			pf:          pessoa(soawebservices.PendenteRegularizacao, adulto),
+			want:        kyc.Revisar,
+			wantCodigos: []kyc.Codigo{kyc.CodigoStatusRevisao},
+		},
+		{
+			name:        "should review a diverging name",
+			solicitacao: kyc.Solicitacao{CPF: "52998224725", Nome: "Maria Souza"},
+			pf:          pessoa(soawebservices.Regular, adulto),
+			want:        kyc.Revisar,
+			wantCodigos: []kyc.Codigo{kyc.CodigoNomeDivergente},
+		},
+		{
+			name:        "should review an unknown date of birth",
+			solicitacao: kyc.Solicitacao{CPF: "52998224725"},
+			pf:          pessoa(soawebservices.Regular, time.Time{}),
+			want:        kyc.Revisar,
+			wantCodigos: []kyc.Codigo{kyc.CodigoDataNascimentoAusente},
+		},
+		{
+			name:        "should reject a minor",
+			solicitacao: kyc.Solicitacao{CPF: "52998224725"},
+			pf:          pessoa(soawebservices.Regular, time.Date(2004, 6, 16, 0, 0, 0, 0, time.UTC)),
+			want:        kyc.Rejeitar,
+			wantCodigos: []kyc.Codigo{kyc.CodigoIdadeMinima},
+		},
+		{
+			name:        "should reject a suspended CPF",
+			solicitacao: kyc.Solicitacao{CPF: "52998224725", Nome: "Maria Souza"},
+			pf:          pessoa(soawebservices.Suspensa, adulto),
+			want:        kyc.Rejeitar,
+			wantCodigos: []kyc.Codigo{kyc.CodigoStatusNaoPermitido, kyc.CodigoNomeDivergente},
+		},
+		{
+			name:        "should reject a CPF with death records",
+			solicitacao: kyc.Solicitacao{CPF: "52998224725"},
+			pf:          falecido,
+			want:        kyc.Rejeitar,
+			wantCodigos: []kyc.Codigo{kyc.CodigoObito},
+		},
+		{
+			name:        "should reject a CPF with a death record whose year is malformed",
+			solicitacao: kyc.Solicitacao{CPF: "52998224725"},
+			pf:          obitoIlegivel,
+			want:        kyc.Rejeitar,
+			wantCodigos: []kyc.Codigo{kyc.CodigoObito},
+		},
+		{
+			name:        "should reject a CPF of a deceased holder",
+			solicitacao: kyc.Solicitacao{CPF: "52998224725"},
+			pf:          pessoa(soawebservices.TitularFalecido, adulto),
+			want:        kyc.Rejeitar,
+			wantCodigos: []kyc.Codigo{kyc.CodigoObito, kyc.CodigoStatusNaoPermitido},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			registro := kyc.DefaultRegras().Avaliar(tt.solicitacao, tt.pf, agora)
+			if registro.Decisao != tt.want {
+				t.Errorf("Decisao got = %v, want %v", registro.Decisao, tt.want)
+			}
+			codigos := []kyc.Codigo{}
+			for _, m := range registro.Motivos {
+				codigos = append(codigos, m.Codigo)
+			}
+			if !reflect.DeepEqual(codigos, tt.wantCodigos) {
+				t.Errorf("Motivos got = %v, want %v", codigos, tt.wantCodigos)
+			}
+		})
+	}
+}
+
+func TestRegras_Avaliar_idade(t *testing.T) {
+	pf := pessoa(soawebservices.Regular, time.Date(2004, 6, 15, 0, 0, 0, 0, soawebservices.SaoPaulo))
+	tests := []struct {
+		name        string
+		agora       time.Time
+		want        kyc.Decisao
+		wantCodigos []kyc.Codigo
+	}{
+		{
+			name:        "should reject before the birthday starts in São Paulo",
+			agora:       time.Date(2022, 6, 15, 2, 59, 0, 0, time.UTC),
+			want:        kyc.Rejeitar,
+			wantCodigos: []kyc.Codigo{kyc.CodigoIdadeMinima},
+		},
+		{
+			name:  "should approve once the birthday starts in São Paulo",
+			agora: time.Date(2022, 6, 15, 3, 0, 0, 0, time.UTC),
+			want:  kyc.Aprovar,
+		},
+		{
+			name:  "should approve regardless of the location of the clock",
+			agora: time.Date(2022, 6, 14, 23, 0, 0, 0, time.FixedZone("-04", -4*60*60)),
+			want:  kyc.Aprovar,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			registro := kyc.DefaultRegras().Avaliar(kyc.Solicitacao{CPF: "52998224725"}, pf, tt.agora)
+			if registro.Decisao != tt.want {
+				t.Errorf("Decisao got = %v, want %v", registro.Decisao, tt.want)
+			}
+			var codigos []kyc.Codigo
+			for _, m := range registro.Motivos {
+				codigos = append(codigos, m.Codigo)
+			}
+			if !reflect.DeepEqual(codigos, tt.wantCodigos) {
+				t.Errorf("Motivos got = %v, want %v", codigos, tt.wantCodigos)
+			}
+		})
+	}
+}
+
+func TestRegistro_Integro(t *testing.T) {
+	registro := kyc.DefaultRegras().Avaliar(kyc.Solicitacao{CPF: "52998224725", Nome: "Jose Silva Sauro"}, pessoa(soawebservices.Regular, time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)), agora)
+	buf, err := json.Marshal(registro)
+	if err != nil {
+		t.Fatal(err)
+	}
+	armazenado := kyc.Registro{}
+	if err = json.Unmarshal(buf, &armazenado); err != nil {
+		t.Fatal(err)
+	}
+	if !armazenado.Integro() {
+		t.Error("expected the stored record to be intact")
+	}
+	armazenado.Decisao = kyc.Rejeitar
+	if armazenado.Integro() {
+		t.Error("expected the changed record not to be intact")
+	}
+	if again := kyc.DefaultRegras().Avaliar(registro.Solicitacao, pessoa(soawebservices.Regular, time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)), agora); again.Digest != registro.Digest {
+		t.Error("expected the same evaluation to have the same digest")
+	}
+}
+
+func TestRegistro_Integro_codigos(t *testing.T) {
+	// registro was stored before PessoaFisicaStatus was encoded by its
+	// symbolic name.
+	registro := kyc.Registro{
+		Solicitacao: kyc.Solicitacao{CPF: "52998224725", Nome: "Jose Silva Sauro", DataNascimento: time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)},
+		Regras: kyc.Regras{
+			Versao:           "1",
+			StatusAprovados:  []soawebservices.PessoaFisicaStatus{soawebservices.Regular},
+			StatusRevisao:    []soawebservices.PessoaFisicaStatus{soawebservices.PendenteRegularizacao, soawebservices.DadosIncompletos},
+			IdadeMinima:      18,
+			SimilaridadeNome: 0.8,
+		},
+		Status:           soawebservices.Regular,
+		SituacaoRFB:      "REGULAR",
+		Nome:             "JOSE DA SILVA SAURO",
+		SimilaridadeNome: 1,
+		Decisao:          kyc.Aprovar,
+		Motivos:          []kyc.Motivo{},
+		AvaliadoEm:       time.Date(2022, 6, 15, 12, 0, 0, 0, time.UTC),
+		Digest:           "9b62de8e839d8ceda5be570cfced9da1095dd5cea1a9b7e93af48f896fd0aa3e",
+	}
+	if !registro.Integro() {
+		t.Error("expected the record stored before to be intact")
+	}
+	desconhecido := kyc.DefaultRegras().Avaliar(kyc.Solicitacao{CPF: "52998224725"}, pessoa("14", time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)), agora)
+	if !desconhecido.Integro() {
+		t.Error("expected the record of an unknown status to be intact")
+	}
+	buf, err := json.Marshal(desconhecido)
+	if err != nil {
+		t.Fatal(err)
+	}
+	armazenado := kyc.Registro{}
+	if err = json.Unmarshal(buf, &armazenado); err != nil {
+		t.Fatal(err)
+	}
+	if armazenado.Status != "14" || !armazenado.Integro() {
+		t.Errorf("expected the stored record of an unknown status to be intact, got %s", buf)
+	}
+	desconhecido.Decisao = kyc.Aprovar
+	if desconhecido.Integro() {
+		t.Error("expected the changed record of an unknown status not to be intact")
+	}
+}
+
+func TestAvaliador_Avaliar(t *testing.T) {
+	nascimento := time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+	fake := soawebservicestest.NewFakeClient()
+	fake.OnCPF("11144477735").ReturnErr(soawebservices.ErrDataNascimentoInvalida)
+	fake.OnCPF("39053344705").ReturnErr(soawebservices.ErrCredenciaisInvalidas)
+	avaliador := kyc.NewAvaliador(fake, kyc.DefaultRegras())
+	tests := []struct {
+		name       string
+		cpf        string
+		want       kyc.Decisao
+		wantCodigo kyc.Codigo
+		wantErr    error
+	}{
+		{
+			name:       "should reject an invalid CPF",
+			cpf:        "111.111.111-12",
+			want:       kyc.Rejeitar,
+			wantCodigo: kyc.CodigoCPFInvalido,
+		},
+		{
+			name:       "should reject a date of birth diverging from the RFB",
+			cpf:        "111.444.777-35",
+			want:       kyc.Rejeitar,
+			wantCodigo: kyc.CodigoDataNascimentoDivergente,
+		},
+		{
+			name:    "should fail due to the failed lookup",
+			cpf:     "390.533.447-05",
+			wantErr: soawebservices.ErrCredenciaisInvalidas,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			registro, err := avaliador.Avaliar(context.TODO(), kyc.Solicitacao{CPF: tt.cpf, DataNascimento: nascimento})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if registro.Decisao != tt.want || len(registro.Motivos) != 1 || registro.Motivos[0].Codigo != tt.wantCodigo {
+				t.Errorf("unexpected record %+v", registro)
+			}
+			if !registro.Integro() {
+				t.Error("expected the record to be intact")
+			}
+		})
+	}
+}
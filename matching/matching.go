@@ -0,0 +1,217 @@
+// Package matching compares the names typed by users with the names in the
+// RFB, regardless of case, accents, particles and abbreviations.
+package matching
+
+import (
+	"github.com/diegohordi/soawebservices"
+	"github.com/diegohordi/soawebservices/internal/texto"
+	"strings"
+)
+
+const (
+	// LimiarCompativel is the minimum score of a compatible name.
+	LimiarCompativel = 0.9
+	// LimiarParcial is the minimum score of a name partially compatible,
+	// which should be reviewed.
+	LimiarParcial = 0.75
+)
+
+// particulas are the particles ignored when comparing names.
+var particulas = map[string]bool{"D": true, "DA": true, "DAS": true, "DE": true, "DI": true, "DO": true, "DOS": true, "E": true}
+
+// abreviaturas expands the usual abbreviations of names.
+var abreviaturas = map[string]string{
+	"JR":  "JUNIOR",
+	"JN":  "JUNIOR",
+	"FO":  "FILHO",
+	"FL":  "FILHO",
+	"NT":  "NETO",
+	"SOB": "SOBRINHO",
+	"STA": "SANTA",
+	"STO": "SANTO",
+	"MA":  "MARIA",
+}
+
+// Tokens returns the words of the given name in upper case and without
+// accents, punctuation, particles and abbreviations.
+func Tokens(nome string) []string {
+	var tokens []string
+	for _, t := range texto.Tokens(nome) {
+		if particulas[t] {
+			continue
+		}
+		if expandida, ok := abreviaturas[t]; ok {
+			t = expandida
+		}
+		tokens = append(tokens, t)
+	}
+	return tokens
+}
+
+// Normalizar returns the tokens of the given name joined by single spaces.
+func Normalizar(nome string) string {
+	return strings.Join(Tokens(nome), " ")
+}
+
+// JaroWinkler returns the Jaro-Winkler similarity of a and b, from 0 to 1,
+// favouring the strings with a common prefix of up to 4 characters.
+func JaroWinkler(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 && len(rb) == 0 {
+		return 1
+	}
+	if len(ra) == 0 || len(rb) == 0 {
+		return 0
+	}
+	janela := len(ra)
+	if len(rb) > janela {
+		janela = len(rb)
+	}
+	janela = janela/2 - 1
+	if janela < 0 {
+		janela = 0
+	}
+	casadosA := make([]bool, len(ra))
+	casadosB := make([]bool, len(rb))
+	casados := 0
+	for i := range ra {
+		de, ate := i-janela, i+janela+1
+		if de < 0 {
+			de = 0
+		}
+		if ate > len(rb) {
+			ate = len(rb)
+		}
+		for j := de; j < ate; j++ {
+			if !casadosB[j] && ra[i] == rb[j] {
+				casadosA[i], casadosB[j] = true, true
+				casados++
+				break
+			}
+		}
+	}
+	if casados == 0 {
+		return 0
+	}
+	transposicoes, j := 0, 0
+	for i := range ra {
+		if !casadosA[i] {
+			continue
+		}
+		for !casadosB[j] {
+			j++
+		}
+		if ra[i] != rb[j] {
+			transposicoes++
+		}
+		j++
+	}
+	m := float64(casados)
+	jaro := (m/float64(len(ra)) + m/float64(len(rb)) + (m-float64(transposicoes)/2)/m) / 3
+	prefixo := 0
+	for prefixo < 4 && prefixo < len(ra) && prefixo < len(rb) && ra[prefixo] == rb[prefixo] {
+		prefixo++
+	}
+	return jaro + float64(prefixo)*0.1*(1-jaro)
+}
+
+// similaridadeToken returns the similarity of two words, an initial matching
+// the words it starts and words too different not matching at all.
+func similaridadeToken(a, b string) float64 {
+	switch {
+	case a == b:
+		return 1
+	case len(a) == 1 && strings.HasPrefix(b, a), len(b) == 1 && strings.HasPrefix(a, b):
+		return 0.9
+	case len(a) == 1 || len(b) == 1:
+		return 0
+	}
+	if s := JaroWinkler(a, b); s >= 0.85 {
+		return s
+	}
+	return 0
+}
+
+// TokenSet returns the similarity of the words of a and b regardless of their
+// order: each word of the shorter name is paired with the most similar word
+// not yet paired of the other, and the similarities of the pairs are summed
+// as in the Dice coefficient, so missing words lower the score.
+func TokenSet(a, b string) float64 {
+	ta, tb := Tokens(a), Tokens(b)
+	if len(ta)+len(tb) == 0 {
+		return 1
+	}
+	if len(ta) > len(tb) {
+		ta, tb = tb, ta
+	}
+	usados := make([]bool, len(tb))
+	soma := 0.0
+	for _, t := range ta {
+		melhor, indice := 0.0, -1
+		for j, u := range tb {
+			if usados[j] {
+				continue
+			}
+			if s := similaridadeToken(t, u); s > melhor {
+				melhor, indice = s, j
+			}
+		}
+		if indice >= 0 {
+			usados[indice] = true
+			soma += melhor
+		}
+	}
+	return 2 * soma / float64(len(ta)+len(tb))
+}
+
+// Similaridade returns the similarity of two names, from 0 to 1, as the mean
+// of the Jaro-Winkler similarity of the normalized names, which tolerates
+// typos, and of their TokenSet similarity, which tolerates words out of order,
+// abbreviated or missing.
+func Similaridade(a, b string) float64 {
+	return (JaroWinkler(Normalizar(a), Normalizar(b)) + TokenSet(a, b)) / 2
+}
+
+// Veredito classifies the score of a name.
+type Veredito string
+
+const (
+	Compativel   Veredito = "COMPATIVEL"
+	Parcial      Veredito = "PARCIAL"
+	Incompativel Veredito = "INCOMPATIVEL"
+)
+
+// VereditoDe returns the Veredito of the given score.
+func VereditoDe(score float64) Veredito {
+	switch {
+	case score >= LimiarCompativel:
+		return Compativel
+	case score >= LimiarParcial:
+		return Parcial
+	}
+	return Incompativel
+}
+
+// Resultado is the result of MatchNome. Nome is the name of the RFB closest to
+// the informed one, the Nome or the NomeSocial of the person.
+type Resultado struct {
+	Score    float64
+	Veredito Veredito
+	Nome     string
+}
+
+// MatchNome compares the informed name with the Nome and the NomeSocial of
+// the given person, keeping the closest one.
+func MatchNome(pf soawebservices.PessoaFisica, informado string) Resultado {
+	resultado := Resultado{Nome: pf.Nome}
+	if strings.TrimSpace(informado) != "" {
+		resultado.Score = Similaridade(informado, pf.Nome)
+		if pf.NomeSocial != "" {
+			if social := Similaridade(informado, pf.NomeSocial); social > resultado.Score {
+				resultado.Score, resultado.Nome = social, pf.NomeSocial
+			}
+		}
+	}
+	resultado.Veredito = VereditoDe(resultado.Score)
+	return resultado
+}
@@ -0,0 +1,95 @@
+package matching_test
+
+import (
+	"github.com/diegohordi/soawebservices"
+	"github.com/diegohordi/soawebservices/matching"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestTokens(t *testing.T) {
+	tests := []struct {
+		nome string
+		want []string
+	}{
+		{nome: "José  da Silva", want: []string{"JOSE", "SILVA"}},
+		{nome: "joão d'ávila dos santos jr.", want: []string{"JOAO", "AVILA", "SANTOS", "JUNIOR"}},
+		{nome: "Ma. Conceição Fo", want: []string{"MARIA", "CONCEICAO", "FILHO"}},
+		{nome: "", want: nil},
+	}
+	for _, tt := range tests {
+		if got := matching.Tokens(tt.nome); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Tokens(%q) got = %v, want %v", tt.nome, got, tt.want)
+		}
+	}
+}
+
+func TestJaroWinkler(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{a: "MARTHA", b: "MARHTA", want: 0.961},
+		{a: "DWAYNE", b: "DUANE", want: 0.840},
+		{a: "DIXON", b: "DICKSONX", want: 0.813},
+		{a: "JOSE", b: "JOSE", want: 1},
+		{a: "ABC", b: "XYZ", want: 0},
+		{a: "", b: "", want: 1},
+	}
+	for _, tt := range tests {
+		if got := matching.JaroWinkler(tt.a, tt.b); math.Abs(got-tt.want) > 0.001 {
+			t.Errorf("JaroWinkler(%q, %q) got = %.3f, want %.3f", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestSimilaridade(t *testing.T) {
+	tests := []struct {
+		name      string
+		informado string
+		want      matching.Veredito
+	}{
+		{name: "should match accents, case and spaces", informado: "  jose da  silva sauro", want: matching.Compativel},
+		{name: "should match a typo", informado: "Jose da Silav Sauro", want: matching.Compativel},
+		{name: "should match an abbreviated name", informado: "J. Silva Sauro", want: matching.Compativel},
+		{name: "should partially match a missing surname", informado: "Jose Silva", want: matching.Parcial},
+		{name: "should partially match words out of order", informado: "Silva Sauro, Jose", want: matching.Parcial},
+		{name: "should not match another first name", informado: "Maria da Silva Sauro", want: matching.Incompativel},
+		{name: "should not match another name", informado: "Maria Souza", want: matching.Incompativel},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			score := matching.Similaridade(tt.informado, "JOSÉ DA SILVA SAURO")
+			if got := matching.VereditoDe(score); got != tt.want {
+				t.Errorf("got = %v (%.3f), want %v", got, score, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchNome(t *testing.T) {
+	pf := soawebservices.PessoaFisica{Nome: "JOÃO CARLOS PEREIRA", NomeSocial: "JOANA PEREIRA"}
+	tests := []struct {
+		name      string
+		informado string
+		want      matching.Veredito
+		wantNome  string
+	}{
+		{name: "should match the name", informado: "Joao Carlos Pereira", want: matching.Compativel, wantNome: "JOÃO CARLOS PEREIRA"},
+		{name: "should match the social name", informado: "Joana Pereira", want: matching.Compativel, wantNome: "JOANA PEREIRA"},
+		{name: "should not match an empty name", informado: " ", want: matching.Incompativel, wantNome: "JOÃO CARLOS PEREIRA"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := matching.MatchNome(pf, tt.informado)
+			if got.Veredito != tt.want || got.Nome != tt.wantNome {
+				t.Errorf("MatchNome() got = %+v, want %v for %s", got, tt.want, tt.wantNome)
+			}
+		})
+	}
+}
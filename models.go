@@ -45,17 +45,24 @@ type Credenciais struct {
 	Senha string `xml:"Senha"`
 }
 
+// credenciaisRequest holds the Credenciais sent in the requests, which must not
+// be redacted.
+type credenciaisRequest struct {
+	Email string `xml:"Email" json:"Email"`
+	Senha string `xml:"Senha" json:"Senha"`
+}
+
 type consultaCEPEstendida struct {
-	XMLName     xml.Name    `xml:"ConsultaCEPEstendida"`
-	Namespace   string      `xml:"xmlns,attr"`
-	Credenciais Credenciais `xml:"Credenciais"`
-	Cep         string      `xml:"CEP"`
+	XMLName     xml.Name           `xml:"ConsultaCEPEstendida"`
+	Namespace   string             `xml:"xmlns,attr"`
+	Credenciais credenciaisRequest `xml:"Credenciais"`
+	Cep         string             `xml:"CEP"`
 }
 
 func newConsultaCEPEstendida(credenciais Credenciais, cep string) *consultaCEPEstendida {
 	return &consultaCEPEstendida{
 		Namespace:   defaultNamespace,
-		Credenciais: credenciais,
+		Credenciais: credenciaisRequest(credenciais),
 		Cep:         cep,
 	}
 }
@@ -86,15 +93,61 @@ type CEP struct {
 	CodigoIBGE            string
 }
 
+type consultaLogradouro struct {
+	XMLName     xml.Name           `xml:"ConsultaLogradouro"`
+	Namespace   string             `xml:"xmlns,attr"`
+	Credenciais credenciaisRequest `xml:"Credenciais"`
+	UF          string             `xml:"UF"`
+	Cidade      string             `xml:"Cidade"`
+	Logradouro  string             `xml:"Logradouro"`
+}
+
+func newConsultaLogradouro(credenciais Credenciais, uf, cidade, logradouro string) *consultaLogradouro {
+	return &consultaLogradouro{
+		Namespace:   defaultNamespace,
+		Credenciais: credenciaisRequest(credenciais),
+		UF:          uf,
+		Cidade:      cidade,
+		Logradouro:  logradouro,
+	}
+}
+
+type enderecoResult struct {
+	Cep                   string `xml:"CEP"`
+	UF                    string `xml:"UF"`
+	TipoLogradouro        string `xml:"TipoLogradouro"`
+	Logradouro            string `xml:"Logradouro"`
+	LogradouroCompleto    string `xml:"LogradouroCompleto"`
+	LogradouroComplemento string `xml:"LogradouroComplemento"`
+	Bairro                string `xml:"Bairro"`
+	Cidade                string `xml:"Cidade"`
+	CodigoIBGE            string `xml:"CodigoIBGE"`
+}
+
+type consultaLogradouroResult struct {
+	XMLName   xml.Name         `xml:"ConsultaLogradouroResult"`
+	Enderecos []enderecoResult `xml:"Enderecos>Endereco"`
+	Mensagem  string           `xml:"Mensagem"`
+	Status    bool             `xml:"Status"`
+	Transacao transacao        `xml:"Transacao"`
+}
+
+// CEPEncontrado is a CEP found by BuscarCEPPorLogradouro, with the relevance
+// of its logradouro to the searched one, from 0 to 1.
+type CEPEncontrado struct {
+	CEP
+	Relevancia float64
+}
+
 type consultaPessoaFisicaNFe struct {
-	Credenciais    Credenciais `json:"Credenciais"`
-	Documento      string      `json:"Documento"`
-	DataNascimento string      `json:"DataNascimento"`
+	Credenciais    credenciaisRequest `json:"Credenciais"`
+	Documento      string             `json:"Documento"`
+	DataNascimento string             `json:"DataNascimento"`
 }
 
 func newConsultaPessoaFisicaNFe(credenciais Credenciais, documento string, dataNascimento string) *consultaPessoaFisicaNFe {
 	return &consultaPessoaFisicaNFe{
-		Credenciais:    credenciais,
+		Credenciais:    credenciaisRequest(credenciais),
 		Documento:      documento,
 		DataNascimento: dataNascimento,
 	}
@@ -104,13 +157,13 @@ type pessoaFisicaResult struct {
 	Documento               string    `json:"Documento"`
 	Nome                    string    `json:"Nome"`
 	NomeSocial              string    `json:"NomeSocial"`
-	DataNascimento          string    `json:"DataNascimento"`
-	DataInscricao           string    `json:"DataInscricao"`
+	DataNascimento          Data      `json:"DataNascimento"`
+	DataInscricao           Data      `json:"DataInscricao"`
 	AnoObito                string    `json:"AnoObito"`
 	MensagemObito           string    `json:"MensagemObito"`
 	CodigoSituacaoCadastral string    `json:"CodigoSituacaoCadastral"`
 	SituacaoRFB             string    `json:"SituacaoRFB"`
-	DataConsultaRFB         time.Time `json:"DataConsultaRFB"`
+	DataConsultaRFB         Data      `json:"DataConsultaRFB"`
 	ProtocoloRFB            string    `json:"ProtocoloRFB"`
 	DigitoVerificador       string    `json:"DigitoVerificador"`
 	DIRPF                   string    `json:"DIRPF"`
@@ -135,28 +188,40 @@ const (
 	DadosIncompletos             PessoaFisicaStatus = "13"
 )
 
+// PessoaFisica is a person registered in the RFB. AnoObito and MensagemObito
+// are set when the RFB has records of the death of the person, and
+// AnoObitoTexto keeps the AnoObito received when it is not a year. Avisos
+// lists the malformed dates and numbers of the response, read as the zero
+// value.
 type PessoaFisica struct {
-	Documento      string
-	Nome           string
-	NomeSocial     string
-	DataNascimento time.Time
-	Status         PessoaFisicaStatus
+	Documento       string
+	Nome            string
+	NomeSocial      string
+	DataNascimento  time.Time
+	DataInscricao   time.Time
+	Status          PessoaFisicaStatus
+	SituacaoRFB     string
+	DataConsultaRFB time.Time
+	AnoObito        int
+	AnoObitoTexto   string
+	MensagemObito   string
+	Avisos          []string
 }
 
 type consultaPessoaJuridicaNFe struct {
-	Credenciais Credenciais `json:"Credenciais"`
-	Documento   string      `json:"Documento"`
+	Credenciais credenciaisRequest `json:"Credenciais"`
+	Documento   string             `json:"Documento"`
 }
 
 func newConsultaPessoaJuridicaNFe(credenciais Credenciais, documento string) *consultaPessoaJuridicaNFe {
-	return &consultaPessoaJuridicaNFe{Credenciais: credenciais, Documento: documento}
+	return &consultaPessoaJuridicaNFe{Credenciais: credenciaisRequest(credenciais), Documento: documento}
 }
 
 type pessoaJuridicaResult struct {
 	Documento                         string    `json:"Documento"`
 	RazaoSocial                       string    `json:"RazaoSocial"`
 	NomeFantasia                      string    `json:"NomeFantasia"`
-	DataFundacao                      string    `json:"DataFundacao"`
+	DataFundacao                      Data      `json:"DataFundacao"`
 	MatrizFilial                      string    `json:"MatrizFilial"`
 	Capital                           string    `json:"Capital"`
 	CodigoAtividadeEconomica          string    `json:"CodigoAtividadeEconomica"`
@@ -164,17 +229,32 @@ type pessoaJuridicaResult struct {
 	CodigoNaturezaJuridica            string    `json:"CodigoNaturezaJuridica"`
 	CodigoNaturezaJuridicaDescricao   string    `json:"CodigoNaturezaJuridicaDescricao"`
 	SituacaoRFB                       string    `json:"SituacaoRFB"`
-	DataSituacaoRFB                   string    `json:"DataSituacaoRFB"`
-	DataConsultaRFB                   string    `json:"DataConsultaRFB"`
+	DataSituacaoRFB                   Data      `json:"DataSituacaoRFB"`
+	DataConsultaRFB                   Data      `json:"DataConsultaRFB"`
 	MotivoSituacaoRFB                 string    `json:"MotivoSituacaoRFB"`
-	DataMotivoEspecialSituacaoRFB     string    `json:"DataMotivoEspecialSituacaoRFB"`
+	DataMotivoEspecialSituacaoRFB     Data      `json:"DataMotivoEspecialSituacaoRFB"`
 	Email                             string    `json:"Email"`
 	Telefone                          string    `json:"Telefone"`
+	QSA                               qsaResult `json:"QSA"`
 	Mensagem                          string    `json:"Mensagem"`
 	Status                            bool      `json:"Status"`
 	Transacao                         transacao `json:"Transacao"`
 }
 
+type qsaResult struct {
+	Socios []struct {
+		Pessoa    int    `json:"Pessoa"`
+		Documento string `json:"Documento"`
+		Nome      string `json:"Nome"`
+	} `json:"Socios"`
+	Administradores []struct {
+		Pessoa    int    `json:"Pessoa"`
+		Documento string `json:"Documento"`
+		Nome      string `json:"Nome"`
+		Cargo     string `json:"Cargo"`
+	} `json:"Administradores"`
+}
+
 type CNAE struct {
 	Codigo    string
 	Descricao string
@@ -185,14 +265,511 @@ type NaturezaJuridica struct {
 	Descricao string
 }
 
+// TipoPessoa tells whether a member of a QSA is a natural or a legal person.
+type TipoPessoa int
+
+const (
+	TipoPessoaFisica   TipoPessoa = 1
+	TipoPessoaJuridica TipoPessoa = 2
+)
+
+// Socio is a partner of a company, identified by its CPF or CNPJ.
+type Socio struct {
+	Pessoa    TipoPessoa
+	Documento string
+	Nome      string
+}
+
+// Administrador is a manager of a company, such as its president or director.
+type Administrador struct {
+	Socio
+	Cargo string
+}
+
+// QSA is the Quadro de Sócios e Administradores of a company.
+type QSA struct {
+	Socios          []Socio
+	Administradores []Administrador
+}
+
+// PessoaJuridica is a company registered in the RFB. Avisos lists the
+// malformed dates of the response, read as the zero time.
 type PessoaJuridica struct {
-	Documento        string
-	RazaoSocial      string
-	NomeFantasia     string
-	DataFundacao     time.Time
-	Matriz           bool
-	CNAE             CNAE
-	NaturezaJuridica NaturezaJuridica
-	Email            string
-	Telefone         string
+	Documento         string
+	RazaoSocial       string
+	NomeFantasia      string
+	DataFundacao      time.Time
+	Matriz            bool
+	CNAE              CNAE
+	NaturezaJuridica  NaturezaJuridica
+	SituacaoRFB       string
+	DataSituacaoRFB   time.Time
+	MotivoSituacaoRFB string
+	DataConsultaRFB   time.Time
+	// DataMotivoEspecialSituacaoRFB is the date of the special situation of
+	// the company in the RFB, if any.
+	DataMotivoEspecialSituacaoRFB time.Time
+	Email                         string
+	Telefone                      string
+	QSA                           QSA
+	Avisos                        []string
+}
+
+type consultaVeiculo struct {
+	Credenciais credenciaisRequest `json:"Credenciais"`
+	Placa       string             `json:"Placa"`
+}
+
+func newConsultaVeiculo(credenciais Credenciais, placa string) *consultaVeiculo {
+	return &consultaVeiculo{Credenciais: credenciaisRequest(credenciais), Placa: placa}
+}
+
+type veiculoResult struct {
+	Placa         string    `json:"Placa"`
+	Renavam       string    `json:"Renavam"`
+	Chassi        string    `json:"Chassi"`
+	Marca         string    `json:"Marca"`
+	Modelo        string    `json:"Modelo"`
+	AnoFabricacao string    `json:"AnoFabricacao"`
+	AnoModelo     string    `json:"AnoModelo"`
+	Cor           string    `json:"Cor"`
+	Combustivel   string    `json:"Combustivel"`
+	Municipio     string    `json:"Municipio"`
+	UF            string    `json:"UF"`
+	Restricoes    []string  `json:"Restricoes"`
+	Mensagem      string    `json:"Mensagem"`
+	Status        bool      `json:"Status"`
+	Transacao     transacao `json:"Transacao"`
+}
+
+// Veiculo is a vehicle registered in the DETRAN of its UF. Restricoes lists
+// its restrictions, such as alienação fiduciária or roubo e furto. Avisos
+// lists the malformed years of the response, read as 0.
+type Veiculo struct {
+	Placa         string
+	Renavam       string
+	Chassi        string
+	Marca         string
+	Modelo        string
+	AnoFabricacao int
+	AnoModelo     int
+	Cor           string
+	Combustivel   string
+	Municipio     string
+	UF            string
+	Restricoes    []string
+	Avisos        []string
+}
+
+type consultaDocumento struct {
+	Credenciais credenciaisRequest `json:"Credenciais"`
+	Documento   string             `json:"Documento"`
+}
+
+func newConsultaDocumento(credenciais Credenciais, documento string) *consultaDocumento {
+	return &consultaDocumento{Credenciais: credenciaisRequest(credenciais), Documento: documento}
+}
+
+type scoreResult struct {
+	Documento                  string    `json:"Documento"`
+	Score                      int       `json:"Score"`
+	Faixa                      string    `json:"Faixa"`
+	ProbabilidadeInadimplencia string    `json:"ProbabilidadeInadimplencia"`
+	DataConsulta               Data      `json:"DataConsulta"`
+	Mensagem                   string    `json:"Mensagem"`
+	Status                     bool      `json:"Status"`
+	Transacao                  transacao `json:"Transacao"`
+}
+
+type restricoesResult struct {
+	Documento string `json:"Documento"`
+	Protestos []struct {
+		Cartorio string `json:"Cartorio"`
+		Cidade   string `json:"Cidade"`
+		UF       string `json:"UF"`
+		Valor    string `json:"Valor"`
+		Data     Data   `json:"Data"`
+	} `json:"Protestos"`
+	ChequesSemFundo []struct {
+		Banco                string `json:"Banco"`
+		Agencia              string `json:"Agencia"`
+		Quantidade           int    `json:"Quantidade"`
+		DataUltimaOcorrencia Data   `json:"DataUltimaOcorrencia"`
+	} `json:"ChequesSemFundo"`
+	Pendencias []struct {
+		Tipo     string `json:"Tipo"`
+		Credor   string `json:"Credor"`
+		Contrato string `json:"Contrato"`
+		Valor    string `json:"Valor"`
+		Data     Data   `json:"Data"`
+	} `json:"Pendencias"`
+	Mensagem  string    `json:"Mensagem"`
+	Status    bool      `json:"Status"`
+	Transacao transacao `json:"Transacao"`
+}
+
+// Score is the credit score of a CPF or a CNPJ, from 0 to 1000, along with
+// its risk class and the probability of default, in percent. Avisos lists the
+// malformed dates and numbers of the response, read as the zero value, so a
+// ProbabilidadeInadimplencia of 0 with an Aviso is unknown, not 0%.
+type Score struct {
+	Documento                  string
+	Pontuacao                  int
+	Faixa                      string
+	ProbabilidadeInadimplencia float64
+	Data                       time.Time
+	Avisos                     []string
+}
+
+// Protesto is a protest of a debt registered in a cartório.
+type Protesto struct {
+	Cartorio string
+	Cidade   string
+	UF       string
+	Valor    Valor
+	Data     time.Time
+}
+
+// ChequeSemFundo holds the bounced checks of an account registered in the CCF.
+type ChequeSemFundo struct {
+	Banco            string
+	Agencia          string
+	Quantidade       int
+	UltimaOcorrencia time.Time
+}
+
+// Pendencia is a negative record of a debt, such as PEFIN or REFIN.
+type Pendencia struct {
+	Tipo     string
+	Credor   string
+	Contrato string
+	Valor    Valor
+	Data     time.Time
+}
+
+// Restricoes holds the credit restrictions of a CPF or a CNPJ. Avisos lists
+// the malformed dates of the response, read as the zero time.
+type Restricoes struct {
+	Documento       string
+	Protestos       []Protesto
+	ChequesSemFundo []ChequeSemFundo
+	Pendencias      []Pendencia
+	Avisos          []string
+}
+
+// Possui reports whether there is any restriction.
+func (r Restricoes) Possui() bool {
+	return len(r.Protestos) > 0 || len(r.ChequesSemFundo) > 0 || len(r.Pendencias) > 0
+}
+
+// ValorTotal returns the sum of the protests and of the pending debts.
+func (r Restricoes) ValorTotal() Valor {
+	total := Valor(0)
+	for _, p := range r.Protestos {
+		total += p.Valor
+	}
+	for _, p := range r.Pendencias {
+		total += p.Valor
+	}
+	return total
+}
+
+type simplesNacionalResult struct {
+	Documento      string `json:"Documento"`
+	OptanteSimples string `json:"OptanteSimples"`
+	OptanteSimei   string `json:"OptanteSimei"`
+	Periodos       []struct {
+		Regime       string `json:"Regime"`
+		DataInicial  Data   `json:"DataInicial"`
+		DataFinal    Data   `json:"DataFinal"`
+		Detalhamento string `json:"Detalhamento"`
+	} `json:"Periodos"`
+	Mensagem  string    `json:"Mensagem"`
+	Status    bool      `json:"Status"`
+	Transacao transacao `json:"Transacao"`
+}
+
+type RegimeSimples string
+
+const (
+	RegimeSimplesNacional RegimeSimples = "SIMPLES NACIONAL"
+	RegimeMEI             RegimeSimples = "SIMEI"
+)
+
+// PeriodoSimples is a period in which a CNPJ was in a regime, from the start
+// of the day of Inicio to the end of the day of Fim. Periods still in force
+// have a zero Fim.
+type PeriodoSimples struct {
+	Regime       RegimeSimples
+	Inicio       time.Time
+	Fim          time.Time
+	Detalhamento string
+}
+
+// SimplesNacional holds whether a CNPJ is optante pelo Simples Nacional or MEI
+// and the history of its periods in each regime. Avisos lists the malformed
+// dates of the response, read as the zero time.
+type SimplesNacional struct {
+	Documento      string
+	OptanteSimples bool
+	OptanteMEI     bool
+	Periodos       []PeriodoSimples
+	Avisos         []string
+}
+
+// Optante reports whether the CNPJ was in the given regime at the given date.
+func (s SimplesNacional) Optante(regime RegimeSimples, data time.Time) bool {
+	for _, p := range s.Periodos {
+		if p.Regime == regime && !data.Before(p.Inicio) && (p.Fim.IsZero() || data.Before(fimDoDia(p.Fim))) {
+			return true
+		}
+	}
+	return false
+}
+
+type consultaInscricaoEstadual struct {
+	Credenciais credenciaisRequest `json:"Credenciais"`
+	UF          string             `json:"UF"`
+	Documento   string             `json:"Documento"`
+}
+
+func newConsultaInscricaoEstadual(credenciais Credenciais, uf, documento string) *consultaInscricaoEstadual {
+	return &consultaInscricaoEstadual{Credenciais: credenciaisRequest(credenciais), UF: uf, Documento: documento}
+}
+
+type inscricaoEstadualResult struct {
+	UF                  string    `json:"UF"`
+	InscricaoEstadual   string    `json:"InscricaoEstadual"`
+	CNPJ                string    `json:"CNPJ"`
+	RazaoSocial         string    `json:"RazaoSocial"`
+	Situacao            string    `json:"Situacao"`
+	RegimeApuracao      string    `json:"RegimeApuracao"`
+	DataInicioAtividade Data      `json:"DataInicioAtividade"`
+	DataHabilitacao     Data      `json:"DataHabilitacao"`
+	DataSituacao        Data      `json:"DataSituacao"`
+	Mensagem            string    `json:"Mensagem"`
+	Status              bool      `json:"Status"`
+	Transacao           transacao `json:"Transacao"`
+}
+
+type SituacaoIE string
+
+const (
+	IEHabilitada    SituacaoIE = "HABILITADO"
+	IENaoHabilitada SituacaoIE = "NAO HABILITADO"
+	IESuspensa      SituacaoIE = "SUSPENSO"
+	IEBaixada       SituacaoIE = "BAIXADO"
+)
+
+// InscricaoEstadual is the state registration of a company in the SINTEGRA of
+// its UF. Avisos lists the malformed dates of the response, read as the zero
+// time.
+type InscricaoEstadual struct {
+	UF                  string
+	Inscricao           string
+	CNPJ                string
+	RazaoSocial         string
+	Situacao            SituacaoIE
+	RegimeApuracao      string
+	DataInicioAtividade time.Time
+	DataHabilitacao     time.Time
+	DataSituacao        time.Time
+	Avisos              []string
+}
+
+// Habilitada reports whether the Inscrição Estadual is enabled, as required
+// from the recipients of a NF-e.
+func (ie InscricaoEstadual) Habilitada() bool {
+	return ie.Situacao == IEHabilitada
+}
+
+type telefonesResult struct {
+	Documento string `json:"Documento"`
+	Telefones []struct {
+		DDD       string `json:"DDD"`
+		Numero    string `json:"Numero"`
+		Tipo      string `json:"Tipo"`
+		Operadora string `json:"Operadora"`
+	} `json:"Telefones"`
+	Mensagem  string    `json:"Mensagem"`
+	Status    bool      `json:"Status"`
+	Transacao transacao `json:"Transacao"`
+}
+
+type emailsResult struct {
+	Documento string `json:"Documento"`
+	Emails    []struct {
+		Email string `json:"Email"`
+	} `json:"Emails"`
+	Mensagem  string    `json:"Mensagem"`
+	Status    bool      `json:"Status"`
+	Transacao transacao `json:"Transacao"`
+}
+
+type pepResult struct {
+	Documento string `json:"Documento"`
+	Nome      string `json:"Nome"`
+	Registros []struct {
+		Cargo       string `json:"Cargo"`
+		Orgao       string `json:"Orgao"`
+		Fonte       string `json:"Fonte"`
+		DataInicial Data   `json:"DataInicial"`
+		DataFinal   Data   `json:"DataFinal"`
+	} `json:"Registros"`
+	Mensagem  string    `json:"Mensagem"`
+	Status    bool      `json:"Status"`
+	Transacao transacao `json:"Transacao"`
+}
+
+type sancoesResult struct {
+	Documento string `json:"Documento"`
+	Registros []struct {
+		Lista         string `json:"Lista"`
+		Tipo          string `json:"Tipo"`
+		Orgao         string `json:"Orgao"`
+		Fundamentacao string `json:"Fundamentacao"`
+		Processo      string `json:"Processo"`
+		DataInicial   Data   `json:"DataInicial"`
+		DataFinal     Data   `json:"DataFinal"`
+	} `json:"Registros"`
+	Mensagem  string    `json:"Mensagem"`
+	Status    bool      `json:"Status"`
+	Transacao transacao `json:"Transacao"`
+}
+
+// Periodo is the validity period of a record, from the start of the day of
+// Inicio to the end of the day of Fim. Records still in force have a zero Fim.
+type Periodo struct {
+	Inicio time.Time
+	Fim    time.Time
+}
+
+// Vigente reports whether the period includes the given date.
+func (p Periodo) Vigente(data time.Time) bool {
+	return !data.Before(p.Inicio) && (p.Fim.IsZero() || data.Before(fimDoDia(p.Fim)))
+}
+
+// fimDoDia returns the start of the day after the given date, in its location.
+func fimDoDia(t time.Time) time.Time {
+	ano, mes, dia := t.Date()
+	return time.Date(ano, mes, dia+1, 0, 0, 0, 0, t.Location())
+}
+
+// RegistroPEP is a role held by a Politically Exposed Person, with the list it
+// was found in. The person remains exposed for 5 years after leaving it.
+type RegistroPEP struct {
+	Periodo
+	Cargo string
+	Orgao string
+	Fonte string
+}
+
+// PEP holds the roles that make a CPF a Politically Exposed Person. Avisos
+// lists the malformed dates of the response, read as the zero time.
+type PEP struct {
+	Documento string
+	Nome      string
+	Registros []RegistroPEP
+	Avisos    []string
+}
+
+// Exposta reports whether the person was politically exposed at the given
+// date, counting the 5 years after leaving each role.
+func (p PEP) Exposta(data time.Time) bool {
+	for _, r := range p.Registros {
+		periodo := r.Periodo
+		if !periodo.Fim.IsZero() {
+			periodo.Fim = periodo.Fim.AddDate(5, 0, 0)
+		}
+		if periodo.Vigente(data) {
+			return true
+		}
+	}
+	return false
+}
+
+type ListaSancoes string
+
+const (
+	ListaCEIS ListaSancoes = "CEIS"
+	ListaCNEP ListaSancoes = "CNEP"
+)
+
+// Sancao is a sanction of a CPF or a CNPJ in a sanction list.
+type Sancao struct {
+	Periodo
+	Lista         ListaSancoes
+	Tipo          string
+	Orgao         string
+	Fundamentacao string
+	Processo      string
+}
+
+// Sancoes holds the sanctions of a CPF or a CNPJ. Avisos lists the malformed
+// dates of the response, read as the zero time.
+type Sancoes struct {
+	Documento string
+	Registros []Sancao
+	Avisos    []string
+}
+
+// Vigentes returns the sanctions in force at the given date.
+func (s Sancoes) Vigentes(data time.Time) []Sancao {
+	var vigentes []Sancao
+	for _, r := range s.Registros {
+		if r.Vigente(data) {
+			vigentes = append(vigentes, r)
+		}
+	}
+	return vigentes
+}
+
+type consultaConta struct {
+	Credenciais credenciaisRequest `json:"Credenciais"`
+	DataInicial string             `json:"DataInicial,omitempty"`
+	DataFinal   string             `json:"DataFinal,omitempty"`
+}
+
+func newConsultaConta(credenciais Credenciais, inicio, fim time.Time) *consultaConta {
+	consulta := &consultaConta{Credenciais: credenciaisRequest(credenciais)}
+	if !inicio.IsZero() {
+		consulta.DataInicial = inicio.Format("02/01/2006")
+	}
+	if !fim.IsZero() {
+		consulta.DataFinal = fim.Format("02/01/2006")
+	}
+	return consulta
+}
+
+type saldoResult struct {
+	Saldo     string    `json:"Saldo"`
+	Mensagem  string    `json:"Mensagem"`
+	Status    bool      `json:"Status"`
+	Transacao transacao `json:"Transacao"`
+}
+
+type consumoResult struct {
+	Consumos []struct {
+		Data       Data   `json:"Data"`
+		Produto    string `json:"Produto"`
+		Quantidade int    `json:"Quantidade"`
+		Valor      string `json:"Valor"`
+	} `json:"Consumos"`
+	Mensagem  string    `json:"Mensagem"`
+	Status    bool      `json:"Status"`
+	Transacao transacao `json:"Transacao"`
+}
+
+type Saldo struct {
+	Disponivel Valor
+}
+
+// Consumo holds the calls to a product billed in a day. Avisos lists the
+// malformed dates of the record, read as the zero time.
+type Consumo struct {
+	Data       time.Time
+	Produto    string
+	Quantidade int
+	Valor      Valor
+	Avisos     []string
 }
@@ -0,0 +1,112 @@
+package soawebservices
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	ErrPessoaFisicaStatusDesconhecido = Error("situação cadastral desconhecida")
+)
+
+// pessoaFisicaStatusInfo holds the symbolic name, the RFB description and
+// whether a PessoaFisicaStatus blocks the CPF.
+type pessoaFisicaStatusInfo struct {
+	nome      string
+	descricao string
+	bloqueia  bool
+}
+
+var pessoasFisicasStatus = map[PessoaFisicaStatus]pessoaFisicaStatusInfo{
+	Regular:                      {nome: "REGULAR", descricao: "REGULAR"},
+	Suspensa:                     {nome: "SUSPENSA", descricao: "SUSPENSA", bloqueia: true},
+	TitularFalecido:              {nome: "TITULAR_FALECIDO", descricao: "TITULAR FALECIDO", bloqueia: true},
+	CanceladaPorMultiplicidade:   {nome: "CANCELADA_POR_MULTIPLICIDADE", descricao: "CANCELADA POR MULTIPLICIDADE", bloqueia: true},
+	PendenteRegularizacao:        {nome: "PENDENTE_REGULARIZACAO", descricao: "PENDENTE DE REGULARIZAÇÃO"},
+	CanceladaOficio:              {nome: "CANCELADA_OFICIO", descricao: "CANCELADA DE OFÍCIO", bloqueia: true},
+	CanceladaEncerramento:        {nome: "CANCELADA_ENCERRAMENTO", descricao: "CANCELADA POR ENCERRAMENTO DE ESPÓLIO", bloqueia: true},
+	Cancelada:                    {nome: "CANCELADA", descricao: "CANCELADA", bloqueia: true},
+	Nula:                         {nome: "NULA", descricao: "NULA", bloqueia: true},
+	SituacaoCadastralInexistente: {nome: "SITUACAO_CADASTRAL_INEXISTENTE", descricao: "SITUAÇÃO CADASTRAL INEXISTENTE", bloqueia: true},
+	DadosIncompletos:             {nome: "DADOS_INCOMPLETOS", descricao: "DADOS INCOMPLETOS"},
+}
+
+// ParsePessoaFisicaStatus parses a PessoaFisicaStatus from its RFB code, as
+// in "1", or from its symbolic name, as in "REGULAR". An empty string parses
+// as the empty status, returned when the RFB does not inform it, and any
+// other unknown value fails with ErrPessoaFisicaStatusDesconhecido.
+func ParsePessoaFisicaStatus(s string) (PessoaFisicaStatus, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return "", nil
+	}
+	if _, ok := pessoasFisicasStatus[PessoaFisicaStatus(s)]; ok {
+		return PessoaFisicaStatus(s), nil
+	}
+	for status, info := range pessoasFisicasStatus {
+		if strings.EqualFold(info.nome, s) {
+			return status, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %q", ErrPessoaFisicaStatusDesconhecido, s)
+}
+
+// String returns the RFB description of the status, as in "TITULAR
+// FALECIDO", or the code of an unknown status.
+func (s PessoaFisicaStatus) String() string {
+	if info, ok := pessoasFisicasStatus[s]; ok {
+		return info.descricao
+	}
+	if s == "" {
+		return ""
+	}
+	return fmt.Sprintf("DESCONHECIDA (%s)", string(s))
+}
+
+// IsRegular reports whether the CPF is regular.
+func (s PessoaFisicaStatus) IsRegular() bool {
+	return s == Regular
+}
+
+// Blocks reports whether the status prevents the CPF from being accepted, as
+// the cancelled, suspended or null ones and the ones of deceased holders.
+// Unknown statuses block the CPF too.
+func (s PessoaFisicaStatus) Blocks() bool {
+	info, ok := pessoasFisicasStatus[s]
+	return !ok && s != "" || info.bloqueia
+}
+
+// prefixoDesconhecida prefixes the code of the unknown statuses in their
+// symbolic encoding.
+const prefixoDesconhecida = "DESCONHECIDA_"
+
+// MarshalText encodes the status as its symbolic name. Unknown statuses, as
+// the ones returned along with ErrPessoaFisicaStatusDesconhecido, are encoded
+// as their code prefixed by DESCONHECIDA_, as in "DESCONHECIDA_14", so the
+// values holding them can still be encoded.
+func (s PessoaFisicaStatus) MarshalText() ([]byte, error) {
+	if s == "" {
+		return []byte{}, nil
+	}
+	info, ok := pessoasFisicasStatus[s]
+	if !ok {
+		return []byte(prefixoDesconhecida + string(s)), nil
+	}
+	return []byte(info.nome), nil
+}
+
+// UnmarshalText decodes the status from its code or its symbolic name, as
+// ParsePessoaFisicaStatus does, or from the encoding of an unknown status by
+// MarshalText.
+func (s *PessoaFisicaStatus) UnmarshalText(text []byte) error {
+	if codigo := strings.TrimPrefix(string(text), prefixoDesconhecida); codigo != string(text) && codigo != "" {
+		*s = PessoaFisicaStatus(codigo)
+		return nil
+	}
+	status, err := ParsePessoaFisicaStatus(string(text))
+	if err != nil {
+		return err
+	}
+	*s = status
+	return nil
+}
@@ -0,0 +1,94 @@
+package soawebservices_test
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/diegohordi/soawebservices"
+	"testing"
+)
+
+func TestParsePessoaFisicaStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    soawebservices.PessoaFisicaStatus
+		wantErr error
+	}{
+		{name: "should parse a code", s: "3", want: soawebservices.TitularFalecido},
+		{name: "should parse a symbolic name", s: "pendente_regularizacao", want: soawebservices.PendenteRegularizacao},
+		{name: "should parse an empty status", s: " ", want: ""},
+		{name: "should fail due to an unknown code", s: "14", wantErr: soawebservices.ErrPessoaFisicaStatusDesconhecido},
+		{name: "should fail due to an unknown name", s: "ATIVA", wantErr: soawebservices.ErrPessoaFisicaStatusDesconhecido},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := soawebservices.ParsePessoaFisicaStatus(tt.s)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParsePessoaFisicaStatus() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPessoaFisicaStatus(t *testing.T) {
+	tests := []struct {
+		status      soawebservices.PessoaFisicaStatus
+		wantString  string
+		wantRegular bool
+		wantBlocks  bool
+	}{
+		{status: soawebservices.Regular, wantString: "REGULAR", wantRegular: true},
+		{status: soawebservices.PendenteRegularizacao, wantString: "PENDENTE DE REGULARIZAÇÃO"},
+		{status: soawebservices.TitularFalecido, wantString: "TITULAR FALECIDO", wantBlocks: true},
+		{status: soawebservices.CanceladaOficio, wantString: "CANCELADA DE OFÍCIO", wantBlocks: true},
+		{status: "", wantString: ""},
+		{status: "14", wantString: "DESCONHECIDA (14)", wantBlocks: true},
+	}
+	for _, tt := range tests {
+		if got := tt.status.String(); got != tt.wantString {
+			t.Errorf("String() got = %q, want %q", got, tt.wantString)
+		}
+		if got := tt.status.IsRegular(); got != tt.wantRegular {
+			t.Errorf("%q IsRegular() got = %v, want %v", tt.status, got, tt.wantRegular)
+		}
+		if got := tt.status.Blocks(); got != tt.wantBlocks {
+			t.Errorf("%q Blocks() got = %v, want %v", tt.status, got, tt.wantBlocks)
+		}
+	}
+}
+
+func TestPessoaFisicaStatus_json(t *testing.T) {
+	pf := soawebservices.PessoaFisica{Documento: "52998224725", Status: soawebservices.CanceladaPorMultiplicidade}
+	buf, err := json.Marshal(pf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := soawebservices.PessoaFisica{}
+	if err = json.Unmarshal(buf, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Status != soawebservices.CanceladaPorMultiplicidade {
+		t.Errorf("expected %q, got %q in %s", soawebservices.CanceladaPorMultiplicidade, decoded.Status, buf)
+	}
+	var status soawebservices.PessoaFisicaStatus
+	if err = json.Unmarshal([]byte(`"CANCELADA_POR_MULTIPLICIDADE"`), &status); err != nil || status != soawebservices.CanceladaPorMultiplicidade {
+		t.Errorf("expected %q, got %q (%v)", soawebservices.CanceladaPorMultiplicidade, status, err)
+	}
+	if err = json.Unmarshal([]byte(`"14"`), &status); !errors.Is(err, soawebservices.ErrPessoaFisicaStatusDesconhecido) {
+		t.Errorf("expected error %v, got %v", soawebservices.ErrPessoaFisicaStatusDesconhecido, err)
+	}
+	if buf, err = json.Marshal(soawebservices.PessoaFisica{Status: "14"}); err != nil {
+		t.Fatal(err)
+	}
+	if err = json.Unmarshal(buf, &decoded); err != nil || decoded.Status != "14" {
+		t.Errorf("expected %q, got %q (%v) in %s", "14", decoded.Status, err, buf)
+	}
+	if err = json.Unmarshal([]byte(`"DESCONHECIDA_14"`), &status); err != nil || status != "14" {
+		t.Errorf("expected %q, got %q (%v)", "14", status, err)
+	}
+}
@@ -0,0 +1,64 @@
+package soawebservices
+
+import (
+	"context"
+	"fmt"
+	"github.com/diegohordi/soawebservices/internal/documento"
+	"sort"
+)
+
+const (
+	urlSimplesNacional = "cdc/simplesnacional.ashx"
+)
+
+func parseSimplesNacionalResult(result *simplesNacionalResult, datas *leitorDatas) (SimplesNacional, error) {
+	if err := parseDocumentoTransacao(result.Status, result.Transacao, ErrCNPJInvalido); err != nil {
+		return SimplesNacional{}, err
+	}
+	simples := SimplesNacional{
+		Documento:      result.Documento,
+		OptanteSimples: result.OptanteSimples == "SIM",
+		OptanteMEI:     result.OptanteSimei == "SIM",
+	}
+	for i, p := range result.Periodos {
+		simples.Periodos = append(simples.Periodos, PeriodoSimples{
+			Regime:       RegimeSimples(p.Regime),
+			Inicio:       datas.ler(fmt.Sprintf("Periodos[%d].DataInicial", i), p.DataInicial),
+			Fim:          datas.ler(fmt.Sprintf("Periodos[%d].DataFinal", i), p.DataFinal),
+			Detalhamento: p.Detalhamento,
+		})
+	}
+	sort.SliceStable(simples.Periodos, func(i, j int) bool {
+		return simples.Periodos[i].Inicio.Before(simples.Periodos[j].Inicio)
+	})
+	if datas.err != nil {
+		return SimplesNacional{}, datas.err
+	}
+	simples.Avisos = datas.avisos
+	return simples, nil
+}
+
+// ConsultarSimplesNacional returns whether the given CNPJ is optante pelo
+// Simples Nacional or MEI, along with its periods in each regime from the
+// oldest to the newest. CNPJs with invalid check digits fail with
+// ErrCNPJInvalido without calling the service.
+func (d *defaultClient) ConsultarSimplesNacional(ctx context.Context, cnpj string) (SimplesNacional, error) {
+	if !documento.CNPJValido(cnpj) {
+		return SimplesNacional{}, ErrCNPJInvalido
+	}
+	credenciais, err := d.credenciais(ctx)
+	if err != nil {
+		return SimplesNacional{}, err
+	}
+	custo, err := d.usage.reservar(ServicoSimplesNacional)
+	if err != nil {
+		return SimplesNacional{}, err
+	}
+	simples := SimplesNacional{}
+	result, err := d.consultarDocumento(ctx, credenciais, urlSimplesNacional, documento.SomenteDigitos(cnpj), func() interface{} { return &simplesNacionalResult{} })
+	if err == nil {
+		simples, err = parseSimplesNacionalResult(result.(*simplesNacionalResult), &leitorDatas{estrito: d.datasEstritas})
+	}
+	d.usage.registrar(ctx, ServicoSimplesNacional, custo, err)
+	return simples, err
+}
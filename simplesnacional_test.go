@@ -0,0 +1,94 @@
+package soawebservices_test
+
+import (
+	"context"
+	"errors"
+	"github.com/diegohordi/soawebservices"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_defaultClient_ConsultarSimplesNacional(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		cnpj    string
+		want    soawebservices.SimplesNacional
+		wantErr error
+	}{
+		{
+			name: "should return the regime history",
+			file: "consultasimplesnacional_success.json",
+			cnpj: "99.999.999/9999-62",
+			want: soawebservices.SimplesNacional{
+				Documento:      "99999999999962",
+				OptanteSimples: true,
+				Periodos: []soawebservices.PeriodoSimples{
+					{
+						Regime:       soawebservices.RegimeMEI,
+						Inicio:       time.Date(2017, 5, 10, 0, 0, 0, 0, soawebservices.SaoPaulo),
+						Fim:          time.Date(2019, 12, 31, 0, 0, 0, 0, soawebservices.SaoPaulo),
+						Detalhamento: "Desenquadrado do SIMEI por excesso de receita",
+					},
+					{
+						Regime:       soawebservices.RegimeSimplesNacional,
+						Inicio:       time.Date(2020, 1, 1, 0, 0, 0, 0, soawebservices.SaoPaulo),
+						Detalhamento: "Opção pelo Simples Nacional",
+					},
+				},
+			},
+		},
+		{
+			name:    "should fail due to the given invalid CNPJ without calling the service",
+			file:    "consultasimplesnacional_success.json",
+			cnpj:    "99.999.999/9999-99",
+			wantErr: soawebservices.ErrCNPJInvalido,
+		},
+		{
+			name:    "should fail due to wrong credentials",
+			file:    "consultacnpj_wrong_credentials.json",
+			cnpj:    "99999999999962",
+			wantErr: soawebservices.ErrCredenciaisInvalidas,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			client := MustCreateClient(fixtureClient(t, tt.file))
+			result, err := client.ConsultarSimplesNacional(context.TODO(), tt.cnpj)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if !reflect.DeepEqual(result, tt.want) {
+				t.Error("want ", tt.want, " but got ", result)
+			}
+		})
+	}
+}
+
+func TestSimplesNacional_Optante(t *testing.T) {
+	simples := soawebservices.SimplesNacional{
+		Periodos: []soawebservices.PeriodoSimples{
+			{Regime: soawebservices.RegimeMEI, Inicio: time.Date(2017, 5, 10, 0, 0, 0, 0, time.UTC), Fim: time.Date(2019, 12, 31, 0, 0, 0, 0, time.UTC)},
+			{Regime: soawebservices.RegimeSimplesNacional, Inicio: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)},
+		},
+	}
+	tests := []struct {
+		regime soawebservices.RegimeSimples
+		data   time.Time
+		want   bool
+	}{
+		{regime: soawebservices.RegimeMEI, data: time.Date(2019, 12, 31, 0, 0, 0, 0, time.UTC), want: true},
+		{regime: soawebservices.RegimeMEI, data: time.Date(2019, 12, 31, 15, 0, 0, 0, time.UTC), want: true},
+		{regime: soawebservices.RegimeMEI, data: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), want: false},
+		{regime: soawebservices.RegimeSimplesNacional, data: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC), want: true},
+		{regime: soawebservices.RegimeSimplesNacional, data: time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC), want: false},
+	}
+	for _, tt := range tests {
+		if got := simples.Optante(tt.regime, tt.data); got != tt.want {
+			t.Errorf("Optante(%s, %s) got = %v, want %v", tt.regime, tt.data.Format("02/01/2006"), got, tt.want)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package soawebservices
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"time"
 )
@@ -15,6 +16,7 @@ const (
 
 type CEPService interface {
 	ConsultarCEP(ctx context.Context, cep string) (CEP, error)
+	BuscarCEPPorLogradouro(ctx context.Context, uf, cidade, logradouro string) (BuscaCEP, error)
 }
 
 type PessoaFisicaService interface {
@@ -23,26 +25,144 @@ type PessoaFisicaService interface {
 
 type PessoaJuridicaService interface {
 	ConsultarCNPJ(ctx context.Context, cnpj string) (PessoaJuridica, error)
+	ConsultarSimplesNacional(ctx context.Context, cnpj string) (SimplesNacional, error)
+	ConsultarInscricaoEstadual(ctx context.Context, uf, documento string) (InscricaoEstadual, error)
+}
+
+// CreditoService queries the credit risk of a CPF or a CNPJ.
+type CreditoService interface {
+	ConsultarScoreCPF(ctx context.Context, cpf string) (Score, error)
+	ConsultarScoreCNPJ(ctx context.Context, cnpj string) (Score, error)
+	ConsultarRestricoesCPF(ctx context.Context, cpf string) (Restricoes, error)
+	ConsultarRestricoesCNPJ(ctx context.Context, cnpj string) (Restricoes, error)
+}
+
+// ComplianceService screens a CPF or a CNPJ for AML purposes.
+type ComplianceService interface {
+	ConsultarPEP(ctx context.Context, cpf string) (PEP, error)
+	ConsultarSancoes(ctx context.Context, documento string) (Sancoes, error)
+}
+
+// ContatoService queries the contact data of a CPF or a CNPJ.
+type ContatoService interface {
+	ConsultarTelefones(ctx context.Context, documento string) ([]Telefone, error)
+	ConsultarEmails(ctx context.Context, documento string) ([]string, error)
+}
+
+type VeiculoService interface {
+	ConsultarVeiculo(ctx context.Context, placa string) (Veiculo, error)
+}
+
+// ContaService queries the prepaid account of the Credenciais.
+type ContaService interface {
+	ConsultarSaldo(ctx context.Context) (Saldo, error)
+	ConsultarConsumo(ctx context.Context, inicio, fim time.Time) ([]Consumo, error)
 }
 
 type Client interface {
 	CEPService
 	PessoaFisicaService
 	PessoaJuridicaService
+	CreditoService
+	ContatoService
+	ComplianceService
+	VeiculoService
+	ContaService
 }
 
 type defaultClient struct {
-	httpClient  *http.Client
-	baseURL     string
-	ambiente    Ambiente
-	credenciais Credenciais
+	httpClient *http.Client
+	baseURL    string
+	ambiente   Ambiente
+	provider   CredenciaisProvider
+	usage      *usageMeter
+	// datasEstritas fails the responses with malformed dates instead of
+	// reporting them as Avisos.
+	datasEstritas bool
+}
+
+// ClientOption configures the Client returned by NewClient and
+// NewClientWithProvider.
+type ClientOption func(*defaultClient)
+
+// WithTabelaPrecos sets the price of the calls made in the given ambiente,
+// which are charged to the spend reported by Usage. Calls made in TestDrive
+// always cost zero, and so do the calls to services missing from the table.
+func WithTabelaPrecos(ambiente Ambiente, tabela TabelaPrecos) ClientOption {
+	return func(d *defaultClient) {
+		if ambiente != d.ambiente || ambiente == TestDrive {
+			return
+		}
+		d.usage.precos = make(TabelaPrecos, len(tabela))
+		for servico, preco := range tabela {
+			d.usage.precos[servico] = preco
+		}
+	}
+}
+
+// WithOrcamento sets a hard budget for the spend of the Client. Once the
+// spend, including the calls in flight, reaches it, further calls fail with
+// an OrcamentoExcedidoError without calling the service.
+func WithOrcamento(orcamento Valor) ClientOption {
+	return func(d *defaultClient) {
+		d.usage.orcamento = orcamento
+	}
+}
+
+// WithDatasEstritas fails the lookups whose responses have malformed dates
+// or numbers with ErrDataInvalida or ErrNumeroInvalido. By default they
+// succeed, reading the malformed fields as the zero value and reporting them
+// as Avisos.
+func WithDatasEstritas() ClientOption {
+	return func(d *defaultClient) {
+		d.datasEstritas = true
+	}
+}
+
+// NewClient returns a Client bound to the given Credenciais, failing if they
+// are not valid.
+func NewClient(httpClient *http.Client, baseURL string, ambiente Ambiente, credenciais Credenciais, opts ...ClientOption) (Client, error) {
+	if err := credenciais.Validar(); err != nil {
+		return nil, fmt.Errorf("invalid credenciais: %w", err)
+	}
+	return NewClientWithProvider(httpClient, baseURL, ambiente, StaticCredenciais(credenciais), opts...), nil
+}
+
+// NewClientWithProvider returns a Client that resolves the Credenciais of each
+// call with the given provider. The returned Client also implements
+// UsageReporter, attributing the calls and their spend to the tenant and the
+// label in their context.
+func NewClientWithProvider(httpClient *http.Client, baseURL string, ambiente Ambiente, provider CredenciaisProvider, opts ...ClientOption) Client {
+	d := &defaultClient{
+		httpClient: httpClient,
+		baseURL:    baseURL,
+		ambiente:   ambiente,
+		provider:   provider,
+		usage:      newUsageMeter(),
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
 }
 
-func NewClient(httpClient *http.Client, baseURL string, ambiente Ambiente, credenciais Credenciais) Client {
-	return &defaultClient{
-		httpClient:  httpClient,
-		baseURL:     baseURL,
-		ambiente:    ambiente,
-		credenciais: credenciais,
+func (d *defaultClient) credenciais(ctx context.Context) (Credenciais, error) {
+	credenciais, err := d.provider.Credenciais(ctx)
+	if err == nil {
+		err = credenciais.Validar()
+	}
+	if err != nil {
+		return Credenciais{}, fmt.Errorf("could not resolve the credenciais: %w", err)
 	}
+	return credenciais, nil
+}
+
+// Format implements fmt.Formatter, so printing a Client never prints its
+// Credenciais.
+func (d *defaultClient) Format(f fmt.State, _ rune) {
+	_, _ = fmt.Fprintf(f, "soawebservices.Client{baseURL: %s, ambiente: %s, provider: %v}", d.baseURL, d.ambiente, d.provider)
+}
+
+func (d *defaultClient) Usage() []Usage {
+	return d.usage.snapshot()
 }
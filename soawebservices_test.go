@@ -16,7 +16,11 @@ func (f RoundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
 
 func MustCreateClient(httpClient *http.Client) soawebservices.Client {
 	credenciais := soawebservices.Credenciais{Email: "test@test.com", Senha: "test"}
-	return soawebservices.NewClient(httpClient, "https://soawebservices.com.br", soawebservices.TestDrive, credenciais)
+	client, err := soawebservices.NewClient(httpClient, "https://soawebservices.com.br", soawebservices.TestDrive, credenciais)
+	if err != nil {
+		panic(err)
+	}
+	return client
 }
 
 func MustLoadTestDataFile(t *testing.T, fileName string) []byte {
@@ -0,0 +1,349 @@
+package soawebservicestest
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/diegohordi/soawebservices"
+	"github.com/diegohordi/soawebservices/internal/documento"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	ErrInteractionNotFound = soawebservices.Error("no recorded interaction matches the request")
+	ErrEmptyKey            = soawebservices.Error("the cassette key must not be empty")
+)
+
+const (
+	redacted = "[REDACTED]"
+
+	// dataNascimentoMascarada replaces the birth dates, in a format the client
+	// still parses.
+	dataNascimentoMascarada = "01/01/1900"
+)
+
+var (
+	jsonCredenciaisPattern    = regexp.MustCompile(`("(?:Email|Senha)"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+	xmlCredenciaisPattern     = regexp.MustCompile(`(<(Email|Senha)>)[^<]*(</(?:Email|Senha)>)`)
+	jsonDocumentoPattern      = regexp.MustCompile(`("Documento"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+	jsonDadosPessoaisPattern  = regexp.MustCompile(`("(?:Nome|NomeSocial|NomeMae|Email|Telefone|Numero)"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+	jsonDataNascimentoPattern = regexp.MustCompile(`("DataNascimento"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+	documentosPattern         = regexp.MustCompile(`\b(?:\d{3}\.?\d{3}\.?\d{3}-?\d{2}|\d{2}\.?\d{3}\.?\d{3}/?\d{4}-?\d{2})\b`)
+)
+
+// Interaction is a recorded request/response exchange. Credentials are
+// redacted from the request body. In both bodies every CPF and CNPJ, formatted
+// or not, has its digits masked, and names, e-mails, phones and birth dates
+// are replaced. The document looked up is kept only as an HMAC-SHA256 of its
+// digits under the key of the cassette, which is what requests are matched
+// against on replay.
+type Interaction struct {
+	Servico       Servico                 `json:"servico"`
+	Ambiente      soawebservices.Ambiente `json:"ambiente"`
+	DocumentoHMAC string                  `json:"documento_hmac"`
+	Method        string                  `json:"method"`
+	Path          string                  `json:"path"`
+	RequestBody   string                  `json:"request_body"`
+	StatusCode    int                     `json:"status_code"`
+	ContentType   string                  `json:"content_type"`
+	ResponseBody  string                  `json:"response_body"`
+}
+
+// Cassette is a sequence of recorded interactions that can be stored as a
+// JSON file. Its documents are hashed with a key that is not stored in the
+// file: anyone holding both could test candidate CPFs against the cassette, so
+// the key must be kept as a secret, as an environment variable of the CI.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+func LoadCassette(path string) (*Cassette, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cassette := &Cassette{}
+	if err = json.Unmarshal(buf, cassette); err != nil {
+		return nil, fmt.Errorf("an error occurred while reading the cassette %s: %w", path, err)
+	}
+	return cassette, nil
+}
+
+func (c *Cassette) Save(path string) error {
+	buf, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(buf, '\n'), 0o600)
+}
+
+// NewKey returns a random key to record a cassette with.
+func NewKey() ([]byte, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// DocumentoHMAC returns the HMAC-SHA256 of the given document under the key of
+// a cassette, as kept in Interaction.DocumentoHMAC.
+func DocumentoHMAC(key []byte, documento string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(documento))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Recorder is an http.RoundTripper that forwards requests to the underlying
+// transport and records the exchanges into a Cassette.
+type Recorder struct {
+	mu        sync.Mutex
+	transport http.RoundTripper
+	key       []byte
+	cassette  Cassette
+}
+
+// NewRecorder returns a Recorder forwarding requests to the given transport, or
+// to http.DefaultTransport if it is nil, and hashing the documents with the
+// given key. It fails with ErrEmptyKey if the key is empty.
+func NewRecorder(transport http.RoundTripper, key []byte) (*Recorder, error) {
+	if len(key) == 0 {
+		return nil, ErrEmptyKey
+	}
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &Recorder{transport: transport, key: key}, nil
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	decoded, err := decodeExchange(req, body)
+	if err != nil {
+		return nil, err
+	}
+	outReq := req.Clone(req.Context())
+	outReq.Body = io.NopCloser(bytes.NewReader(body))
+	outReq.ContentLength = int64(len(body))
+	resp, err := r.transport.RoundTrip(outReq)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	documento := documentoSensivel(decoded)
+	interaction := Interaction{
+		Servico:       decoded.Servico,
+		Ambiente:      decoded.Ambiente,
+		DocumentoHMAC: DocumentoHMAC(r.key, decoded.Documento),
+		Method:        req.Method,
+		Path:          req.URL.Path,
+		RequestBody:   scrubRequestBody(string(body), documento),
+		StatusCode:    resp.StatusCode,
+		ContentType:   resp.Header.Get("Content-Type"),
+		ResponseBody:  maskDadosPessoais(string(respBody), documento),
+	}
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	r.mu.Unlock()
+	return resp, nil
+}
+
+// Cassette returns a copy of the interactions recorded so far.
+func (r *Recorder) Cassette() *Cassette {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	interactions := make([]Interaction, len(r.cassette.Interactions))
+	copy(interactions, r.cassette.Interactions)
+	return &Cassette{Interactions: interactions}
+}
+
+func (r *Recorder) Save(path string) error {
+	return r.Cassette().Save(path)
+}
+
+// Matcher reports whether a recorded interaction answers the given request,
+// given the key the cassette was recorded with.
+type Matcher func(key []byte, req Request, interaction Interaction) bool
+
+// MatchStrict matches on method, path, service, ambiente and document.
+func MatchStrict(key []byte, req Request, interaction Interaction) bool {
+	return req.Method == interaction.Method && req.Path == interaction.Path && req.Ambiente == interaction.Ambiente &&
+		MatchServicoDocumento(key, req, interaction)
+}
+
+// MatchServicoDocumento matches only on service and normalized document, so
+// cassettes recorded against an ambiente or base URL can be replayed against
+// another one.
+func MatchServicoDocumento(key []byte, req Request, interaction Interaction) bool {
+	return req.Servico == interaction.Servico && hmac.Equal([]byte(DocumentoHMAC(key, req.Documento)), []byte(interaction.DocumentoHMAC))
+}
+
+// Replayer is an http.RoundTripper serving the interactions of a Cassette,
+// without reaching the network. Interactions matching the same request are
+// served in the order they were recorded, repeating the last one once they are
+// exhausted. Requests without a matching interaction fail with
+// ErrInteractionNotFound.
+type Replayer struct {
+	mu       sync.Mutex
+	cassette *Cassette
+	key      []byte
+	match    Matcher
+	served   []int
+}
+
+// NewReplayer returns a Replayer for the given cassette, recorded with the
+// given key, matching requests with the given Matcher, or MatchStrict if it is
+// nil. It fails with ErrEmptyKey if the key is empty.
+func NewReplayer(cassette *Cassette, key []byte, match Matcher) (*Replayer, error) {
+	if len(key) == 0 {
+		return nil, ErrEmptyKey
+	}
+	if match == nil {
+		match = MatchStrict
+	}
+	return &Replayer{
+		cassette: cassette,
+		key:      key,
+		match:    match,
+		served:   make([]int, len(cassette.Interactions)),
+	}, nil
+}
+
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	decoded, err := decodeExchange(req, body)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	found, last := -1, -1
+	for i, interaction := range r.cassette.Interactions {
+		if !r.match(r.key, decoded, interaction) {
+			continue
+		}
+		if found == -1 && r.served[i] == 0 {
+			found = i
+		}
+		last = i
+	}
+	if found == -1 {
+		found = last
+	}
+	if found == -1 {
+		return nil, fmt.Errorf("%w: %s %s (servico %s, ambiente %s)", ErrInteractionNotFound, req.Method, req.URL.Path, decoded.Servico, decoded.Ambiente)
+	}
+	r.served[found]++
+	interaction := r.cassette.Interactions[found]
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", interaction.StatusCode, http.StatusText(interaction.StatusCode)),
+		StatusCode:    interaction.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{interaction.ContentType}},
+		Body:          io.NopCloser(strings.NewReader(interaction.ResponseBody)),
+		ContentLength: int64(len(interaction.ResponseBody)),
+		Request:       req,
+	}, nil
+}
+
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(req.Body)
+	return io.ReadAll(req.Body)
+}
+
+// decodeExchange decodes the request of an exchange. Requests to the endpoints
+// the Handler does not serve are decoded by decodeEndpoint.
+func decodeExchange(req *http.Request, body []byte) (Request, error) {
+	servico, ambiente, ok := parsePath(req.URL.Path)
+	if !ok {
+		return decodeEndpoint(req.Method, req.URL.Path, body), nil
+	}
+	return decodeRequest(req.Method, req.URL.Path, servico, ambiente, body)
+}
+
+// decodeEndpoint decodes a request to an endpoint the Handler does not serve.
+// Its Servico is the path of the endpoint below the ambiente, and its
+// Documento the Documento of the JSON body, if any, so it can still be matched
+// and masked.
+func decodeEndpoint(method, path string, body []byte) Request {
+	req := Request{
+		Method:     method,
+		Path:       path,
+		Servico:    Servico(path),
+		Body:       body,
+		ReceivedAt: time.Now(),
+	}
+	if parts := strings.SplitN(strings.TrimPrefix(path, "/"), "/", 3); len(parts) == 3 {
+		req.Servico, req.Ambiente = Servico(parts[2]), soawebservices.Ambiente(parts[1])
+	}
+	if consulta, err := unmarshalConsultaDocumento(body); err == nil {
+		req.Credenciais = soawebservices.Credenciais(consulta.Credenciais)
+		req.Documento = documento.SomenteDigitos(consulta.Documento)
+		req.DataNascimento = consulta.DataNascimento
+	}
+	return req
+}
+
+func documentoSensivel(req Request) string {
+	if req.Servico == ServicoCEP || req.Servico == ServicoBuscaCEP {
+		return ""
+	}
+	return req.Documento
+}
+
+func scrubRequestBody(body, documento string) string {
+	body = jsonCredenciaisPattern.ReplaceAllString(body, `${1}"`+redacted+`"`)
+	body = xmlCredenciaisPattern.ReplaceAllString(body, "${1}"+redacted+"${3}")
+	if documento != "" {
+		body = jsonDocumentoPattern.ReplaceAllString(body, `${1}"`+strings.Repeat("9", len(documento))+`"`)
+	}
+	return maskDadosPessoais(body, documento)
+}
+
+// maskDadosPessoais masks the document looked up and any other CPF or CNPJ,
+// formatted or not, keeping their format, and replaces the names, e-mails,
+// phones and birth dates of the body.
+func maskDadosPessoais(body, documento string) string {
+	if documento != "" {
+		body = strings.ReplaceAll(body, documento, strings.Repeat("9", len(documento)))
+	}
+	body = documentosPattern.ReplaceAllStringFunc(body, func(s string) string {
+		return strings.Map(func(r rune) rune {
+			if r >= '0' && r <= '9' {
+				return '9'
+			}
+			return r
+		}, s)
+	})
+	body = jsonDadosPessoaisPattern.ReplaceAllString(body, `${1}"`+redacted+`"`)
+	return jsonDataNascimentoPattern.ReplaceAllString(body, `${1}"`+dataNascimentoMascarada+`"`)
+}
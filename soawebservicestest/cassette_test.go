@@ -0,0 +1,266 @@
+package soawebservicestest_test
+
+import (
+	"context"
+	"errors"
+	"github.com/diegohordi/soawebservices"
+	"github.com/diegohordi/soawebservices/soawebservicestest"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fixtureTransport answers every request with the given fixture.
+type fixtureTransport string
+
+func (f fixtureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	buf, err := os.ReadFile(filepath.Join("../test/testdata", string(f)))
+	if err != nil {
+		return nil, err
+	}
+	resp := httptest.NewRecorder()
+	resp.Body.Write(buf)
+	result := resp.Result()
+	result.Request = req
+	return result, nil
+}
+
+func mustNewKey(t *testing.T) []byte {
+	key, err := soawebservicestest.NewKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func TestRecorder(t *testing.T) {
+	server := soawebservicestest.NewServer()
+	defer server.Close()
+	pj := soawebservices.PessoaJuridica{
+		Documento:   "39621470000109",
+		RazaoSocial: "EMPRESA DE TESTES",
+		Matriz:      true,
+		QSA: soawebservices.QSA{
+			Socios: []soawebservices.Socio{{Pessoa: soawebservices.TipoPessoaFisica, Documento: "529.982.247-25", Nome: "JOSE DA SILVA"}},
+			Administradores: []soawebservices.Administrador{
+				{Socio: soawebservices.Socio{Pessoa: soawebservices.TipoPessoaFisica, Documento: "11144477735", Nome: "MARIA DE TESTES"}, Cargo: "DIRETORA"},
+			},
+		},
+	}
+	server.AddPessoaJuridica(pj)
+	server.AddPessoaFisica(soawebservices.PessoaFisica{
+		Documento:      "52998224725",
+		Nome:           "JOSE DA SILVA",
+		DataNascimento: time.Date(1980, 5, 2, 0, 0, 0, 0, soawebservices.SaoPaulo),
+	})
+	server.AddCEP(soawebservices.CEP{CEP: "99999999", UF: "XX"})
+
+	key := mustNewKey(t)
+	recorder, err := soawebservicestest.NewRecorder(server.Client().Transport, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := soawebservices.NewClient(&http.Client{Transport: recorder}, server.URL, soawebservices.TestDrive, credenciais)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.ConsultarCNPJ(context.TODO(), "39.621.470/0001-09"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.ConsultarCPF(context.TODO(), "529.982.247-25", time.Date(1980, 5, 2, 0, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.ConsultarCEP(context.TODO(), "99999-999"); err != nil {
+		t.Fatal(err)
+	}
+
+	cassette := recorder.Cassette()
+	if len(cassette.Interactions) != 3 {
+		t.Fatalf("expected 3 interactions, got %d", len(cassette.Interactions))
+	}
+	secrets := []string{
+		credenciais.Email, credenciais.Senha, pj.Documento, "39.621.470/0001-09", "52998224725", "529.982.247-25",
+		"11144477735", "JOSE DA SILVA", "MARIA DE TESTES", "02/05/1980",
+	}
+	for _, interaction := range cassette.Interactions {
+		for _, secret := range secrets {
+			if strings.Contains(interaction.RequestBody, secret) || strings.Contains(interaction.ResponseBody, secret) {
+				t.Errorf("interaction %s was not scrubbed, found %q", interaction.Servico, secret)
+			}
+		}
+	}
+	if got, want := cassette.Interactions[0].DocumentoHMAC, soawebservicestest.DocumentoHMAC(key, pj.Documento); got != want {
+		t.Errorf("DocumentoHMAC got = %s, want %s", got, want)
+	}
+	if got, other := cassette.Interactions[0].DocumentoHMAC, soawebservicestest.DocumentoHMAC(mustNewKey(t), pj.Documento); got == other {
+		t.Error("expected the DocumentoHMAC to depend on the key")
+	}
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	if err := recorder.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := soawebservicestest.LoadCassette(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded, cassette) {
+		t.Error("want ", cassette, " but got ", loaded)
+	}
+}
+
+func TestNewRecorder(t *testing.T) {
+	if _, err := soawebservicestest.NewRecorder(nil, nil); !errors.Is(err, soawebservicestest.ErrEmptyKey) {
+		t.Errorf("NewRecorder() error = %v, wantErr %v", err, soawebservicestest.ErrEmptyKey)
+	}
+	if _, err := soawebservicestest.NewReplayer(&soawebservicestest.Cassette{}, nil, nil); !errors.Is(err, soawebservicestest.ErrEmptyKey) {
+		t.Errorf("NewReplayer() error = %v, wantErr %v", err, soawebservicestest.ErrEmptyKey)
+	}
+}
+
+func TestReplayer(t *testing.T) {
+	server := soawebservicestest.NewServer()
+	server.AddPessoaFisica(soawebservices.PessoaFisica{Documento: "99999999999", Nome: "DOCUMENTO CPF DE TESTE"})
+	key := mustNewKey(t)
+	recorder, err := soawebservicestest.NewRecorder(server.Client().Transport, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := soawebservices.NewClient(&http.Client{Transport: recorder}, server.URL, soawebservices.TestDrive, credenciais)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := client.ConsultarCPF(context.TODO(), "999.999.999-99", time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	server.Close()
+	want.Nome = "[REDACTED]"
+
+	tests := []struct {
+		name     string
+		matcher  soawebservicestest.Matcher
+		ambiente soawebservices.Ambiente
+		cpf      string
+		want     soawebservices.PessoaFisica
+		wantErr  error
+	}{
+		{
+			name:     "should replay the recorded interaction",
+			ambiente: soawebservices.TestDrive,
+			cpf:      "99999999999",
+			want:     want,
+		},
+		{
+			name:     "should fail due to an unmatched document",
+			ambiente: soawebservices.TestDrive,
+			cpf:      "111.111.111-11",
+			wantErr:  soawebservicestest.ErrInteractionNotFound,
+		},
+		{
+			name:     "should fail due to an unmatched ambiente",
+			ambiente: soawebservices.Producao,
+			cpf:      "999.999.999-99",
+			wantErr:  soawebservicestest.ErrInteractionNotFound,
+		},
+		{
+			name:     "should replay regardless of the ambiente when matching on service and document",
+			matcher:  soawebservicestest.MatchServicoDocumento,
+			ambiente: soawebservices.Producao,
+			cpf:      "999.999.999-99",
+			want:     want,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			replayer, err := soawebservicestest.NewReplayer(recorder.Cassette(), key, tt.matcher)
+			if err != nil {
+				t.Fatal(err)
+			}
+			client, err := soawebservices.NewClient(&http.Client{Transport: replayer}, "http://offline.invalid", tt.ambiente, credenciais)
+			if err != nil {
+				t.Fatal(err)
+			}
+			result, err := client.ConsultarCPF(context.TODO(), tt.cpf, time.Time{})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ConsultarCPF() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(result, tt.want) {
+				t.Error("want ", tt.want, " but got ", result)
+			}
+		})
+	}
+}
+
+func TestReplayer_endpoints(t *testing.T) {
+	tests := []struct {
+		name      string
+		fixture   string
+		consultar func(client soawebservices.Client, documento string) (interface{}, error)
+		documento string
+		outro     string
+	}{
+		{
+			name:    "should record and replay an endpoint the Handler does not serve",
+			fixture: "consultapep_success.json",
+			consultar: func(client soawebservices.Client, documento string) (interface{}, error) {
+				return client.ConsultarPEP(context.TODO(), documento)
+			},
+			documento: "529.982.247-25",
+			outro:     "111.444.777-35",
+		},
+		{
+			name:    "should record and replay the search of the CEPs of a street",
+			fixture: "buscacep_success.xml",
+			consultar: func(client soawebservices.Client, logradouro string) (interface{}, error) {
+				return client.BuscarCEPPorLogradouro(context.TODO(), "SP", "SAO PAULO", logradouro)
+			},
+			documento: "PAULISTA",
+			outro:     "AUGUSTA",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			key := mustNewKey(t)
+			recorder, err := soawebservicestest.NewRecorder(fixtureTransport(tt.fixture), key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			client, err := soawebservices.NewClient(&http.Client{Transport: recorder}, "https://soawebservices.com.br", soawebservices.TestDrive, credenciais)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := tt.consultar(client, tt.documento); err != nil {
+				t.Fatal(err)
+			}
+			cassette := recorder.Cassette()
+			if strings.Contains(cassette.Interactions[0].ResponseBody, "JOSE DA SILVA") {
+				t.Errorf("interaction %s was not scrubbed", cassette.Interactions[0].Servico)
+			}
+
+			replayer, err := soawebservicestest.NewReplayer(cassette, key, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			client, err = soawebservices.NewClient(&http.Client{Transport: replayer}, "https://soawebservices.com.br", soawebservices.TestDrive, credenciais)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := tt.consultar(client, tt.documento); err != nil {
+				t.Errorf("expected the recorded interaction to be replayed, got %v", err)
+			}
+			if _, err := tt.consultar(client, tt.outro); !errors.Is(err, soawebservicestest.ErrInteractionNotFound) {
+				t.Errorf("expected error %v, got %v", soawebservicestest.ErrInteractionNotFound, err)
+			}
+		})
+	}
+}
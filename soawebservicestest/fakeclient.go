@@ -0,0 +1,722 @@
+package soawebservicestest
+
+import (
+	"context"
+	"fmt"
+	"github.com/diegohordi/soawebservices"
+	"github.com/diegohordi/soawebservices/internal/documento"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	ErrUnexpectedCall = soawebservices.Error("unexpected call to the fake client")
+)
+
+const (
+	MethodConsultarCEP               = "ConsultarCEP"
+	MethodBuscarCEPPorLogradouro     = "BuscarCEPPorLogradouro"
+	MethodConsultarCPF               = "ConsultarCPF"
+	MethodConsultarCNPJ              = "ConsultarCNPJ"
+	MethodConsultarSimplesNacional   = "ConsultarSimplesNacional"
+	MethodConsultarInscricaoEstadual = "ConsultarInscricaoEstadual"
+	MethodConsultarScoreCPF          = "ConsultarScoreCPF"
+	MethodConsultarScoreCNPJ         = "ConsultarScoreCNPJ"
+	MethodConsultarRestricoesCPF     = "ConsultarRestricoesCPF"
+	MethodConsultarRestricoesCNPJ    = "ConsultarRestricoesCNPJ"
+	MethodConsultarTelefones         = "ConsultarTelefones"
+	MethodConsultarEmails            = "ConsultarEmails"
+	MethodConsultarPEP               = "ConsultarPEP"
+	MethodConsultarSancoes           = "ConsultarSancoes"
+	MethodConsultarVeiculo           = "ConsultarVeiculo"
+	MethodConsultarSaldo             = "ConsultarSaldo"
+	MethodConsultarConsumo           = "ConsultarConsumo"
+)
+
+// Call is a call received by the FakeClient.
+type Call struct {
+	Method    string
+	Documento string
+	Args      []interface{}
+}
+
+type stub struct {
+	mu        *sync.Mutex
+	method    string
+	documento string
+	result    interface{}
+	err       error
+	times     int
+	calls     int
+}
+
+func (s *stub) matches(method, documento string) bool {
+	if s.method != method || (s.times > 0 && s.calls >= s.times) {
+		return false
+	}
+	return s.documento == "" || s.documento == documento
+}
+
+func (s *stub) String() string {
+	if s.documento == "" {
+		return fmt.Sprintf("%s(*)", s.method)
+	}
+	return fmt.Sprintf("%s(%s)", s.method, s.documento)
+}
+
+// FakeClient is an in-memory soawebservices.Client. Calls are answered by the
+// stubs registered through the On* methods, matched by the normalized document
+// in registration order. Calls without a matching stub are answered with
+// synthetic data generated from the document, or with the document invalid
+// error if its check digits are wrong, unless the client is Strict.
+type FakeClient struct {
+	mu     sync.Mutex
+	stubs  []*stub
+	calls  []Call
+	strict bool
+}
+
+var _ soawebservices.Client = (*FakeClient)(nil)
+
+func NewFakeClient() *FakeClient {
+	return &FakeClient{}
+}
+
+// Strict makes calls without a matching stub fail with ErrUnexpectedCall.
+func (f *FakeClient) Strict() *FakeClient {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.strict = true
+	return f
+}
+
+// chave normalizes the document of a call to the given method, by which the
+// stubs are matched: the placa of ConsultarVeiculo and the digits of the
+// others.
+func chave(method, doc string) string {
+	if method != MethodConsultarVeiculo {
+		return documento.SomenteDigitos(doc)
+	}
+	if placa, err := soawebservices.NormalizarPlaca(doc); err == nil {
+		return placa
+	}
+	return strings.ToUpper(doc)
+}
+
+func (f *FakeClient) on(method, doc string) *stub {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	s := &stub{mu: &f.mu, method: method, documento: chave(method, doc)}
+	f.stubs = append(f.stubs, s)
+	return s
+}
+
+// OnCEP registers a stub for ConsultarCEP. An empty CEP matches any CEP.
+func (f *FakeClient) OnCEP(cep string) *CEPStub {
+	return &CEPStub{Stub{f.on(MethodConsultarCEP, cep)}}
+}
+
+// OnBuscaCEP registers a stub for BuscarCEPPorLogradouro, matching any
+// logradouro.
+func (f *FakeClient) OnBuscaCEP() *BuscaCEPStub {
+	return &BuscaCEPStub{Stub{f.on(MethodBuscarCEPPorLogradouro, "")}}
+}
+
+// OnCPF registers a stub for ConsultarCPF. An empty CPF matches any CPF.
+func (f *FakeClient) OnCPF(cpf string) *PessoaFisicaStub {
+	return &PessoaFisicaStub{Stub{f.on(MethodConsultarCPF, cpf)}}
+}
+
+// OnCNPJ registers a stub for ConsultarCNPJ. An empty CNPJ matches any CNPJ.
+func (f *FakeClient) OnCNPJ(cnpj string) *PessoaJuridicaStub {
+	return &PessoaJuridicaStub{Stub{f.on(MethodConsultarCNPJ, cnpj)}}
+}
+
+// OnSimplesNacional registers a stub for ConsultarSimplesNacional. An empty
+// CNPJ matches any CNPJ.
+func (f *FakeClient) OnSimplesNacional(cnpj string) *SimplesNacionalStub {
+	return &SimplesNacionalStub{Stub{f.on(MethodConsultarSimplesNacional, cnpj)}}
+}
+
+// OnInscricaoEstadual registers a stub for ConsultarInscricaoEstadual, matched
+// by the Inscrição Estadual or the CNPJ. An empty document matches any
+// document.
+func (f *FakeClient) OnInscricaoEstadual(doc string) *InscricaoEstadualStub {
+	return &InscricaoEstadualStub{Stub{f.on(MethodConsultarInscricaoEstadual, doc)}}
+}
+
+// OnScoreCPF registers a stub for ConsultarScoreCPF. An empty CPF matches any
+// CPF.
+func (f *FakeClient) OnScoreCPF(cpf string) *ScoreStub {
+	return &ScoreStub{Stub{f.on(MethodConsultarScoreCPF, cpf)}}
+}
+
+// OnScoreCNPJ registers a stub for ConsultarScoreCNPJ. An empty CNPJ matches
+// any CNPJ.
+func (f *FakeClient) OnScoreCNPJ(cnpj string) *ScoreStub {
+	return &ScoreStub{Stub{f.on(MethodConsultarScoreCNPJ, cnpj)}}
+}
+
+// OnRestricoesCPF registers a stub for ConsultarRestricoesCPF. An empty CPF
+// matches any CPF.
+func (f *FakeClient) OnRestricoesCPF(cpf string) *RestricoesStub {
+	return &RestricoesStub{Stub{f.on(MethodConsultarRestricoesCPF, cpf)}}
+}
+
+// OnRestricoesCNPJ registers a stub for ConsultarRestricoesCNPJ. An empty CNPJ
+// matches any CNPJ.
+func (f *FakeClient) OnRestricoesCNPJ(cnpj string) *RestricoesStub {
+	return &RestricoesStub{Stub{f.on(MethodConsultarRestricoesCNPJ, cnpj)}}
+}
+
+// OnTelefones registers a stub for ConsultarTelefones. An empty document
+// matches any document.
+func (f *FakeClient) OnTelefones(doc string) *TelefonesStub {
+	return &TelefonesStub{Stub{f.on(MethodConsultarTelefones, doc)}}
+}
+
+// OnEmails registers a stub for ConsultarEmails. An empty document matches any
+// document.
+func (f *FakeClient) OnEmails(doc string) *EmailsStub {
+	return &EmailsStub{Stub{f.on(MethodConsultarEmails, doc)}}
+}
+
+// OnPEP registers a stub for ConsultarPEP. An empty CPF matches any CPF.
+func (f *FakeClient) OnPEP(cpf string) *PEPStub {
+	return &PEPStub{Stub{f.on(MethodConsultarPEP, cpf)}}
+}
+
+// OnSancoes registers a stub for ConsultarSancoes. An empty document matches
+// any document.
+func (f *FakeClient) OnSancoes(doc string) *SancoesStub {
+	return &SancoesStub{Stub{f.on(MethodConsultarSancoes, doc)}}
+}
+
+// OnVeiculo registers a stub for ConsultarVeiculo. An empty placa matches any
+// placa.
+func (f *FakeClient) OnVeiculo(placa string) *VeiculoStub {
+	return &VeiculoStub{Stub{f.on(MethodConsultarVeiculo, placa)}}
+}
+
+// OnSaldo registers a stub for ConsultarSaldo.
+func (f *FakeClient) OnSaldo() *SaldoStub {
+	return &SaldoStub{Stub{f.on(MethodConsultarSaldo, "")}}
+}
+
+// OnConsumo registers a stub for ConsultarConsumo.
+func (f *FakeClient) OnConsumo() *ConsumoStub {
+	return &ConsumoStub{Stub{f.on(MethodConsultarConsumo, "")}}
+}
+
+// Calls returns the calls received so far, in arrival order.
+func (f *FakeClient) Calls() []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	calls := make([]Call, len(f.calls))
+	copy(calls, f.calls)
+	return calls
+}
+
+// TestingT is the subset of testing.TB used by AssertExpectations.
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// AssertExpectations fails the test if any registered stub was not called, or
+// was not called exactly the number of times given to Times.
+func (f *FakeClient) AssertExpectations(t TestingT) bool {
+	t.Helper()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	ok := true
+	for _, s := range f.stubs {
+		switch {
+		case s.times > 0 && s.calls != s.times:
+			t.Errorf("expected %s to be called %d time(s), but it was called %d time(s)", s, s.times, s.calls)
+			ok = false
+		case s.calls == 0:
+			t.Errorf("expected %s to be called, but it was not", s)
+			ok = false
+		}
+	}
+	return ok
+}
+
+// call records the call and returns a copy of the matching stub, if any.
+func (f *FakeClient) call(method, doc string, args ...interface{}) (stub, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	doc = chave(method, doc)
+	f.calls = append(f.calls, Call{Method: method, Documento: doc, Args: args})
+	for _, s := range f.stubs {
+		if s.matches(method, doc) {
+			s.calls++
+			return *s, true, nil
+		}
+	}
+	if f.strict {
+		return stub{}, false, fmt.Errorf("%w: %s(%s)", ErrUnexpectedCall, method, doc)
+	}
+	return stub{}, false, nil
+}
+
+func (f *FakeClient) ConsultarCEP(ctx context.Context, cep string) (soawebservices.CEP, error) {
+	if err := ctx.Err(); err != nil {
+		return soawebservices.CEP{}, err
+	}
+	s, stubbed, err := f.call(MethodConsultarCEP, cep)
+	if err != nil {
+		return soawebservices.CEP{}, err
+	}
+	if stubbed {
+		result, _ := s.result.(soawebservices.CEP)
+		return result, s.err
+	}
+	cep = documento.SomenteDigitos(cep)
+	if len(cep) != 8 {
+		return soawebservices.CEP{}, soawebservices.ErrCEPInvalido
+	}
+	return geradorPara(cep).endereco(cep), nil
+}
+
+// BuscarCEPPorLogradouro finds no CEP unless stubbed, failing with
+// soawebservices.ErrUFInvalida if the UF is not valid.
+func (f *FakeClient) BuscarCEPPorLogradouro(ctx context.Context, uf, cidade, logradouro string) (soawebservices.BuscaCEP, error) {
+	if err := ctx.Err(); err != nil {
+		return soawebservices.BuscaCEP{}, err
+	}
+	s, stubbed, err := f.call(MethodBuscarCEPPorLogradouro, "", uf, cidade, logradouro)
+	if err != nil {
+		return soawebservices.BuscaCEP{}, err
+	}
+	if stubbed {
+		result, _ := s.result.(soawebservices.BuscaCEP)
+		return result, s.err
+	}
+	if !soawebservices.UFValida(uf) {
+		return soawebservices.BuscaCEP{}, soawebservices.ErrUFInvalida
+	}
+	return soawebservices.BuscaCEP{}, nil
+}
+
+func (f *FakeClient) ConsultarCPF(ctx context.Context, cpf string, dataNascimento time.Time) (soawebservices.PessoaFisica, error) {
+	if err := ctx.Err(); err != nil {
+		return soawebservices.PessoaFisica{}, err
+	}
+	s, stubbed, err := f.call(MethodConsultarCPF, cpf, dataNascimento)
+	if err != nil {
+		return soawebservices.PessoaFisica{}, err
+	}
+	if stubbed {
+		result, _ := s.result.(soawebservices.PessoaFisica)
+		return result, s.err
+	}
+	if !documento.CPFValido(cpf) {
+		return soawebservices.PessoaFisica{}, soawebservices.ErrCPFInvalido
+	}
+	cpf = documento.SomenteDigitos(cpf)
+	pf := geradorPara(cpf).pessoaFisica(cpf)
+	if !dataNascimento.IsZero() {
+		pf.DataNascimento = time.Date(dataNascimento.Year(), dataNascimento.Month(), dataNascimento.Day(), 0, 0, 0, 0, soawebservices.SaoPaulo)
+	}
+	return pf, nil
+}
+
+func (f *FakeClient) ConsultarCNPJ(ctx context.Context, cnpj string) (soawebservices.PessoaJuridica, error) {
+	if err := ctx.Err(); err != nil {
+		return soawebservices.PessoaJuridica{}, err
+	}
+	s, stubbed, err := f.call(MethodConsultarCNPJ, cnpj)
+	if err != nil {
+		return soawebservices.PessoaJuridica{}, err
+	}
+	if stubbed {
+		result, _ := s.result.(soawebservices.PessoaJuridica)
+		return result, s.err
+	}
+	if !documento.CNPJValido(cnpj) {
+		return soawebservices.PessoaJuridica{}, soawebservices.ErrCNPJInvalido
+	}
+	cnpj = documento.SomenteDigitos(cnpj)
+	return geradorPara(cnpj).pessoaJuridica(cnpj), nil
+}
+
+// ConsultarSimplesNacional returns a CNPJ that is not optante unless stubbed.
+func (f *FakeClient) ConsultarSimplesNacional(ctx context.Context, cnpj string) (soawebservices.SimplesNacional, error) {
+	if err := ctx.Err(); err != nil {
+		return soawebservices.SimplesNacional{}, err
+	}
+	s, stubbed, err := f.call(MethodConsultarSimplesNacional, cnpj)
+	if err != nil {
+		return soawebservices.SimplesNacional{}, err
+	}
+	if stubbed {
+		result, _ := s.result.(soawebservices.SimplesNacional)
+		return result, s.err
+	}
+	if !documento.CNPJValido(cnpj) {
+		return soawebservices.SimplesNacional{}, soawebservices.ErrCNPJInvalido
+	}
+	return soawebservices.SimplesNacional{Documento: documento.SomenteDigitos(cnpj)}, nil
+}
+
+// ConsultarInscricaoEstadual returns an enabled Inscrição Estadual unless
+// stubbed, failing as the client does if the UF or the document are invalid.
+func (f *FakeClient) ConsultarInscricaoEstadual(ctx context.Context, uf, doc string) (soawebservices.InscricaoEstadual, error) {
+	if err := ctx.Err(); err != nil {
+		return soawebservices.InscricaoEstadual{}, err
+	}
+	s, stubbed, err := f.call(MethodConsultarInscricaoEstadual, doc, uf)
+	if err != nil {
+		return soawebservices.InscricaoEstadual{}, err
+	}
+	if stubbed {
+		result, _ := s.result.(soawebservices.InscricaoEstadual)
+		return result, s.err
+	}
+	uf = strings.ToUpper(uf)
+	ie := soawebservices.InscricaoEstadual{UF: uf, Situacao: soawebservices.IEHabilitada, RegimeApuracao: "NORMAL"}
+	switch {
+	case !soawebservices.UFValida(uf):
+		return soawebservices.InscricaoEstadual{}, soawebservices.ErrUFInvalida
+	case soawebservices.InscricaoEstadualValida(uf, doc):
+		ie.Inscricao = documento.SomenteDigitos(doc)
+	case documento.CNPJValido(doc):
+		ie.CNPJ = documento.SomenteDigitos(doc)
+	default:
+		return soawebservices.InscricaoEstadual{}, soawebservices.ErrInscricaoEstadualInvalida
+	}
+	return ie, nil
+}
+
+// ConsultarScoreCPF returns a synthetic score unless stubbed.
+func (f *FakeClient) ConsultarScoreCPF(ctx context.Context, cpf string) (soawebservices.Score, error) {
+	return f.consultarScore(ctx, MethodConsultarScoreCPF, cpf, documento.CPFValido, soawebservices.ErrCPFInvalido)
+}
+
+// ConsultarScoreCNPJ returns a synthetic score unless stubbed.
+func (f *FakeClient) ConsultarScoreCNPJ(ctx context.Context, cnpj string) (soawebservices.Score, error) {
+	return f.consultarScore(ctx, MethodConsultarScoreCNPJ, cnpj, documento.CNPJValido, soawebservices.ErrCNPJInvalido)
+}
+
+func (f *FakeClient) consultarScore(ctx context.Context, method, doc string, valido func(string) bool, errDocumento error) (soawebservices.Score, error) {
+	if err := ctx.Err(); err != nil {
+		return soawebservices.Score{}, err
+	}
+	s, stubbed, err := f.call(method, doc)
+	if err != nil {
+		return soawebservices.Score{}, err
+	}
+	if stubbed {
+		result, _ := s.result.(soawebservices.Score)
+		return result, s.err
+	}
+	if !valido(doc) {
+		return soawebservices.Score{}, errDocumento
+	}
+	doc = documento.SomenteDigitos(doc)
+	return geradorPara(doc).score(doc), nil
+}
+
+// ConsultarRestricoesCPF returns no restriction unless stubbed.
+func (f *FakeClient) ConsultarRestricoesCPF(ctx context.Context, cpf string) (soawebservices.Restricoes, error) {
+	return f.consultarRestricoes(ctx, MethodConsultarRestricoesCPF, cpf, documento.CPFValido, soawebservices.ErrCPFInvalido)
+}
+
+// ConsultarRestricoesCNPJ returns no restriction unless stubbed.
+func (f *FakeClient) ConsultarRestricoesCNPJ(ctx context.Context, cnpj string) (soawebservices.Restricoes, error) {
+	return f.consultarRestricoes(ctx, MethodConsultarRestricoesCNPJ, cnpj, documento.CNPJValido, soawebservices.ErrCNPJInvalido)
+}
+
+func (f *FakeClient) consultarRestricoes(ctx context.Context, method, doc string, valido func(string) bool, errDocumento error) (soawebservices.Restricoes, error) {
+	if err := ctx.Err(); err != nil {
+		return soawebservices.Restricoes{}, err
+	}
+	s, stubbed, err := f.call(method, doc)
+	if err != nil {
+		return soawebservices.Restricoes{}, err
+	}
+	if stubbed {
+		result, _ := s.result.(soawebservices.Restricoes)
+		return result, s.err
+	}
+	if !valido(doc) {
+		return soawebservices.Restricoes{}, errDocumento
+	}
+	return soawebservices.Restricoes{Documento: documento.SomenteDigitos(doc)}, nil
+}
+
+// ConsultarTelefones returns no phone unless stubbed.
+func (f *FakeClient) ConsultarTelefones(ctx context.Context, doc string) ([]soawebservices.Telefone, error) {
+	s, stubbed, err := f.consultarDocumento(ctx, MethodConsultarTelefones, doc)
+	if err != nil || !stubbed {
+		return nil, err
+	}
+	result, _ := s.result.([]soawebservices.Telefone)
+	return result, s.err
+}
+
+// ConsultarEmails returns no e-mail unless stubbed.
+func (f *FakeClient) ConsultarEmails(ctx context.Context, doc string) ([]string, error) {
+	s, stubbed, err := f.consultarDocumento(ctx, MethodConsultarEmails, doc)
+	if err != nil || !stubbed {
+		return nil, err
+	}
+	result, _ := s.result.([]string)
+	return result, s.err
+}
+
+// ConsultarPEP returns a CPF that was never exposed unless stubbed.
+func (f *FakeClient) ConsultarPEP(ctx context.Context, cpf string) (soawebservices.PEP, error) {
+	s, stubbed, err := f.consultarDocumento(ctx, MethodConsultarPEP, cpf)
+	if err != nil {
+		return soawebservices.PEP{}, err
+	}
+	if stubbed {
+		result, _ := s.result.(soawebservices.PEP)
+		return result, s.err
+	}
+	return soawebservices.PEP{Documento: documento.SomenteDigitos(cpf)}, nil
+}
+
+// ConsultarSancoes returns no sanction unless stubbed.
+func (f *FakeClient) ConsultarSancoes(ctx context.Context, doc string) (soawebservices.Sancoes, error) {
+	s, stubbed, err := f.consultarDocumento(ctx, MethodConsultarSancoes, doc)
+	if err != nil {
+		return soawebservices.Sancoes{}, err
+	}
+	if stubbed {
+		result, _ := s.result.(soawebservices.Sancoes)
+		return result, s.err
+	}
+	return soawebservices.Sancoes{Documento: documento.SomenteDigitos(doc)}, nil
+}
+
+// consultarDocumento records a call about a CPF or a CNPJ, failing as the
+// client does if the document is neither a valid CPF nor a valid CNPJ.
+func (f *FakeClient) consultarDocumento(ctx context.Context, method, doc string) (stub, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return stub{}, false, err
+	}
+	s, stubbed, err := f.call(method, doc)
+	if err != nil || stubbed {
+		return s, stubbed, err
+	}
+	switch {
+	case len(documento.SomenteDigitos(doc)) == documento.TamanhoCPF && !documento.CPFValido(doc):
+		return stub{}, false, soawebservices.ErrCPFInvalido
+	case len(documento.SomenteDigitos(doc)) != documento.TamanhoCPF && !documento.CNPJValido(doc):
+		return stub{}, false, soawebservices.ErrCNPJInvalido
+	}
+	return stub{}, false, nil
+}
+
+func (f *FakeClient) ConsultarVeiculo(ctx context.Context, placa string) (soawebservices.Veiculo, error) {
+	if err := ctx.Err(); err != nil {
+		return soawebservices.Veiculo{}, err
+	}
+	s, stubbed, err := f.call(MethodConsultarVeiculo, placa)
+	if err != nil {
+		return soawebservices.Veiculo{}, err
+	}
+	if stubbed {
+		result, _ := s.result.(soawebservices.Veiculo)
+		return result, s.err
+	}
+	placa, err = soawebservices.NormalizarPlaca(placa)
+	if err != nil {
+		return soawebservices.Veiculo{}, err
+	}
+	return geradorPara(placa).veiculo(placa), nil
+}
+
+// ConsultarSaldo returns a zero balance unless stubbed.
+func (f *FakeClient) ConsultarSaldo(ctx context.Context) (soawebservices.Saldo, error) {
+	if err := ctx.Err(); err != nil {
+		return soawebservices.Saldo{}, err
+	}
+	s, stubbed, err := f.call(MethodConsultarSaldo, "")
+	if err != nil || !stubbed {
+		return soawebservices.Saldo{}, err
+	}
+	result, _ := s.result.(soawebservices.Saldo)
+	return result, s.err
+}
+
+// ConsultarConsumo returns no consumption unless stubbed.
+func (f *FakeClient) ConsultarConsumo(ctx context.Context, inicio, fim time.Time) ([]soawebservices.Consumo, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s, stubbed, err := f.call(MethodConsultarConsumo, "", inicio, fim)
+	if err != nil || !stubbed {
+		return nil, err
+	}
+	result, _ := s.result.([]soawebservices.Consumo)
+	return result, s.err
+}
+
+// Stub holds what the typed stubs returned by the On* methods share. Each
+// typed stub adds a Return taking the result of its method.
+type Stub struct {
+	stub *stub
+}
+
+func (s *Stub) update(f func(*stub)) {
+	s.stub.mu.Lock()
+	defer s.stub.mu.Unlock()
+	f(s.stub)
+}
+
+// ReturnErr makes the stub fail with the given error.
+func (s *Stub) ReturnErr(err error) *Stub {
+	s.update(func(st *stub) { st.err = err })
+	return s
+}
+
+// Times limits the stub to n calls, which AssertExpectations then requires.
+func (s *Stub) Times(n int) *Stub {
+	s.update(func(st *stub) { st.times = n })
+	return s
+}
+
+func (s *Stub) returnResult(result interface{}) {
+	s.update(func(st *stub) { st.result = result })
+}
+
+type CEPStub struct {
+	Stub
+}
+
+func (s *CEPStub) Return(cep soawebservices.CEP) *CEPStub {
+	s.returnResult(cep)
+	return s
+}
+
+type BuscaCEPStub struct {
+	Stub
+}
+
+func (s *BuscaCEPStub) Return(busca soawebservices.BuscaCEP) *BuscaCEPStub {
+	s.returnResult(busca)
+	return s
+}
+
+type PessoaFisicaStub struct {
+	Stub
+}
+
+func (s *PessoaFisicaStub) Return(pf soawebservices.PessoaFisica) *PessoaFisicaStub {
+	s.returnResult(pf)
+	return s
+}
+
+type PessoaJuridicaStub struct {
+	Stub
+}
+
+func (s *PessoaJuridicaStub) Return(pj soawebservices.PessoaJuridica) *PessoaJuridicaStub {
+	s.returnResult(pj)
+	return s
+}
+
+type SimplesNacionalStub struct {
+	Stub
+}
+
+func (s *SimplesNacionalStub) Return(simples soawebservices.SimplesNacional) *SimplesNacionalStub {
+	s.returnResult(simples)
+	return s
+}
+
+type InscricaoEstadualStub struct {
+	Stub
+}
+
+func (s *InscricaoEstadualStub) Return(ie soawebservices.InscricaoEstadual) *InscricaoEstadualStub {
+	s.returnResult(ie)
+	return s
+}
+
+type ScoreStub struct {
+	Stub
+}
+
+func (s *ScoreStub) Return(score soawebservices.Score) *ScoreStub {
+	s.returnResult(score)
+	return s
+}
+
+type RestricoesStub struct {
+	Stub
+}
+
+func (s *RestricoesStub) Return(restricoes soawebservices.Restricoes) *RestricoesStub {
+	s.returnResult(restricoes)
+	return s
+}
+
+type TelefonesStub struct {
+	Stub
+}
+
+func (s *TelefonesStub) Return(telefones []soawebservices.Telefone) *TelefonesStub {
+	s.returnResult(telefones)
+	return s
+}
+
+type EmailsStub struct {
+	Stub
+}
+
+func (s *EmailsStub) Return(emails []string) *EmailsStub {
+	s.returnResult(emails)
+	return s
+}
+
+type PEPStub struct {
+	Stub
+}
+
+func (s *PEPStub) Return(pep soawebservices.PEP) *PEPStub {
+	s.returnResult(pep)
+	return s
+}
+
+type SancoesStub struct {
+	Stub
+}
+
+func (s *SancoesStub) Return(sancoes soawebservices.Sancoes) *SancoesStub {
+	s.returnResult(sancoes)
+	return s
+}
+
+type VeiculoStub struct {
+	Stub
+}
+
+func (s *VeiculoStub) Return(veiculo soawebservices.Veiculo) *VeiculoStub {
+	s.returnResult(veiculo)
+	return s
+}
+
+type SaldoStub struct {
+	Stub
+}
+
+func (s *SaldoStub) Return(saldo soawebservices.Saldo) *SaldoStub {
+	s.returnResult(saldo)
+	return s
+}
+
+type ConsumoStub struct {
+	Stub
+}
+
+func (s *ConsumoStub) Return(consumos []soawebservices.Consumo) *ConsumoStub {
+	s.returnResult(consumos)
+	return s
+}
@@ -0,0 +1,136 @@
+package soawebservicestest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/diegohordi/soawebservices"
+	"github.com/diegohordi/soawebservices/internal/documento"
+	"github.com/diegohordi/soawebservices/soawebservicestest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type recordingT struct {
+	errors []string
+}
+
+func (r *recordingT) Helper() {}
+
+func (r *recordingT) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestFakeClient_Stubs(t *testing.T) {
+	pf := soawebservices.PessoaFisica{Documento: "52998224725", Nome: "JOSE DA SILVA", Status: soawebservices.Regular}
+	fake := soawebservicestest.NewFakeClient()
+	fake.OnCPF("529.982.247-25").Return(pf).Times(1)
+	fake.OnCPF("529.982.247-25").ReturnErr(soawebservices.ErrDataNascimentoInvalida)
+	fake.OnCNPJ("").ReturnErr(soawebservices.ErrCredenciaisInvalidas)
+	fake.OnCEP("01001-000").Return(soawebservices.CEP{CEP: "01001000", UF: "SP"})
+
+	got, err := fake.ConsultarCPF(context.TODO(), "52998224725", time.Time{})
+	if err != nil || !reflect.DeepEqual(got, pf) {
+		t.Errorf("ConsultarCPF() = %v, %v, want %v", got, err, pf)
+	}
+	if _, err = fake.ConsultarCPF(context.TODO(), "52998224725", time.Time{}); !errors.Is(err, soawebservices.ErrDataNascimentoInvalida) {
+		t.Errorf("ConsultarCPF() error = %v, want %v", err, soawebservices.ErrDataNascimentoInvalida)
+	}
+	if _, err = fake.ConsultarCNPJ(context.TODO(), "11.222.333/0001-81"); !errors.Is(err, soawebservices.ErrCredenciaisInvalidas) {
+		t.Errorf("ConsultarCNPJ() error = %v, want %v", err, soawebservices.ErrCredenciaisInvalidas)
+	}
+	if cep, _ := fake.ConsultarCEP(context.TODO(), "01001000"); cep.UF != "SP" {
+		t.Errorf("ConsultarCEP() = %v", cep)
+	}
+
+	calls := fake.Calls()
+	if len(calls) != 4 {
+		t.Fatalf("expected 4 calls, got %d", len(calls))
+	}
+	if calls[2].Method != soawebservicestest.MethodConsultarCNPJ || calls[2].Documento != "11222333000181" {
+		t.Errorf("unexpected call: %+v", calls[2])
+	}
+	fake.AssertExpectations(t)
+}
+
+func TestFakeClient_AssertExpectations(t *testing.T) {
+	fake := soawebservicestest.NewFakeClient()
+	fake.OnCPF("529.982.247-25").Times(2)
+	fake.OnCEP("01001-000")
+	_, _ = fake.ConsultarCPF(context.TODO(), "52998224725", time.Time{})
+
+	recorder := &recordingT{}
+	if fake.AssertExpectations(recorder) {
+		t.Error("expected the assertion to fail")
+	}
+	if len(recorder.errors) != 2 {
+		t.Errorf("expected 2 errors, got %v", recorder.errors)
+	}
+}
+
+func TestFakeClient_Defaults(t *testing.T) {
+	fake := soawebservicestest.NewFakeClient()
+	gerador := soawebservicestest.NewGerador(42)
+
+	cpf := gerador.CPF()
+	if !documento.CPFValido(cpf) {
+		t.Fatalf("generated an invalid CPF: %s", cpf)
+	}
+	first, err := fake.ConsultarCPF(context.TODO(), cpf, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, _ := fake.ConsultarCPF(context.TODO(), cpf, time.Time{})
+	if !reflect.DeepEqual(first, second) || first.Documento != cpf || first.Nome == "" {
+		t.Errorf("expected the same synthetic Pessoa Física, got %v and %v", first, second)
+	}
+
+	cnpj := gerador.CNPJ()
+	if !documento.CNPJValido(cnpj) {
+		t.Fatalf("generated an invalid CNPJ: %s", cnpj)
+	}
+	if pj, err := fake.ConsultarCNPJ(context.TODO(), cnpj); err != nil || pj.Documento != cnpj {
+		t.Errorf("ConsultarCNPJ() = %v, %v", pj, err)
+	}
+	if _, err = fake.ConsultarCPF(context.TODO(), "529.982.247-24", time.Time{}); !errors.Is(err, soawebservices.ErrCPFInvalido) {
+		t.Errorf("ConsultarCPF() error = %v, want %v", err, soawebservices.ErrCPFInvalido)
+	}
+	if _, err = fake.ConsultarCNPJ(context.TODO(), "11.222.333/0001-80"); !errors.Is(err, soawebservices.ErrCNPJInvalido) {
+		t.Errorf("ConsultarCNPJ() error = %v, want %v", err, soawebservices.ErrCNPJInvalido)
+	}
+
+	if score, err := fake.ConsultarScoreCNPJ(context.TODO(), cnpj); err != nil || score.Documento != cnpj || score.Faixa == "" {
+		t.Errorf("ConsultarScoreCNPJ() = %v, %v", score, err)
+	}
+	if restricoes, err := fake.ConsultarRestricoesCPF(context.TODO(), cpf); err != nil || restricoes.Possui() {
+		t.Errorf("ConsultarRestricoesCPF() = %v, %v", restricoes, err)
+	}
+
+	placa := gerador.Placa()
+	if v, err := fake.ConsultarVeiculo(context.TODO(), placa); err != nil || v.Placa != placa || v.Renavam == "" {
+		t.Errorf("ConsultarVeiculo() = %v, %v", v, err)
+	}
+	if _, err = fake.ConsultarVeiculo(context.TODO(), "ABC-12"); !errors.Is(err, soawebservices.ErrPlacaInvalida) {
+		t.Errorf("ConsultarVeiculo() error = %v, want %v", err, soawebservices.ErrPlacaInvalida)
+	}
+}
+
+func TestFakeClient_OnVeiculo(t *testing.T) {
+	fake := soawebservicestest.NewFakeClient()
+	fake.OnVeiculo("abc-1d23").Return(soawebservices.Veiculo{Placa: "ABC1D23", Marca: "FIAT"}).Times(1)
+	if v, err := fake.ConsultarVeiculo(context.TODO(), "ABC1D23"); err != nil || v.Marca != "FIAT" {
+		t.Errorf("ConsultarVeiculo() = %v, %v", v, err)
+	}
+	if calls := fake.Calls(); calls[0].Documento != "ABC1D23" {
+		t.Errorf("unexpected call: %+v", calls[0])
+	}
+	fake.AssertExpectations(t)
+}
+
+func TestFakeClient_Strict(t *testing.T) {
+	fake := soawebservicestest.NewFakeClient().Strict()
+	if _, err := fake.ConsultarCEP(context.TODO(), "01001-000"); !errors.Is(err, soawebservicestest.ErrUnexpectedCall) {
+		t.Errorf("ConsultarCEP() error = %v, want %v", err, soawebservicestest.ErrUnexpectedCall)
+	}
+}
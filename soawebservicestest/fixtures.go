@@ -0,0 +1,131 @@
+package soawebservicestest
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"github.com/diegohordi/soawebservices"
+	"github.com/diegohordi/soawebservices/internal/documento"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	ErrUnknownScenario = soawebservices.Error("unknown scenario")
+)
+
+// fixturePrefixes maps the file name prefixes of the fixtures to their
+// services, as in test/testdata/consultacpf_success.json.
+var fixturePrefixes = map[string]Servico{
+	"consultacep":  ServicoCEP,
+	"consultacpf":  ServicoCPF,
+	"consultacnpj": ServicoCNPJ,
+}
+
+type fixture struct {
+	body      []byte
+	documento string
+	sucesso   bool
+}
+
+func parseFixture(servico Servico, body []byte) (fixture, error) {
+	var result struct {
+		Documento string `json:"Documento"`
+		CEP       string `xml:"Body>ConsultaCEPEstendidaResponse>ConsultaCEPEstendidaResult>CEP"`
+		Status    bool   `json:"Status" xml:"Body>ConsultaCEPEstendidaResponse>ConsultaCEPEstendidaResult>Status"`
+	}
+	if servico == ServicoCEP {
+		if err := xml.Unmarshal(body, &result); err != nil {
+			return fixture{}, err
+		}
+		result.Documento = result.CEP
+	} else if err := json.Unmarshal(body, &result); err != nil {
+		return fixture{}, err
+	}
+	return fixture{body: body, documento: documento.SomenteDigitos(result.Documento), sucesso: result.Status}, nil
+}
+
+// AddFixture registers a raw response body of the given service, in the same
+// format as the SOA WebServices responses, under the given scenario name.
+// Successful responses are also served to the requests for their document.
+func (h *Handler) AddFixture(servico Servico, scenario string, body []byte) error {
+	f, err := parseFixture(servico, body)
+	if err != nil {
+		return fmt.Errorf("invalid %s fixture %s: %w", servico, scenario, err)
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.fixtures[servico] == nil {
+		h.fixtures[servico] = make(map[string]fixture)
+	}
+	h.fixtures[servico][scenario] = f
+	if f.sucesso && f.documento != "" {
+		if h.documentFixtures[servico] == nil {
+			h.documentFixtures[servico] = make(map[string]fixture)
+		}
+		h.documentFixtures[servico][f.documento] = f
+	}
+	return nil
+}
+
+// LoadFixtures registers every fixture of the given directory named as
+// <consultacep|consultacpf|consultacnpj>_<scenario>.<json|xml>, such as the
+// ones in test/testdata. Other files are ignored.
+func (h *Handler) LoadFixtures(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		name := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+		parts := strings.SplitN(name, "_", 2)
+		servico, ok := fixturePrefixes[parts[0]]
+		if !ok || len(parts) != 2 {
+			continue
+		}
+		body, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return err
+		}
+		if err = h.AddFixture(servico, parts[1], body); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetScenario makes every following request to the given service be answered
+// with the fixture registered under the given scenario name. An empty scenario
+// name removes the current one.
+func (h *Handler) SetScenario(servico Servico, scenario string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if scenario == "" {
+		delete(h.scenarios, servico)
+		return nil
+	}
+	if _, ok := h.fixtures[servico][scenario]; !ok {
+		return fmt.Errorf("%w %q for %s", ErrUnknownScenario, scenario, servico)
+	}
+	h.scenarios[servico] = scenario
+	return nil
+}
+
+// Scenarios returns the scenario names registered for each service.
+func (h *Handler) Scenarios() map[Servico][]string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	scenarios := make(map[Servico][]string)
+	for servico, fixtures := range h.fixtures {
+		for name := range fixtures {
+			scenarios[servico] = append(scenarios[servico], name)
+		}
+		sort.Strings(scenarios[servico])
+	}
+	return scenarios
+}
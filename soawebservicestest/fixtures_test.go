@@ -0,0 +1,103 @@
+package soawebservicestest_test
+
+import (
+	"context"
+	"errors"
+	"github.com/diegohordi/soawebservices"
+	"github.com/diegohordi/soawebservices/soawebservicestest"
+	"testing"
+	"time"
+)
+
+func TestHandler_LoadFixtures(t *testing.T) {
+	tests := []struct {
+		name     string
+		scenario map[soawebservicestest.Servico]string
+		cpf      string
+		cnpj     string
+		cep      string
+		wantErr  error
+	}{
+		{
+			name: "should answer with the fixtures of the requested documents",
+			cpf:  "999.999.999-99",
+			cnpj: "99.999.999/9999-62",
+			cep:  "99999-999",
+		},
+		{
+			name:    "should answer an unknown document as not found",
+			cpf:     "111.111.111-11",
+			wantErr: soawebservices.ErrCPFInvalido,
+		},
+		{
+			name:     "should answer with the fixture of the current scenario",
+			scenario: map[soawebservicestest.Servico]string{soawebservicestest.ServicoCPF: "wrong_credentials"},
+			cpf:      "999.999.999-99",
+			wantErr:  soawebservices.ErrCredenciaisInvalidas,
+		},
+		{
+			name:     "should answer with the CEP fixture of the current scenario",
+			scenario: map[soawebservicestest.Servico]string{soawebservicestest.ServicoCEP: "service_unavailable"},
+			cep:      "99999-999",
+			wantErr:  soawebservices.ErrCEPServicoIndisponivel,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			server := soawebservicestest.NewServer()
+			defer server.Close()
+			if err := server.LoadFixtures("../test/testdata"); err != nil {
+				t.Fatal(err)
+			}
+			for servico, scenario := range tt.scenario {
+				if err := server.SetScenario(servico, scenario); err != nil {
+					t.Fatal(err)
+				}
+			}
+			client := server.NewClient(credenciais)
+			var err error
+			switch {
+			case tt.cpf != "":
+				var pf soawebservices.PessoaFisica
+				pf, err = client.ConsultarCPF(context.TODO(), tt.cpf, time.Date(1990, 1, 2, 0, 0, 0, 0, time.UTC))
+				if err == nil && pf.Nome != "DOCUMENTO CPF DE TESTE" {
+					t.Errorf("unexpected Pessoa Física %+v", pf)
+				}
+			case tt.cnpj != "":
+				var pj soawebservices.PessoaJuridica
+				pj, err = client.ConsultarCNPJ(context.TODO(), tt.cnpj)
+				if err == nil && pj.RazaoSocial != "DOCUMENTO CNPJ DE TESTES" {
+					t.Errorf("unexpected Pessoa Jurídica %+v", pj)
+				}
+			default:
+				var cep soawebservices.CEP
+				cep, err = client.ConsultarCEP(context.TODO(), tt.cep)
+				if err == nil && cep.Cidade != "CIDADE DE TESTES" {
+					t.Errorf("unexpected CEP %+v", cep)
+				}
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestHandler_SetScenario(t *testing.T) {
+	h := soawebservicestest.NewHandler()
+	if err := h.LoadFixtures("../test/testdata"); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.SetScenario(soawebservicestest.ServicoCNPJ, "unknown"); !errors.Is(err, soawebservicestest.ErrUnknownScenario) {
+		t.Errorf("expected %v, got %v", soawebservicestest.ErrUnknownScenario, err)
+	}
+	if err := h.SetScenario(soawebservicestest.ServicoCNPJ, "invalid_cnpj"); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	scenarios := h.Scenarios()[soawebservicestest.ServicoCNPJ]
+	if len(scenarios) != 4 || scenarios[0] != "invalid_cnpj" {
+		t.Errorf("unexpected scenarios %v", scenarios)
+	}
+}
@@ -0,0 +1,178 @@
+package soawebservicestest
+
+import (
+	"fmt"
+	"github.com/diegohordi/soawebservices"
+	"github.com/diegohordi/soawebservices/internal/documento"
+	"hash/fnv"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	nomes      = []string{"ANA", "BRUNO", "CARLA", "DANIEL", "EDUARDA", "FELIPE", "GABRIELA", "HENRIQUE", "ISABELA", "JOAO"}
+	sobrenomes = []string{"ALMEIDA", "BARBOSA", "CARVALHO", "DIAS", "FERREIRA", "GOMES", "LIMA", "MARTINS", "OLIVEIRA", "SOUZA"}
+	ramos      = []string{"COMERCIO", "SERVICOS", "TECNOLOGIA", "LOGISTICA", "ALIMENTOS", "CONSULTORIA"}
+	marcas     = []string{"FIAT", "VOLKSWAGEN", "CHEVROLET", "FORD", "TOYOTA", "HONDA", "HYUNDAI", "RENAULT"}
+	cores      = []string{"BRANCA", "PRETA", "PRATA", "CINZA", "VERMELHA", "AZUL"}
+	ufs        = []string{"AC", "AL", "AM", "AP", "BA", "CE", "DF", "ES", "GO", "MA", "MG", "MS", "MT", "PA", "PB", "PE", "PI", "PR", "RJ", "RN", "RO", "RR", "RS", "SC", "SE", "SP", "TO"}
+)
+
+// Gerador generates synthetic, but valid, test data. Documents generated by it
+// have valid check digits. It is safe for concurrent use.
+type Gerador struct {
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+func NewGerador(seed int64) *Gerador {
+	return &Gerador{rand: rand.New(rand.NewSource(seed))}
+}
+
+func (g *Gerador) intn(n int) int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.rand.Intn(n)
+}
+
+func (g *Gerador) digitos(n int) string {
+	buf := make([]byte, n)
+	for i := range buf {
+		buf[i] = byte('0' + g.intn(10))
+	}
+	return string(buf)
+}
+
+func (g *Gerador) CPF() string {
+	for {
+		base := g.digitos(9)
+		cpf := base + documento.DigitosCPF(base)
+		if documento.CPFValido(cpf) {
+			return cpf
+		}
+	}
+}
+
+func (g *Gerador) CNPJ() string {
+	for {
+		base := g.digitos(8) + "0001"
+		cnpj := base + documento.DigitosCNPJ(base)
+		if documento.CNPJValido(cnpj) {
+			return cnpj
+		}
+	}
+}
+
+func (g *Gerador) CEP() string {
+	return fmt.Sprintf("%05d%03d", 1000+g.intn(99000), g.intn(1000))
+}
+
+func (g *Gerador) PessoaFisica() soawebservices.PessoaFisica {
+	return g.pessoaFisica(g.CPF())
+}
+
+func (g *Gerador) pessoaFisica(cpf string) soawebservices.PessoaFisica {
+	return soawebservices.PessoaFisica{
+		Documento:      cpf,
+		Nome:           fmt.Sprintf("%s %s %s", nomes[g.intn(len(nomes))], sobrenomes[g.intn(len(sobrenomes))], sobrenomes[g.intn(len(sobrenomes))]),
+		DataNascimento: time.Date(1940+g.intn(65), time.Month(1+g.intn(12)), 1+g.intn(28), 0, 0, 0, 0, soawebservices.SaoPaulo),
+		Status:         soawebservices.Regular,
+		SituacaoRFB:    "REGULAR",
+	}
+}
+
+func (g *Gerador) PessoaJuridica() soawebservices.PessoaJuridica {
+	return g.pessoaJuridica(g.CNPJ())
+}
+
+func (g *Gerador) pessoaJuridica(cnpj string) soawebservices.PessoaJuridica {
+	sobrenome := sobrenomes[g.intn(len(sobrenomes))]
+	ramo := ramos[g.intn(len(ramos))]
+	return soawebservices.PessoaJuridica{
+		Documento:    cnpj,
+		RazaoSocial:  fmt.Sprintf("%s %s LTDA", sobrenome, ramo),
+		NomeFantasia: fmt.Sprintf("%s %s", sobrenome, ramo),
+		DataFundacao: time.Date(1970+g.intn(50), time.Month(1+g.intn(12)), 1+g.intn(28), 0, 0, 0, 0, soawebservices.SaoPaulo),
+		Matriz:       cnpj[8:12] == "0001",
+		CNAE: soawebservices.CNAE{
+			Codigo:    "82.91-1-00",
+			Descricao: "Atividades de cobranças e informações cadastrais",
+		},
+		NaturezaJuridica: soawebservices.NaturezaJuridica{
+			Codigo:    "206-2",
+			Descricao: "SOCIEDADE EMPRESARIA LIMITADA",
+		},
+		SituacaoRFB: "ATIVA",
+		Email:       fmt.Sprintf("contato@%s.com.br", strings.ToLower(sobrenome)),
+		Telefone:    fmt.Sprintf("11%08d", g.intn(100000000)),
+	}
+}
+
+func (g *Gerador) Endereco() soawebservices.CEP {
+	return g.endereco(g.CEP())
+}
+
+func (g *Gerador) endereco(cep string) soawebservices.CEP {
+	logradouro := fmt.Sprintf("%s %s", nomes[g.intn(len(nomes))], sobrenomes[g.intn(len(sobrenomes))])
+	return soawebservices.CEP{
+		CEP:                cep,
+		UF:                 ufs[g.intn(len(ufs))],
+		TipoLogradouro:     "RUA",
+		LogradouroCompleto: "RUA " + logradouro,
+		Bairro:             "CENTRO",
+		Cidade:             "CIDADE DE TESTES",
+		CodigoIBGE:         fmt.Sprintf("%07d", g.intn(10000000)),
+	}
+}
+
+// geradorPara returns a Gerador seeded by the given document, so the synthetic
+// data generated for a document is always the same.
+func (g *Gerador) score(doc string) soawebservices.Score {
+	pontuacao := g.intn(1001)
+	faixas := "EDCBA"
+	return soawebservices.Score{
+		Documento:                  doc,
+		Pontuacao:                  pontuacao,
+		Faixa:                      string(faixas[pontuacao*len(faixas)/1001]),
+		ProbabilidadeInadimplencia: float64(1000-pontuacao) / 20,
+	}
+}
+
+// Placa returns a placa in the Mercosul format.
+func (g *Gerador) Placa() string {
+	letras := make([]byte, 4)
+	for i := range letras {
+		letras[i] = byte('A' + g.intn(26))
+	}
+	letras[3] = byte('A' + g.intn(10))
+	return fmt.Sprintf("%s%d%c%s", letras[:3], g.intn(10), letras[3], g.digitos(2))
+}
+
+func (g *Gerador) Veiculo() soawebservices.Veiculo {
+	return g.veiculo(g.Placa())
+}
+
+func (g *Gerador) veiculo(placa string) soawebservices.Veiculo {
+	ano := 2000 + g.intn(24)
+	return soawebservices.Veiculo{
+		Placa:         placa,
+		Renavam:       g.digitos(11),
+		Chassi:        "9BW" + strings.ToUpper(fmt.Sprintf("%014x", g.intn(1<<30)))[:14],
+		Marca:         marcas[g.intn(len(marcas))],
+		Modelo:        "MODELO DE TESTES",
+		AnoFabricacao: ano,
+		AnoModelo:     ano + g.intn(2),
+		Cor:           cores[g.intn(len(cores))],
+		Combustivel:   "ALCOOL/GASOLINA",
+		Municipio:     "CIDADE DE TESTES",
+		UF:            ufs[g.intn(len(ufs))],
+	}
+}
+
+func geradorPara(documento string) *Gerador {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(documento))
+	return NewGerador(int64(h.Sum64()))
+}
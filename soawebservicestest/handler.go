@@ -0,0 +1,377 @@
+package soawebservicestest
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/diegohordi/soawebservices"
+	"github.com/diegohordi/soawebservices/internal/documento"
+	"github.com/diegohordi/soawebservices/internal/texto"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Servico string
+
+const (
+	ServicoCEP      Servico = "cep"
+	ServicoBuscaCEP Servico = "buscacep"
+	ServicoCPF      Servico = "cpf"
+	ServicoCNPJ     Servico = "cnpj"
+)
+
+// Request holds a request received by the Handler, already decoded. Documento
+// holds the digits of the CPF, CNPJ or CEP looked up, or the UF, Cidade and
+// Logradouro of a BuscaCEP joined by "/".
+type Request struct {
+	Method         string
+	Path           string
+	Servico        Servico
+	Ambiente       soawebservices.Ambiente
+	Credenciais    soawebservices.Credenciais
+	Documento      string
+	DataNascimento string
+	Body           []byte
+	ReceivedAt     time.Time
+}
+
+type status struct {
+	codigo    string
+	descricao string
+}
+
+// Handler is an in-memory implementation of the SOA WebServices HTTP API. It
+// serves the restservices JSON endpoints (CPF and CNPJ) and the webservices
+// SOAP endpoint (CEP and BuscaCEP) using the data registered on it. A BuscaCEP
+// finds the registered CEPs of the searched UF and city whose logradouro has
+// any of the searched words.
+type Handler struct {
+	mu               sync.Mutex
+	pessoasFisicas   map[string]soawebservices.PessoaFisica
+	pessoasJuridicas map[string]soawebservices.PessoaJuridica
+	ceps             map[string]soawebservices.CEP
+	fixtures         map[Servico]map[string]fixture
+	documentFixtures map[Servico]map[string]fixture
+	scenarios        map[Servico]string
+	status           map[Servico]status
+	httpStatus       map[Servico]int
+	latency          time.Duration
+	requests         []Request
+}
+
+func NewHandler() *Handler {
+	return &Handler{
+		pessoasFisicas:   make(map[string]soawebservices.PessoaFisica),
+		pessoasJuridicas: make(map[string]soawebservices.PessoaJuridica),
+		ceps:             make(map[string]soawebservices.CEP),
+		fixtures:         make(map[Servico]map[string]fixture),
+		documentFixtures: make(map[Servico]map[string]fixture),
+		scenarios:        make(map[Servico]string),
+		status:           make(map[Servico]status),
+		httpStatus:       make(map[Servico]int),
+	}
+}
+
+func (h *Handler) AddPessoaFisica(pf soawebservices.PessoaFisica) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.pessoasFisicas[documento.SomenteDigitos(pf.Documento)] = pf
+}
+
+func (h *Handler) AddPessoaJuridica(pj soawebservices.PessoaJuridica) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.pessoasJuridicas[documento.SomenteDigitos(pj.Documento)] = pj
+}
+
+func (h *Handler) AddCEP(cep soawebservices.CEP) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.ceps[documento.SomenteDigitos(cep.CEP)] = cep
+}
+
+// SetStatus makes every following request to the given service be answered
+// with the given CodigoStatus, e.g. soawebservices.StatusCredenciaisInvalidas.
+func (h *Handler) SetStatus(servico Servico, codigoStatus, descricao string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.status[servico] = status{codigo: codigoStatus, descricao: descricao}
+}
+
+// SetHTTPStatus makes every following request to the given service fail with
+// the given HTTP status code and an empty body. A zero status code removes the
+// failure.
+func (h *Handler) SetHTTPStatus(servico Servico, statusCode int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.httpStatus[servico] = statusCode
+}
+
+// SetLatency delays every following response by the given duration.
+func (h *Handler) SetLatency(latency time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.latency = latency
+}
+
+// Reset removes every scenario, injected status, failure and latency, keeping
+// the registered data and the received requests.
+func (h *Handler) Reset() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.scenarios = make(map[Servico]string)
+	h.status = make(map[Servico]status)
+	h.httpStatus = make(map[Servico]int)
+	h.latency = 0
+}
+
+// Requests returns the requests received so far, in arrival order.
+func (h *Handler) Requests() []Request {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	requests := make([]Request, len(h.requests))
+	copy(requests, h.requests)
+	return requests
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	servico, ambiente, ok := parsePath(r.URL.Path)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	req, err := decodeRequest(r.Method, r.URL.Path, servico, ambiente, body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h.mu.Lock()
+	h.requests = append(h.requests, req)
+	latency := h.latency
+	statusCode := h.httpStatus[req.Servico]
+	h.mu.Unlock()
+
+	if latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+	if statusCode != 0 {
+		w.WriteHeader(statusCode)
+		return
+	}
+	resp, contentType, err := h.response(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	_, _ = w.Write(resp)
+}
+
+// response returns the response to the given request, from the first of:
+// the injected status, the current scenario, the registered data, the
+// fixtures of the requested document, or a not found status.
+func (h *Handler) response(req Request) ([]byte, string, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	contentType := "application/json"
+	if req.Servico == ServicoCEP || req.Servico == ServicoBuscaCEP {
+		contentType = "text/xml; charset=utf-8"
+	}
+	if injected, ok := h.status[req.Servico]; ok {
+		buf, err := statusResponse(req, injected)
+		return buf, contentType, err
+	}
+	if scenario, ok := h.scenarios[req.Servico]; ok {
+		return h.fixtures[req.Servico][scenario].body, contentType, nil
+	}
+	if buf, ok, err := h.dataResponse(req); ok || err != nil {
+		return buf, contentType, err
+	}
+	if f, ok := h.documentFixtures[req.Servico][req.Documento]; ok {
+		return f.body, contentType, nil
+	}
+	notFound := status{codigo: statusDocumentoInvalido, descricao: "Documento invalido para consulta"}
+	if req.Servico == ServicoCEP || req.Servico == ServicoBuscaCEP {
+		notFound = status{codigo: statusCEPNaoEncontrado, descricao: "CEP nao foi encontrado"}
+	}
+	buf, err := statusResponse(req, notFound)
+	return buf, contentType, err
+}
+
+// dataResponse returns the response built from the data registered for the
+// requested document, if any. It must be called with the lock held.
+func (h *Handler) dataResponse(req Request) ([]byte, bool, error) {
+	sucesso := newTransacao(statusSucesso, "Transacao realizada com sucesso")
+	switch req.Servico {
+	case ServicoCPF:
+		pf, ok := h.pessoasFisicas[req.Documento]
+		if !ok {
+			return nil, false, nil
+		}
+		resp := newPessoaFisicaResponse(pf)
+		resp.Transacao, resp.Status, resp.Mensagem = sucesso, true, sucesso.CodigoStatusDescricao
+		buf, err := json.Marshal(resp)
+		return buf, true, err
+	case ServicoCNPJ:
+		pj, ok := h.pessoasJuridicas[req.Documento]
+		if !ok {
+			return nil, false, nil
+		}
+		resp := newPessoaJuridicaResponse(pj)
+		resp.Transacao, resp.Status, resp.Mensagem = sucesso, true, sucesso.CodigoStatusDescricao
+		buf, err := json.Marshal(resp)
+		return buf, true, err
+	case ServicoCEP:
+		cep, ok := h.ceps[req.Documento]
+		if !ok {
+			return nil, false, nil
+		}
+		result := newCEPResult(cep)
+		result.Transacao, result.Status, result.Mensagem = sucesso, true, sucesso.CodigoStatusDescricao
+		buf, err := marshalCEPResponse(result)
+		return buf, true, err
+	case ServicoBuscaCEP:
+		enderecos := h.buscarCEPs(req.Documento)
+		if len(enderecos) == 0 {
+			return nil, false, nil
+		}
+		result := buscaCEPResult{Enderecos: enderecos}
+		result.Transacao, result.Status, result.Mensagem = sucesso, true, sucesso.CodigoStatusDescricao
+		buf, err := marshalBuscaCEPResponse(result)
+		return buf, true, err
+	}
+	return nil, false, fmt.Errorf("unknown service %q", req.Servico)
+}
+
+// buscarCEPs returns the registered CEPs matching the UF, Cidade and
+// Logradouro of the given BuscaCEP Documento, ordered by CEP. It must be
+// called with the lock held.
+func (h *Handler) buscarCEPs(busca string) []enderecoResult {
+	parts := strings.SplitN(busca, "/", 3)
+	if len(parts) != 3 {
+		return nil
+	}
+	uf, cidade, palavras := parts[0], texto.Normalizar(parts[1]), texto.Tokens(parts[2])
+	var enderecos []enderecoResult
+	for _, cep := range h.ceps {
+		if !strings.EqualFold(cep.UF, uf) || texto.Normalizar(cep.Cidade) != cidade {
+			continue
+		}
+		if temPalavra(texto.Tokens(cep.LogradouroCompleto), palavras) {
+			enderecos = append(enderecos, newEnderecoResult(cep))
+		}
+	}
+	sort.Slice(enderecos, func(i, j int) bool {
+		return enderecos[i].CEP < enderecos[j].CEP
+	})
+	return enderecos
+}
+
+// temPalavra reports whether any of the given words is a prefix of any of the
+// given tokens, as the client does when ranking the results.
+func temPalavra(tokens, palavras []string) bool {
+	for _, p := range palavras {
+		for _, t := range tokens {
+			if strings.HasPrefix(t, p) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// statusResponse returns a response carrying only the given status.
+func statusResponse(req Request, s status) ([]byte, error) {
+	transacao := newTransacao(s.codigo, s.descricao)
+	if req.Servico == ServicoBuscaCEP {
+		return marshalBuscaCEPResponse(buscaCEPResult{
+			Mensagem:  s.descricao,
+			Status:    transacao.Status,
+			Transacao: transacao,
+		})
+	}
+	if req.Servico == ServicoCEP {
+		return marshalCEPResponse(cepResult{
+			CEP:       req.Documento,
+			Mensagem:  s.descricao,
+			Status:    transacao.Status,
+			Transacao: transacao,
+		})
+	}
+	return json.Marshal(pessoaFisicaResponse{
+		Documento: req.Documento,
+		Mensagem:  s.descricao,
+		Status:    transacao.Status,
+		Transacao: transacao,
+	})
+}
+
+func parsePath(path string) (Servico, soawebservices.Ambiente, bool) {
+	parts := strings.SplitN(strings.TrimPrefix(path, "/"), "/", 3)
+	if len(parts) != 3 {
+		return "", "", false
+	}
+	ambiente := soawebservices.Ambiente(parts[1])
+	switch {
+	case parts[0] == "restservices" && parts[2] == pathCPF:
+		return ServicoCPF, ambiente, true
+	case parts[0] == "restservices" && parts[2] == pathCNPJ:
+		return ServicoCNPJ, ambiente, true
+	case parts[0] == "webservices" && parts[2] == pathCEP:
+		return ServicoCEP, ambiente, true
+	}
+	return "", "", false
+}
+
+func decodeRequest(method, path string, servico Servico, ambiente soawebservices.Ambiente, body []byte) (Request, error) {
+	req := Request{
+		Method:     method,
+		Path:       path,
+		Servico:    servico,
+		Ambiente:   ambiente,
+		Body:       body,
+		ReceivedAt: time.Now(),
+	}
+	if servico == ServicoCEP {
+		consulta, err := unmarshalConsultaCEP(body)
+		if err != nil {
+			return Request{}, fmt.Errorf("invalid SOAP request: %w", err)
+		}
+		switch {
+		case consulta.Body.Consulta != nil:
+			req.Credenciais = soawebservices.Credenciais(consulta.Body.Consulta.Credenciais)
+			req.Documento = documento.SomenteDigitos(consulta.Body.Consulta.CEP)
+		case consulta.Body.Logradouro != nil:
+			busca := consulta.Body.Logradouro
+			req.Servico = ServicoBuscaCEP
+			req.Credenciais = soawebservices.Credenciais(busca.Credenciais)
+			req.Documento = strings.Join([]string{busca.UF, busca.Cidade, busca.Logradouro}, "/")
+		}
+		return req, nil
+	}
+	consulta, err := unmarshalConsultaDocumento(body)
+	if err != nil {
+		return Request{}, fmt.Errorf("invalid JSON request: %w", err)
+	}
+	req.Credenciais = soawebservices.Credenciais(consulta.Credenciais)
+	req.Documento = documento.SomenteDigitos(consulta.Documento)
+	req.DataNascimento = consulta.DataNascimento
+	return req, nil
+}
@@ -0,0 +1,302 @@
+package soawebservicestest
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"github.com/diegohordi/soawebservices"
+	"strconv"
+	"strings"
+)
+
+const (
+	pathCPF  = "cdc/pessoafisicanfe.ashx"
+	pathCNPJ = "cdc/pessoajuridicanfe.ashx"
+	pathCEP  = "cep/cep.asmx"
+)
+
+const (
+	statusSucesso           = "G000M001"
+	statusDocumentoInvalido = "G000M003"
+	statusCEPNaoEncontrado  = "P016M002"
+)
+
+type transacao struct {
+	Status                bool   `xml:"Status" json:"Status"`
+	CodigoStatus          string `xml:"CodigoStatus" json:"CodigoStatus"`
+	CodigoStatusDescricao string `xml:"CodigoStatusDescricao" json:"CodigoStatusDescricao"`
+}
+
+func newTransacao(codigoStatus, descricao string) transacao {
+	return transacao{
+		Status:                codigoStatus == statusSucesso,
+		CodigoStatus:          codigoStatus,
+		CodigoStatusDescricao: descricao,
+	}
+}
+
+type credenciais struct {
+	Email string `xml:"Email" json:"Email"`
+	Senha string `xml:"Senha" json:"Senha"`
+}
+
+type consultaDocumento struct {
+	Credenciais    credenciais `json:"Credenciais"`
+	Documento      string      `json:"Documento"`
+	DataNascimento string      `json:"DataNascimento"`
+}
+
+// consultaCEP is a request to the CEP endpoint, which serves both the lookup
+// of a CEP and the search of the CEPs of a street (BuscaCEP).
+type consultaCEP struct {
+	XMLName xml.Name
+	Body    struct {
+		Consulta *struct {
+			Credenciais credenciais `xml:"Credenciais"`
+			CEP         string      `xml:"CEP"`
+		} `xml:"ConsultaCEPEstendida"`
+		Logradouro *struct {
+			Credenciais credenciais `xml:"Credenciais"`
+			UF          string      `xml:"UF"`
+			Cidade      string      `xml:"Cidade"`
+			Logradouro  string      `xml:"Logradouro"`
+		} `xml:"ConsultaLogradouro"`
+	} `xml:"Body"`
+}
+
+type pessoaFisicaResponse struct {
+	Documento               string    `json:"Documento"`
+	Nome                    string    `json:"Nome,omitempty"`
+	NomeSocial              string    `json:"NomeSocial,omitempty"`
+	DataNascimento          string    `json:"DataNascimento,omitempty"`
+	DataInscricao           string    `json:"DataInscricao,omitempty"`
+	CodigoSituacaoCadastral string    `json:"CodigoSituacaoCadastral,omitempty"`
+	SituacaoRFB             string    `json:"SituacaoRFB,omitempty"`
+	DataConsultaRFB         string    `json:"DataConsultaRFB,omitempty"`
+	AnoObito                string    `json:"AnoObito,omitempty"`
+	MensagemObito           string    `json:"MensagemObito,omitempty"`
+	Mensagem                string    `json:"Mensagem"`
+	Status                  bool      `json:"Status"`
+	Transacao               transacao `json:"Transacao"`
+}
+
+func newPessoaFisicaResponse(pf soawebservices.PessoaFisica) pessoaFisicaResponse {
+	resp := pessoaFisicaResponse{
+		Documento:               pf.Documento,
+		Nome:                    pf.Nome,
+		NomeSocial:              pf.NomeSocial,
+		CodigoSituacaoCadastral: string(pf.Status),
+		SituacaoRFB:             pf.SituacaoRFB,
+		MensagemObito:           pf.MensagemObito,
+	}
+	if pf.AnoObito != 0 {
+		resp.AnoObito = strconv.Itoa(pf.AnoObito)
+	}
+	if !pf.DataNascimento.IsZero() {
+		resp.DataNascimento = pf.DataNascimento.Format("02/01/2006")
+	}
+	if !pf.DataInscricao.IsZero() {
+		resp.DataInscricao = pf.DataInscricao.Format("02/01/2006")
+	}
+	if !pf.DataConsultaRFB.IsZero() {
+		resp.DataConsultaRFB = pf.DataConsultaRFB.Format("02/01/2006 15:04:05")
+	}
+	return resp
+}
+
+type pessoaJuridicaResponse struct {
+	Documento                         string    `json:"Documento"`
+	RazaoSocial                       string    `json:"RazaoSocial,omitempty"`
+	NomeFantasia                      string    `json:"NomeFantasia,omitempty"`
+	DataFundacao                      string    `json:"DataFundacao,omitempty"`
+	MatrizFilial                      string    `json:"MatrizFilial,omitempty"`
+	CodigoAtividadeEconomica          string    `json:"CodigoAtividadeEconomica,omitempty"`
+	CodigoAtividadeEconomicaDescricao string    `json:"CodigoAtividadeEconomicaDescricao,omitempty"`
+	CodigoNaturezaJuridica            string    `json:"CodigoNaturezaJuridica,omitempty"`
+	CodigoNaturezaJuridicaDescricao   string    `json:"CodigoNaturezaJuridicaDescricao,omitempty"`
+	SituacaoRFB                       string    `json:"SituacaoRFB,omitempty"`
+	DataSituacaoRFB                   string    `json:"DataSituacaoRFB,omitempty"`
+	DataConsultaRFB                   string    `json:"DataConsultaRFB,omitempty"`
+	MotivoSituacaoRFB                 string    `json:"MotivoSituacaoRFB,omitempty"`
+	DataMotivoEspecialSituacaoRFB     string    `json:"DataMotivoEspecialSituacaoRFB,omitempty"`
+	Email                             string    `json:"Email,omitempty"`
+	Telefone                          string    `json:"Telefone,omitempty"`
+	QSA                               *qsa      `json:"QSA,omitempty"`
+	Mensagem                          string    `json:"Mensagem"`
+	Status                            bool      `json:"Status"`
+	Transacao                         transacao `json:"Transacao"`
+}
+
+func newPessoaJuridicaResponse(pj soawebservices.PessoaJuridica) pessoaJuridicaResponse {
+	resp := pessoaJuridicaResponse{
+		Documento:                         pj.Documento,
+		RazaoSocial:                       pj.RazaoSocial,
+		NomeFantasia:                      pj.NomeFantasia,
+		MatrizFilial:                      "FILIAL",
+		CodigoAtividadeEconomica:          pj.CNAE.Codigo,
+		CodigoAtividadeEconomicaDescricao: pj.CNAE.Descricao,
+		CodigoNaturezaJuridica:            pj.NaturezaJuridica.Codigo,
+		CodigoNaturezaJuridicaDescricao:   pj.NaturezaJuridica.Descricao,
+		SituacaoRFB:                       pj.SituacaoRFB,
+		MotivoSituacaoRFB:                 pj.MotivoSituacaoRFB,
+		Email:                             pj.Email,
+		Telefone:                          pj.Telefone,
+	}
+	if pj.Matriz {
+		resp.MatrizFilial = "MATRIZ"
+	}
+	if len(pj.QSA.Socios) > 0 || len(pj.QSA.Administradores) > 0 {
+		resp.QSA = newQSA(pj.QSA)
+	}
+	if !pj.DataFundacao.IsZero() {
+		resp.DataFundacao = pj.DataFundacao.Format("02/01/2006")
+	}
+	if !pj.DataSituacaoRFB.IsZero() {
+		resp.DataSituacaoRFB = pj.DataSituacaoRFB.Format("02/01/2006")
+	}
+	if !pj.DataConsultaRFB.IsZero() {
+		resp.DataConsultaRFB = pj.DataConsultaRFB.Format("02/01/2006 15:04:05")
+	}
+	if !pj.DataMotivoEspecialSituacaoRFB.IsZero() {
+		resp.DataMotivoEspecialSituacaoRFB = pj.DataMotivoEspecialSituacaoRFB.Format("02/01/2006")
+	}
+	return resp
+}
+
+type socio struct {
+	Pessoa    int    `json:"Pessoa"`
+	Documento string `json:"Documento"`
+	Nome      string `json:"Nome"`
+	Cargo     string `json:"Cargo,omitempty"`
+}
+
+type qsa struct {
+	Socios          []socio `json:"Socios"`
+	Administradores []socio `json:"Administradores"`
+}
+
+func newQSA(q soawebservices.QSA) *qsa {
+	resp := &qsa{}
+	for _, s := range q.Socios {
+		resp.Socios = append(resp.Socios, socio{Pessoa: int(s.Pessoa), Documento: s.Documento, Nome: s.Nome})
+	}
+	for _, a := range q.Administradores {
+		resp.Administradores = append(resp.Administradores, socio{Pessoa: int(a.Pessoa), Documento: a.Documento, Nome: a.Nome, Cargo: a.Cargo})
+	}
+	return resp
+}
+
+type cepResult struct {
+	CEP                   string    `xml:"CEP,omitempty"`
+	TipoLogradouro        string    `xml:"TipoLogradouro,omitempty"`
+	LogradouroCompleto    string    `xml:"LogradouroCompleto,omitempty"`
+	LogradouroComplemento string    `xml:"LogradouroComplemento,omitempty"`
+	Bairro                string    `xml:"Bairro,omitempty"`
+	UF                    string    `xml:"UF,omitempty"`
+	Cidade                string    `xml:"Cidade,omitempty"`
+	CodigoIBGE            string    `xml:"CodigoIBGE,omitempty"`
+	Mensagem              string    `xml:"Mensagem"`
+	Status                bool      `xml:"Status"`
+	Transacao             transacao `xml:"Transacao"`
+}
+
+func newCEPResult(cep soawebservices.CEP) cepResult {
+	return cepResult{
+		CEP:                   cep.CEP,
+		TipoLogradouro:        cep.TipoLogradouro,
+		LogradouroCompleto:    cep.LogradouroCompleto,
+		LogradouroComplemento: cep.LogradouroComplemento,
+		Bairro:                cep.Bairro,
+		UF:                    cep.UF,
+		Cidade:                cep.Cidade,
+		CodigoIBGE:            cep.CodigoIBGE,
+	}
+}
+
+type cepResponseEnvelope struct {
+	XMLName xml.Name `xml:"soap:Envelope"`
+	Soap    string   `xml:"xmlns:soap,attr"`
+	Body    struct {
+		Response struct {
+			Namespace string    `xml:"xmlns,attr"`
+			Result    cepResult `xml:"ConsultaCEPEstendidaResult"`
+		} `xml:"ConsultaCEPEstendidaResponse"`
+	} `xml:"soap:Body"`
+}
+
+type enderecoResult struct {
+	CEP                   string `xml:"CEP"`
+	UF                    string `xml:"UF"`
+	TipoLogradouro        string `xml:"TipoLogradouro,omitempty"`
+	Logradouro            string `xml:"Logradouro,omitempty"`
+	LogradouroCompleto    string `xml:"LogradouroCompleto,omitempty"`
+	LogradouroComplemento string `xml:"LogradouroComplemento,omitempty"`
+	Bairro                string `xml:"Bairro,omitempty"`
+	Cidade                string `xml:"Cidade,omitempty"`
+	CodigoIBGE            string `xml:"CodigoIBGE,omitempty"`
+}
+
+type buscaCEPResult struct {
+	Enderecos []enderecoResult `xml:"Enderecos>Endereco,omitempty"`
+	Mensagem  string           `xml:"Mensagem"`
+	Status    bool             `xml:"Status"`
+	Transacao transacao        `xml:"Transacao"`
+}
+
+func newEnderecoResult(cep soawebservices.CEP) enderecoResult {
+	return enderecoResult{
+		CEP:                   cep.CEP,
+		UF:                    cep.UF,
+		TipoLogradouro:        cep.TipoLogradouro,
+		Logradouro:            strings.TrimSpace(strings.TrimPrefix(cep.LogradouroCompleto, cep.TipoLogradouro)),
+		LogradouroCompleto:    cep.LogradouroCompleto,
+		LogradouroComplemento: cep.LogradouroComplemento,
+		Bairro:                cep.Bairro,
+		Cidade:                cep.Cidade,
+		CodigoIBGE:            cep.CodigoIBGE,
+	}
+}
+
+type buscaCEPResponseEnvelope struct {
+	XMLName xml.Name `xml:"soap:Envelope"`
+	Soap    string   `xml:"xmlns:soap,attr"`
+	Body    struct {
+		Response struct {
+			Namespace string         `xml:"xmlns,attr"`
+			Result    buscaCEPResult `xml:"ConsultaLogradouroResult"`
+		} `xml:"ConsultaLogradouroResponse"`
+	} `xml:"soap:Body"`
+}
+
+func marshalBuscaCEPResponse(result buscaCEPResult) ([]byte, error) {
+	envelope := buscaCEPResponseEnvelope{Soap: "http://schemas.xmlsoap.org/soap/envelope/"}
+	envelope.Body.Response.Namespace = "SOAWebServices"
+	envelope.Body.Response.Result = result
+	buf, err := xml.Marshal(envelope)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), buf...), nil
+}
+
+func marshalCEPResponse(result cepResult) ([]byte, error) {
+	envelope := cepResponseEnvelope{Soap: "http://schemas.xmlsoap.org/soap/envelope/"}
+	envelope.Body.Response.Namespace = "SOAWebServices"
+	envelope.Body.Response.Result = result
+	buf, err := xml.Marshal(envelope)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), buf...), nil
+}
+
+func unmarshalConsultaDocumento(body []byte) (consultaDocumento, error) {
+	consulta := consultaDocumento{}
+	err := json.Unmarshal(body, &consulta)
+	return consulta, err
+}
+
+func unmarshalConsultaCEP(body []byte) (consultaCEP, error) {
+	consulta := consultaCEP{}
+	err := xml.Unmarshal(body, &consulta)
+	return consulta, err
+}
@@ -0,0 +1,33 @@
+// Package soawebservicestest provides test doubles for code that depends on
+// the soawebservices client.
+package soawebservicestest
+
+import (
+	"github.com/diegohordi/soawebservices"
+	"net/http/httptest"
+)
+
+// Server is an httptest.Server backed by a Handler. The Handler methods can be
+// used directly on the Server to register data and inject failures.
+type Server struct {
+	*httptest.Server
+	*Handler
+}
+
+func NewServer() *Server {
+	handler := NewHandler()
+	return &Server{
+		Server:  httptest.NewServer(handler),
+		Handler: handler,
+	}
+}
+
+// NewClient returns a soawebservices.Client pointing to the server, using the
+// TestDrive environment.
+func (s *Server) NewClient(credenciais soawebservices.Credenciais) soawebservices.Client {
+	client, err := soawebservices.NewClient(s.Client(), s.URL, soawebservices.TestDrive, credenciais)
+	if err != nil {
+		panic(err)
+	}
+	return client
+}
@@ -0,0 +1,299 @@
+package soawebservicestest_test
+
+import (
+	"context"
+	"errors"
+	"github.com/diegohordi/soawebservices"
+	"github.com/diegohordi/soawebservices/soawebservicestest"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var credenciais = soawebservices.Credenciais{Email: "test@test.com", Senha: "test"}
+
+func TestServer_ConsultarCPF(t *testing.T) {
+	pf := soawebservices.PessoaFisica{
+		Documento:      "99999999999",
+		Nome:           "DOCUMENTO CPF DE TESTE",
+		DataNascimento: time.Date(1990, 1, 2, 0, 0, 0, 0, soawebservices.SaoPaulo),
+		Status:         soawebservices.Regular,
+	}
+	tests := []struct {
+		name    string
+		setup   func(s *soawebservicestest.Server)
+		cpf     string
+		want    soawebservices.PessoaFisica
+		wantErr error
+	}{
+		{
+			name:  "should return the registered Pessoa Física",
+			setup: func(s *soawebservicestest.Server) { s.AddPessoaFisica(pf) },
+			cpf:   "999.999.999-99",
+			want:  pf,
+		},
+		{
+			name:    "should fail due to an unknown document",
+			setup:   func(s *soawebservicestest.Server) {},
+			cpf:     "111.111.111-11",
+			wantErr: soawebservices.ErrCPFInvalido,
+		},
+		{
+			name: "should fail due to the injected status",
+			setup: func(s *soawebservicestest.Server) {
+				s.AddPessoaFisica(pf)
+				s.SetStatus(soawebservicestest.ServicoCPF, soawebservices.StatusDataNascimentoInvalida, "Data de nascimento é invalida")
+			},
+			cpf:     "999.999.999-99",
+			wantErr: soawebservices.ErrDataNascimentoInvalida,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			server := soawebservicestest.NewServer()
+			defer server.Close()
+			tt.setup(server)
+			result, err := server.NewClient(credenciais).ConsultarCPF(context.TODO(), tt.cpf, pf.DataNascimento)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ConsultarCPF() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(result, tt.want) {
+				t.Error("want ", tt.want, " but got ", result)
+			}
+		})
+	}
+}
+
+func TestServer_ConsultarCNPJ(t *testing.T) {
+	pj := soawebservices.PessoaJuridica{
+		Documento:        "99999999999962",
+		RazaoSocial:      "DOCUMENTO CNPJ DE TESTES",
+		NomeFantasia:     "EMPRESA DE TESTES",
+		DataFundacao:     time.Date(2007, 5, 2, 0, 0, 0, 0, soawebservices.SaoPaulo),
+		Matriz:           true,
+		CNAE:             soawebservices.CNAE{Codigo: "82.91-1-00", Descricao: "Atividades de cobranças e informações cadastrais"},
+		NaturezaJuridica: soawebservices.NaturezaJuridica{Codigo: "206-2", Descricao: "SOCIEDADE EMPRESARIA LIMITADA"},
+		Email:            "email@email.com",
+		Telefone:         "1199999999",
+	}
+	tests := []struct {
+		name    string
+		setup   func(s *soawebservicestest.Server)
+		want    soawebservices.PessoaJuridica
+		wantErr error
+	}{
+		{
+			name:  "should return the registered Pessoa Jurídica",
+			setup: func(s *soawebservicestest.Server) { s.AddPessoaJuridica(pj) },
+			want:  pj,
+		},
+		{
+			name: "should fail due to the wrong credentials",
+			setup: func(s *soawebservicestest.Server) {
+				s.AddPessoaJuridica(pj)
+				s.SetStatus(soawebservicestest.ServicoCNPJ, soawebservices.StatusCredenciaisInvalidas, "Credenciais de Acesso (Usuario e/ou Senha) Invalidos")
+			},
+			wantErr: soawebservices.ErrCredenciaisInvalidas,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			server := soawebservicestest.NewServer()
+			defer server.Close()
+			tt.setup(server)
+			result, err := server.NewClient(credenciais).ConsultarCNPJ(context.TODO(), "99.999.999/9999-62")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ConsultarCNPJ() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(result, tt.want) {
+				t.Error("want ", tt.want, " but got ", result)
+			}
+		})
+	}
+}
+
+func TestServer_ConsultarCEP(t *testing.T) {
+	cep := soawebservices.CEP{
+		CEP:                "99999999",
+		UF:                 "XX",
+		TipoLogradouro:     "RUA",
+		LogradouroCompleto: "RUA LOGRADOURO DE TESTES",
+		Bairro:             "BAIRRO DE TESTES",
+		Cidade:             "CIDADE DE TESTES",
+		CodigoIBGE:         "99999",
+	}
+	tests := []struct {
+		name    string
+		setup   func(s *soawebservicestest.Server)
+		cep     string
+		want    soawebservices.CEP
+		wantErr error
+	}{
+		{
+			name:  "should return the registered CEP",
+			setup: func(s *soawebservicestest.Server) { s.AddCEP(cep) },
+			cep:   "99999-999",
+			want:  cep,
+		},
+		{
+			name:  "should return an empty CEP",
+			setup: func(s *soawebservicestest.Server) { s.AddCEP(cep) },
+			cep:   "12345-123",
+			want:  soawebservices.CEP{},
+		},
+		{
+			name: "should fail due to service unavailability",
+			setup: func(s *soawebservicestest.Server) {
+				s.SetStatus(soawebservicestest.ServicoCEP, soawebservices.StatusCEPServicoIndisponivel, "Servico dos Correios indisponivel no momento")
+			},
+			cep:     "99999-999",
+			wantErr: soawebservices.ErrCEPServicoIndisponivel,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			server := soawebservicestest.NewServer()
+			defer server.Close()
+			tt.setup(server)
+			result, err := server.NewClient(credenciais).ConsultarCEP(context.TODO(), tt.cep)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ConsultarCEP() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(result, tt.want) {
+				t.Error("want ", tt.want, " but got ", result)
+			}
+		})
+	}
+}
+
+func TestServer_BuscarCEPPorLogradouro(t *testing.T) {
+	cep := soawebservices.CEP{
+		CEP:                "99999999",
+		UF:                 "SP",
+		TipoLogradouro:     "RUA",
+		LogradouroCompleto: "RUA LOGRADOURO DE TESTES",
+		Bairro:             "BAIRRO DE TESTES",
+		Cidade:             "São Paulo",
+		CodigoIBGE:         "99999",
+	}
+	tests := []struct {
+		name       string
+		setup      func(s *soawebservicestest.Server)
+		uf         string
+		cidade     string
+		logradouro string
+		want       soawebservices.BuscaCEP
+		wantErr    error
+	}{
+		{
+			name:       "should return the registered CEPs of the logradouro",
+			setup:      func(s *soawebservicestest.Server) { s.AddCEP(cep) },
+			uf:         "sp",
+			cidade:     "SAO PAULO",
+			logradouro: "logradouro de testes",
+			want: soawebservices.BuscaCEP{Resultados: []soawebservices.CEPEncontrado{
+				{CEP: cep, Relevancia: 1},
+			}},
+		},
+		{
+			name:       "should return an empty BuscaCEP due to another city",
+			setup:      func(s *soawebservicestest.Server) { s.AddCEP(cep) },
+			uf:         "SP",
+			cidade:     "Campinas",
+			logradouro: "logradouro de testes",
+			want:       soawebservices.BuscaCEP{},
+		},
+		{
+			name:       "should return an empty BuscaCEP due to another logradouro",
+			setup:      func(s *soawebservicestest.Server) { s.AddCEP(cep) },
+			uf:         "SP",
+			cidade:     "São Paulo",
+			logradouro: "avenida paulista",
+			want:       soawebservices.BuscaCEP{},
+		},
+		{
+			name: "should fail due to service unavailability",
+			setup: func(s *soawebservicestest.Server) {
+				s.SetStatus(soawebservicestest.ServicoBuscaCEP, soawebservices.StatusCEPServicoIndisponivel, "Servico dos Correios indisponivel no momento")
+			},
+			uf:         "SP",
+			cidade:     "São Paulo",
+			logradouro: "logradouro de testes",
+			wantErr:    soawebservices.ErrCEPServicoIndisponivel,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			server := soawebservicestest.NewServer()
+			defer server.Close()
+			tt.setup(server)
+			result, err := server.NewClient(credenciais).BuscarCEPPorLogradouro(context.TODO(), tt.uf, tt.cidade, tt.logradouro)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("BuscarCEPPorLogradouro() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(result, tt.want) {
+				t.Error("want ", tt.want, " but got ", result)
+			}
+		})
+	}
+}
+
+func TestServer_Failures(t *testing.T) {
+	t.Run("should fail due to the injected HTTP status", func(t *testing.T) {
+		server := soawebservicestest.NewServer()
+		defer server.Close()
+		server.SetHTTPStatus(soawebservicestest.ServicoCNPJ, http.StatusInternalServerError)
+		if _, err := server.NewClient(credenciais).ConsultarCNPJ(context.TODO(), "99999999999962"); err == nil {
+			t.Error("expected an error")
+		}
+	})
+	t.Run("should fail due to the injected latency", func(t *testing.T) {
+		server := soawebservicestest.NewServer()
+		defer server.Close()
+		server.SetLatency(200 * time.Millisecond)
+		ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Millisecond)
+		defer cancel()
+		if _, err := server.NewClient(credenciais).ConsultarCEP(ctx, "99999999"); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected %v, got %v", context.DeadlineExceeded, err)
+		}
+	})
+}
+
+func TestServer_Requests(t *testing.T) {
+	server := soawebservicestest.NewServer()
+	defer server.Close()
+	client := server.NewClient(credenciais)
+	_, _ = client.ConsultarCEP(context.TODO(), "99999-999")
+	_, _ = client.ConsultarCPF(context.TODO(), "999.999.999-99", time.Date(1990, 1, 2, 0, 0, 0, 0, time.UTC))
+	_, _ = client.BuscarCEPPorLogradouro(context.TODO(), "SP", "São Paulo", "Avenida Paulista")
+	requests := server.Requests()
+	if len(requests) != 3 {
+		t.Fatalf("expected 3 requests, got %d", len(requests))
+	}
+	if requests[0].Servico != soawebservicestest.ServicoCEP || requests[0].Documento != "99999999" {
+		t.Errorf("unexpected CEP request: %+v", requests[0])
+	}
+	if requests[1].Servico != soawebservicestest.ServicoCPF || requests[1].DataNascimento != "02/01/1990" {
+		t.Errorf("unexpected CPF request: %+v", requests[1])
+	}
+	if requests[2].Servico != soawebservicestest.ServicoBuscaCEP || requests[2].Documento != "SP/São Paulo/Avenida Paulista" {
+		t.Errorf("unexpected BuscaCEP request: %+v", requests[2])
+	}
+	for _, req := range requests {
+		if req.Credenciais != credenciais {
+			t.Errorf("unexpected credentials: %+v", req.Credenciais)
+		}
+		if req.Ambiente != soawebservices.TestDrive {
+			t.Errorf("unexpected ambiente: %s", req.Ambiente)
+		}
+	}
+}
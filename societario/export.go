@@ -0,0 +1,45 @@
+package societario
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"github.com/diegohordi/soawebservices"
+	"io"
+)
+
+// WriteJSON writes the graph as indented JSON.
+func (g *Grafo) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(g)
+}
+
+// WriteDOT writes the graph in the Graphviz DOT language, with the companies
+// as boxes, the people as ellipses and the edges of cycles in red. The nodes
+// not looked up are dashed.
+func (g *Grafo) WriteDOT(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "digraph societario {")
+	fmt.Fprintln(bw, "  rankdir=BT;")
+	for _, no := range g.Nos {
+		forma := "ellipse"
+		if no.Pessoa != soawebservices.TipoPessoaFisica {
+			forma = "box"
+		}
+		estilo := ""
+		if !no.Resolvido {
+			estilo = ", style=dashed"
+		}
+		fmt.Fprintf(bw, "  %q [label=%q, shape=%s%s];\n", no.Documento, no.Nome+"\n"+no.Documento, forma, estilo)
+	}
+	for _, a := range g.Arestas {
+		cor := ""
+		if a.Ciclo {
+			cor = " [color=red]"
+		}
+		fmt.Fprintf(bw, "  %q -> %q%s;\n", a.Socio, a.Empresa, cor)
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
@@ -0,0 +1,304 @@
+// Package societario builds the ownership graph of a company, following the
+// QSA of its partners that are companies themselves.
+package societario
+
+import (
+	"context"
+	"errors"
+	"github.com/diegohordi/soawebservices"
+	"github.com/diegohordi/soawebservices/internal/documento"
+	"sync"
+	"time"
+)
+
+const (
+	ErrConsultasEsgotadas = soawebservices.Error("o limite de consultas do grafo societário foi atingido")
+)
+
+const (
+	defaultProfundidade = 5
+)
+
+// Client is the subset of the soawebservices.Client used to build the graph.
+type Client interface {
+	ConsultarCPF(ctx context.Context, cpf string, dataNascimento time.Time) (soawebservices.PessoaFisica, error)
+	ConsultarCNPJ(ctx context.Context, cnpj string) (soawebservices.PessoaJuridica, error)
+}
+
+// No is a company or a person of the graph. Profundidade is the distance to
+// the company the graph starts from, and Resolvido tells whether its document
+// was looked up, in which case PessoaJuridica or PessoaFisica holds the result.
+type No struct {
+	Documento      string                         `json:"documento"`
+	Pessoa         soawebservices.TipoPessoa      `json:"pessoa"`
+	Nome           string                         `json:"nome"`
+	Profundidade   int                            `json:"profundidade"`
+	Resolvido      bool                           `json:"resolvido"`
+	Erro           string                         `json:"erro,omitempty"`
+	PessoaJuridica *soawebservices.PessoaJuridica `json:"-"`
+	PessoaFisica   *soawebservices.PessoaFisica   `json:"-"`
+}
+
+// Aresta tells that Socio is a partner of Empresa. Ciclo is set when Empresa
+// is also, directly or not, a partner of Socio.
+type Aresta struct {
+	Socio   string `json:"socio"`
+	Empresa string `json:"empresa"`
+	Ciclo   bool   `json:"ciclo,omitempty"`
+}
+
+// Grafo is the ownership graph of the company Raiz. Truncado is set when some
+// company was not looked up due to the depth or to the lookups limit.
+type Grafo struct {
+	Raiz     string   `json:"raiz"`
+	Nos      []*No    `json:"nos"`
+	Arestas  []Aresta `json:"arestas"`
+	Truncado bool     `json:"truncado"`
+	indice   map[string]*No
+}
+
+// No returns the node of the given document, formatted or not.
+func (g *Grafo) No(doc string) (*No, bool) {
+	no, ok := g.indice[documento.SomenteDigitos(doc)]
+	return no, ok
+}
+
+// Socios returns the partners of the given company.
+func (g *Grafo) Socios(cnpj string) []*No {
+	cnpj = documento.SomenteDigitos(cnpj)
+	var socios []*No
+	for _, a := range g.Arestas {
+		if a.Empresa == cnpj {
+			socios = append(socios, g.indice[a.Socio])
+		}
+	}
+	return socios
+}
+
+func (g *Grafo) add(no *No) *No {
+	g.Nos = append(g.Nos, no)
+	g.indice[no.Documento] = no
+	return no
+}
+
+// marcarCiclos flags the edges whose company reaches back its partner.
+func (g *Grafo) marcarCiclos() {
+	empresas := make(map[string][]string)
+	for _, a := range g.Arestas {
+		empresas[a.Socio] = append(empresas[a.Socio], a.Empresa)
+	}
+	alcanca := func(de, para string) bool {
+		visitados := map[string]bool{de: true}
+		pilha := []string{de}
+		for len(pilha) > 0 {
+			atual := pilha[len(pilha)-1]
+			pilha = pilha[:len(pilha)-1]
+			if atual == para {
+				return true
+			}
+			for _, e := range empresas[atual] {
+				if !visitados[e] {
+					visitados[e] = true
+					pilha = append(pilha, e)
+				}
+			}
+		}
+		return false
+	}
+	for i, a := range g.Arestas {
+		g.Arestas[i].Ciclo = alcanca(a.Empresa, a.Socio)
+	}
+}
+
+// Option configures a Resolver.
+type Option func(*Resolver)
+
+// WithProfundidade limits the graph to the companies up to the given distance
+// from the first one. It defaults to 5.
+func WithProfundidade(profundidade int) Option {
+	return func(r *Resolver) {
+		r.profundidade = profundidade
+	}
+}
+
+// WithMaxConsultas limits the lookups made to build each graph, cached ones
+// aside. Zero, the default, means no limit.
+func WithMaxConsultas(maxConsultas int) Option {
+	return func(r *Resolver) {
+		r.maxConsultas = maxConsultas
+	}
+}
+
+// WithPessoasFisicas also looks up the partners that are people whose date of
+// birth is returned by the given function, as ConsultarCPF requires it.
+func WithPessoasFisicas(nascimento func(cpf string) (time.Time, bool)) Option {
+	return func(r *Resolver) {
+		r.nascimento = nascimento
+	}
+}
+
+// Resolver builds ownership graphs, caching the companies and people looked
+// up so far. It is safe for concurrent use.
+type Resolver struct {
+	client       Client
+	profundidade int
+	maxConsultas int
+	nascimento   func(cpf string) (time.Time, bool)
+	mu           sync.Mutex
+	empresas     map[string]soawebservices.PessoaJuridica
+	pessoas      map[string]soawebservices.PessoaFisica
+}
+
+func NewResolver(client Client, opts ...Option) *Resolver {
+	r := &Resolver{
+		client:       client,
+		profundidade: defaultProfundidade,
+		empresas:     make(map[string]soawebservices.PessoaJuridica),
+		pessoas:      make(map[string]soawebservices.PessoaFisica),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// montagem holds the state of a single graph being built.
+type montagem struct {
+	*Resolver
+	grafo     *Grafo
+	consultas int
+	esgotado  bool
+}
+
+// Montar builds the ownership graph of the given company, walking its
+// partners breadth first. Failures to look up a partner are recorded in its
+// node; only a failure to look up the first company or a cancelled context
+// are returned, the latter along with the graph built so far.
+func (r *Resolver) Montar(ctx context.Context, cnpj string) (*Grafo, error) {
+	cnpj = documento.SomenteDigitos(cnpj)
+	m := &montagem{Resolver: r, grafo: &Grafo{Raiz: cnpj, indice: make(map[string]*No)}}
+	pj, err := m.consultarCNPJ(ctx, cnpj)
+	if err != nil {
+		return nil, err
+	}
+	raiz := m.grafo.add(&No{Documento: cnpj, Pessoa: soawebservices.TipoPessoaJuridica, Nome: pj.RazaoSocial, Resolvido: true, PessoaJuridica: &pj})
+	fila := []*No{raiz}
+	for len(fila) > 0 {
+		empresa := fila[0]
+		fila = fila[1:]
+		for _, s := range empresa.PessoaJuridica.QSA.Socios {
+			doc := documento.SomenteDigitos(s.Documento)
+			if doc == "" {
+				continue
+			}
+			m.grafo.Arestas = append(m.grafo.Arestas, Aresta{Socio: doc, Empresa: empresa.Documento})
+			if _, ok := m.grafo.indice[doc]; ok {
+				continue
+			}
+			no := m.grafo.add(&No{Documento: doc, Pessoa: s.Pessoa, Nome: s.Nome, Profundidade: empresa.Profundidade + 1})
+			if err := m.resolver(ctx, no); err != nil {
+				m.grafo.marcarCiclos()
+				return m.grafo, err
+			}
+			if no.Resolvido && no.PessoaJuridica != nil {
+				fila = append(fila, no)
+			}
+		}
+	}
+	m.grafo.marcarCiclos()
+	return m.grafo, nil
+}
+
+// resolver looks up the given partner, returning only context errors.
+func (m *montagem) resolver(ctx context.Context, no *No) error {
+	var err error
+	switch no.Pessoa {
+	case soawebservices.TipoPessoaJuridica:
+		if no.Profundidade > m.profundidade {
+			m.grafo.Truncado = true
+			return nil
+		}
+		var pj soawebservices.PessoaJuridica
+		if pj, err = m.consultarCNPJ(ctx, no.Documento); err == nil {
+			no.PessoaJuridica = &pj
+			no.Nome = pj.RazaoSocial
+		}
+	case soawebservices.TipoPessoaFisica:
+		if m.nascimento == nil {
+			return nil
+		}
+		dataNascimento, ok := m.nascimento(no.Documento)
+		if !ok {
+			return nil
+		}
+		var pf soawebservices.PessoaFisica
+		if pf, err = m.consultarCPF(ctx, no.Documento, dataNascimento); err == nil {
+			no.PessoaFisica = &pf
+			no.Nome = pf.Nome
+		}
+	default:
+		return nil
+	}
+	switch {
+	case err == nil:
+		no.Resolvido = true
+	case errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded):
+		return err
+	case errors.Is(err, ErrConsultasEsgotadas) || errors.Is(err, soawebservices.ErrOrcamentoExcedido):
+		m.esgotado = true
+		m.grafo.Truncado = true
+		no.Erro = err.Error()
+	default:
+		no.Erro = err.Error()
+	}
+	return nil
+}
+
+// consultar checks whether another lookup may be made, counting it.
+func (m *montagem) consultar() error {
+	if m.esgotado || m.maxConsultas > 0 && m.consultas >= m.maxConsultas {
+		return ErrConsultasEsgotadas
+	}
+	m.consultas++
+	return nil
+}
+
+func (m *montagem) consultarCNPJ(ctx context.Context, cnpj string) (soawebservices.PessoaJuridica, error) {
+	m.mu.Lock()
+	pj, ok := m.empresas[cnpj]
+	m.mu.Unlock()
+	if ok {
+		return pj, nil
+	}
+	if err := m.consultar(); err != nil {
+		return soawebservices.PessoaJuridica{}, err
+	}
+	pj, err := m.client.ConsultarCNPJ(ctx, cnpj)
+	if err != nil {
+		return soawebservices.PessoaJuridica{}, err
+	}
+	m.mu.Lock()
+	m.empresas[cnpj] = pj
+	m.mu.Unlock()
+	return pj, nil
+}
+
+func (m *montagem) consultarCPF(ctx context.Context, cpf string, dataNascimento time.Time) (soawebservices.PessoaFisica, error) {
+	m.mu.Lock()
+	pf, ok := m.pessoas[cpf]
+	m.mu.Unlock()
+	if ok {
+		return pf, nil
+	}
+	if err := m.consultar(); err != nil {
+		return soawebservices.PessoaFisica{}, err
+	}
+	pf, err := m.client.ConsultarCPF(ctx, cpf, dataNascimento)
+	if err != nil {
+		return soawebservices.PessoaFisica{}, err
+	}
+	m.mu.Lock()
+	m.pessoas[cpf] = pf
+	m.mu.Unlock()
+	return pf, nil
+}
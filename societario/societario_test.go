@@ -0,0 +1,184 @@
+package societario_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/diegohordi/soawebservices"
+	"github.com/diegohordi/soawebservices/soawebservicestest"
+	"github.com/diegohordi/soawebservices/societario"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	cnpjRaiz    = "11222333000181"
+	cnpjHolding = "11444777000161"
+	cnpjFundo   = "45723174000110"
+	cnpjFilha   = "04252011000110"
+	cpfSocio    = "52998224725"
+)
+
+func empresa(cnpj, razaoSocial string, socios ...soawebservices.Socio) soawebservices.PessoaJuridica {
+	return soawebservices.PessoaJuridica{Documento: cnpj, RazaoSocial: razaoSocial, QSA: soawebservices.QSA{Socios: socios}}
+}
+
+func socioPJ(cnpj string) soawebservices.Socio {
+	return soawebservices.Socio{Pessoa: soawebservices.TipoPessoaJuridica, Documento: cnpj, Nome: "SOCIO " + cnpj}
+}
+
+// newFakeClient returns a client where the raiz is owned by a person and by
+// the holding, which is owned by the fundo, which is owned by the raiz.
+func newFakeClient() *soawebservicestest.FakeClient {
+	fake := soawebservicestest.NewFakeClient().Strict()
+	fake.OnCNPJ(cnpjRaiz).Return(empresa(cnpjRaiz, "RAIZ LTDA",
+		soawebservices.Socio{Pessoa: soawebservices.TipoPessoaFisica, Documento: cpfSocio, Nome: "JOSE DA SILVA"},
+		socioPJ(cnpjHolding)))
+	fake.OnCNPJ(cnpjHolding).Return(empresa(cnpjHolding, "HOLDING SA", socioPJ(cnpjFundo)))
+	fake.OnCNPJ(cnpjFundo).Return(empresa(cnpjFundo, "FUNDO FIP", socioPJ(cnpjRaiz), socioPJ(cnpjFilha)))
+	fake.OnCNPJ(cnpjFilha).ReturnErr(soawebservices.ErrCNPJInvalido)
+	return fake
+}
+
+func TestResolver_Montar(t *testing.T) {
+	fake := newFakeClient()
+	grafo, err := societario.NewResolver(fake).Montar(context.TODO(), "11.222.333/0001-81")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(grafo.Nos) != 5 || len(grafo.Arestas) != 5 || grafo.Truncado {
+		t.Fatalf("unexpected graph %+v", grafo)
+	}
+	if socio, _ := grafo.No(cpfSocio); socio.Resolvido || socio.Profundidade != 1 {
+		t.Errorf("expected the person not to be looked up, got %+v", socio)
+	}
+	if fundo, _ := grafo.No(cnpjFundo); !fundo.Resolvido || fundo.Nome != "FUNDO FIP" || fundo.Profundidade != 2 {
+		t.Errorf("unexpected fundo %+v", fundo)
+	}
+	if filha, _ := grafo.No(cnpjFilha); filha.Resolvido || filha.Erro != soawebservices.ErrCNPJInvalido.Error() {
+		t.Errorf("expected the error to be recorded, got %+v", filha)
+	}
+	ciclos := 0
+	for _, a := range grafo.Arestas {
+		if a.Ciclo {
+			ciclos++
+		}
+	}
+	if ciclos != 3 {
+		t.Errorf("expected 3 edges in the cycle, got %d", ciclos)
+	}
+	if got := len(grafo.Socios(cnpjFundo)); got != 2 {
+		t.Errorf("expected 2 partners of the fundo, got %d", got)
+	}
+	if got := len(fake.Calls()); got != 4 {
+		t.Errorf("expected 4 calls, got %d", got)
+	}
+}
+
+func TestResolver_Montar_limits(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      []societario.Option
+		wantCalls int
+	}{
+		{
+			name:      "should stop at the given depth",
+			opts:      []societario.Option{societario.WithProfundidade(1)},
+			wantCalls: 2,
+		},
+		{
+			name:      "should stop at the given number of lookups",
+			opts:      []societario.Option{societario.WithMaxConsultas(2)},
+			wantCalls: 2,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			fake := newFakeClient()
+			grafo, err := societario.NewResolver(fake, tt.opts...).Montar(context.TODO(), cnpjRaiz)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !grafo.Truncado {
+				t.Error("expected the graph to be truncated")
+			}
+			if fundo, _ := grafo.No(cnpjFundo); fundo.Resolvido {
+				t.Errorf("expected the fundo not to be looked up, got %+v", fundo)
+			}
+			if got := len(fake.Calls()); got != tt.wantCalls {
+				t.Errorf("expected %d calls, got %d", tt.wantCalls, got)
+			}
+		})
+	}
+}
+
+func TestResolver_Montar_cache(t *testing.T) {
+	fake := newFakeClient()
+	resolver := societario.NewResolver(fake, societario.WithPessoasFisicas(func(cpf string) (time.Time, bool) {
+		return time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC), cpf == cpfSocio
+	}))
+	fake.OnCPF(cpfSocio).Return(soawebservices.PessoaFisica{Documento: cpfSocio, Nome: "JOSE DA SILVA SAURO"})
+	for i := 0; i < 2; i++ {
+		grafo, err := resolver.Montar(context.TODO(), cnpjRaiz)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if socio, _ := grafo.No(cpfSocio); !socio.Resolvido || socio.Nome != "JOSE DA SILVA SAURO" {
+			t.Errorf("expected the person to be looked up, got %+v", socio)
+		}
+	}
+	// The failed lookup of the filha is the only one not cached.
+	if got := len(fake.Calls()); got != 6 {
+		t.Errorf("expected 6 calls, got %d", got)
+	}
+}
+
+func TestResolver_Montar_errors(t *testing.T) {
+	fake := newFakeClient()
+	if _, err := societario.NewResolver(fake).Montar(context.TODO(), cnpjFilha); !errors.Is(err, soawebservices.ErrCNPJInvalido) {
+		t.Errorf("expected error %v, got %v", soawebservices.ErrCNPJInvalido, err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := societario.NewResolver(fake).Montar(ctx, cnpjRaiz); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected error %v, got %v", context.Canceled, err)
+	}
+}
+
+func TestGrafo_export(t *testing.T) {
+	grafo, err := societario.NewResolver(newFakeClient()).Montar(context.TODO(), cnpjRaiz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := &bytes.Buffer{}
+	if err = grafo.WriteJSON(buf); err != nil {
+		t.Fatal(err)
+	}
+	decoded := societario.Grafo{}
+	if err = json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Raiz != cnpjRaiz || len(decoded.Nos) != 5 || len(decoded.Arestas) != 5 {
+		t.Errorf("unexpected decoded graph %+v", decoded)
+	}
+	buf.Reset()
+	if err = grafo.WriteDOT(buf); err != nil {
+		t.Fatal(err)
+	}
+	dot := buf.String()
+	for _, want := range []string{
+		"digraph societario {",
+		`"11222333000181" [label="RAIZ LTDA\n11222333000181", shape=box];`,
+		`"52998224725" [label="JOSE DA SILVA\n52998224725", shape=ellipse, style=dashed];`,
+		`"11222333000181" -> "45723174000110" [color=red];`,
+		`"52998224725" -> "11222333000181";`,
+	} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("expected %q in\n%s", want, dot)
+		}
+	}
+}
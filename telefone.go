@@ -0,0 +1,97 @@
+package soawebservices
+
+import (
+	"fmt"
+	"github.com/diegohordi/soawebservices/internal/documento"
+	"net/mail"
+	"strings"
+)
+
+const (
+	ErrTelefoneInvalido = Error("o telefone informado é inválido")
+	ErrEmailInvalido    = Error("o e-mail informado é inválido")
+)
+
+type TipoTelefone string
+
+const (
+	TelefoneMovel TipoTelefone = "MOVEL"
+	TelefoneFixo  TipoTelefone = "FIXO"
+)
+
+var ddds = map[string]bool{
+	"11": true, "12": true, "13": true, "14": true, "15": true, "16": true, "17": true, "18": true, "19": true,
+	"21": true, "22": true, "24": true, "27": true, "28": true,
+	"31": true, "32": true, "33": true, "34": true, "35": true, "37": true, "38": true,
+	"41": true, "42": true, "43": true, "44": true, "45": true, "46": true, "47": true, "48": true, "49": true,
+	"51": true, "53": true, "54": true, "55": true,
+	"61": true, "62": true, "63": true, "64": true, "65": true, "66": true, "67": true, "68": true, "69": true,
+	"71": true, "73": true, "74": true, "75": true, "77": true, "79": true,
+	"81": true, "82": true, "83": true, "84": true, "85": true, "86": true, "87": true, "88": true, "89": true,
+	"91": true, "92": true, "93": true, "94": true, "95": true, "96": true, "97": true, "98": true, "99": true,
+}
+
+// Telefone is a Brazilian phone number. Operadora is only known for the
+// numbers returned by the ContatoService.
+type Telefone struct {
+	DDD       string
+	Numero    string
+	Tipo      TipoTelefone
+	Operadora string
+}
+
+// ParseTelefone parses a Brazilian phone number with its DDD, formatted or
+// not, as in (11) 91234-5678, optionally with the +55 country code or the
+// 0 and carrier code prefixes of long distance calls. Mobile numbers must
+// have the ninth digit.
+func ParseTelefone(value string) (Telefone, error) {
+	digitos := documento.SomenteDigitos(value)
+	if (strings.HasPrefix(strings.TrimSpace(value), "+") || len(digitos) == 12 || len(digitos) == 13) && strings.HasPrefix(digitos, "55") {
+		digitos = digitos[2:]
+	}
+	if strings.HasPrefix(digitos, "0") {
+		switch len(digitos) {
+		case 11, 12:
+			digitos = digitos[1:]
+		case 13, 14:
+			digitos = digitos[3:]
+		}
+	}
+	if len(digitos) != 10 && len(digitos) != 11 || !ddds[digitos[:2]] {
+		return Telefone{}, fmt.Errorf("%w: %q", ErrTelefoneInvalido, value)
+	}
+	t := Telefone{DDD: digitos[:2], Numero: digitos[2:]}
+	switch {
+	case len(t.Numero) == 9 && t.Numero[0] == '9':
+		t.Tipo = TelefoneMovel
+	case len(t.Numero) == 8 && t.Numero[0] >= '2' && t.Numero[0] <= '5':
+		t.Tipo = TelefoneFixo
+	default:
+		return Telefone{}, fmt.Errorf("%w: %q", ErrTelefoneInvalido, value)
+	}
+	return t, nil
+}
+
+// String returns the Telefone in the national format, as in (11) 91234-5678.
+func (t Telefone) String() string {
+	if len(t.Numero) < 5 {
+		return t.Numero
+	}
+	meio := len(t.Numero) - 4
+	return fmt.Sprintf("(%s) %s-%s", t.DDD, t.Numero[:meio], t.Numero[meio:])
+}
+
+// E164 returns the Telefone in the E.164 format, as in +5511912345678.
+func (t Telefone) E164() string {
+	return "+55" + t.DDD + t.Numero
+}
+
+// ValidarEmail checks whether the given e-mail address is syntactically valid,
+// failing with ErrEmailInvalido otherwise.
+func ValidarEmail(email string) error {
+	endereco, err := mail.ParseAddress(email)
+	if err != nil || endereco.Address != strings.TrimSpace(email) || !strings.Contains(email[strings.LastIndex(email, "@")+1:], ".") {
+		return fmt.Errorf("%w: %q", ErrEmailInvalido, email)
+	}
+	return nil
+}
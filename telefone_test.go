@@ -0,0 +1,83 @@
+package soawebservices_test
+
+import (
+	"errors"
+	"github.com/diegohordi/soawebservices"
+	"testing"
+)
+
+func TestParseTelefone(t *testing.T) {
+	tests := []struct {
+		value    string
+		want     soawebservices.Telefone
+		wantE164 string
+		wantErr  bool
+	}{
+		{value: "(11) 91234-5678", want: soawebservices.Telefone{DDD: "11", Numero: "912345678", Tipo: soawebservices.TelefoneMovel}, wantE164: "+5511912345678"},
+		{value: "+55 21 3123-4567", want: soawebservices.Telefone{DDD: "21", Numero: "31234567", Tipo: soawebservices.TelefoneFixo}, wantE164: "+552131234567"},
+		{value: "5521912345678", want: soawebservices.Telefone{DDD: "21", Numero: "912345678", Tipo: soawebservices.TelefoneMovel}, wantE164: "+5521912345678"},
+		{value: "0 21 11 91234-5678", want: soawebservices.Telefone{DDD: "11", Numero: "912345678", Tipo: soawebservices.TelefoneMovel}, wantE164: "+5511912345678"},
+		{value: "011 3123-4567", want: soawebservices.Telefone{DDD: "11", Numero: "31234567", Tipo: soawebservices.TelefoneFixo}, wantE164: "+551131234567"},
+		{value: "55 3222-1234", want: soawebservices.Telefone{DDD: "55", Numero: "32221234", Tipo: soawebservices.TelefoneFixo}, wantE164: "+555532221234"},
+		{value: "(11) 8123-4567", wantErr: true},
+		{value: "(20) 91234-5678", wantErr: true},
+		{value: "91234-5678", wantErr: true},
+		{value: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.value, func(t *testing.T) {
+			t.Parallel()
+			got, err := soawebservices.ParseTelefone(tt.value)
+			if tt.wantErr {
+				if !errors.Is(err, soawebservices.ErrTelefoneInvalido) {
+					t.Fatalf("expected error %v, got %v", soawebservices.ErrTelefoneInvalido, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseTelefone() got = %+v, want %+v", got, tt.want)
+			}
+			if got.E164() != tt.wantE164 {
+				t.Errorf("E164() got = %s, want %s", got.E164(), tt.wantE164)
+			}
+		})
+	}
+}
+
+func TestTelefone_String(t *testing.T) {
+	tests := []struct {
+		telefone soawebservices.Telefone
+		want     string
+	}{
+		{telefone: soawebservices.Telefone{DDD: "11", Numero: "912345678"}, want: "(11) 91234-5678"},
+		{telefone: soawebservices.Telefone{DDD: "21", Numero: "31234567"}, want: "(21) 3123-4567"},
+	}
+	for _, tt := range tests {
+		if got := tt.telefone.String(); got != tt.want {
+			t.Errorf("String() got = %s, want %s", got, tt.want)
+		}
+	}
+}
+
+func TestValidarEmail(t *testing.T) {
+	tests := []struct {
+		email   string
+		wantErr bool
+	}{
+		{email: "contato@empresa.com.br"},
+		{email: "nome.sobrenome+crm@empresa.com"},
+		{email: "Contato <contato@empresa.com.br>", wantErr: true},
+		{email: "contato@localhost", wantErr: true},
+		{email: "contato.empresa.com.br", wantErr: true},
+		{email: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		if err := soawebservices.ValidarEmail(tt.email); (err != nil) != tt.wantErr {
+			t.Errorf("ValidarEmail(%q) error = %v, wantErr %v", tt.email, err, tt.wantErr)
+		}
+	}
+}
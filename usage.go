@@ -0,0 +1,195 @@
+package soawebservices
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+)
+
+type Servico string
+
+const (
+	ServicoCEP  Servico = "cep"
+	ServicoCPF  Servico = "cpf"
+	ServicoCNPJ Servico = "cnpj"
+
+	ServicoBuscaCEP          Servico = "busca-cep"
+	ServicoSimplesNacional   Servico = "simples-nacional"
+	ServicoInscricaoEstadual Servico = "inscricao-estadual"
+	ServicoVeiculo           Servico = "veiculo"
+	ServicoTelefones         Servico = "telefones"
+	ServicoEmails            Servico = "emails"
+	ServicoPEP               Servico = "pep"
+	ServicoSancoes           Servico = "sancoes"
+
+	ServicoScoreCPF       Servico = "score-cpf"
+	ServicoScoreCNPJ      Servico = "score-cnpj"
+	ServicoRestricoesCPF  Servico = "restricoes-cpf"
+	ServicoRestricoesCNPJ Servico = "restricoes-cnpj"
+)
+
+const (
+	ErrOrcamentoExcedido = Error("orçamento excedido")
+)
+
+// OrcamentoExcedidoError is returned, without calling the service, by the
+// calls that would take the spend of the client beyond its budget. It matches
+// ErrOrcamentoExcedido with errors.Is.
+type OrcamentoExcedidoError struct {
+	Servico   Servico
+	Custo     Valor
+	Gasto     Valor
+	Orcamento Valor
+}
+
+func (e *OrcamentoExcedidoError) Error() string {
+	return fmt.Sprintf("%s: a consulta de %s custaria %s, com %s gastos de um orçamento de %s", ErrOrcamentoExcedido, e.Servico, e.Custo, e.Gasto, e.Orcamento)
+}
+
+func (e *OrcamentoExcedidoError) Is(target error) bool {
+	return target == ErrOrcamentoExcedido
+}
+
+type labelKey struct{}
+
+// WithLabel returns a copy of ctx carrying the given label, under which the
+// usage and the spend of the calls made with it are accounted.
+func WithLabel(ctx context.Context, label string) context.Context {
+	return context.WithValue(ctx, labelKey{}, label)
+}
+
+// LabelFromContext returns the label carried by ctx, if any.
+func LabelFromContext(ctx context.Context) (string, bool) {
+	label, ok := ctx.Value(labelKey{}).(string)
+	return label, ok
+}
+
+// Usage holds the number of calls made by a tenant to a service and what
+// they cost. Calls without a tenant or a label in the context are attributed
+// to the empty ones. Falhas counts the failed calls, which are charged too
+// unless they are known not to be billed.
+type Usage struct {
+	Tenant    string
+	Label     string
+	Servico   Servico
+	Consultas int64
+	Falhas    int64
+	Gasto     Valor
+}
+
+// UsageReporter is implemented by the clients that account their usage, such
+// as the one returned by NewClient.
+type UsageReporter interface {
+	Usage() []Usage
+}
+
+// GastoTotal returns the spend of all the given usage.
+func GastoTotal(usage []Usage) Valor {
+	gasto := Valor(0)
+	for _, u := range usage {
+		gasto += u.Gasto
+	}
+	return gasto
+}
+
+type usageKey struct {
+	tenant  string
+	label   string
+	servico Servico
+}
+
+type usageMeter struct {
+	mu        sync.Mutex
+	usage     map[usageKey]*Usage
+	precos    TabelaPrecos
+	orcamento Valor
+	gasto     Valor
+	reservado Valor
+}
+
+func newUsageMeter() *usageMeter {
+	return &usageMeter{usage: make(map[usageKey]*Usage)}
+}
+
+// reservar reserves the cost of a call to the given service, failing with an
+// OrcamentoExcedidoError if it does not fit the budget along with the spend
+// and the calls in flight. The reserved cost must be given back to registrar.
+func (m *usageMeter) reservar(servico Servico) (Valor, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	custo := m.precos[servico]
+	if m.orcamento > 0 && m.gasto+m.reservado+custo > m.orcamento {
+		return 0, &OrcamentoExcedidoError{Servico: servico, Custo: custo, Gasto: m.gasto, Orcamento: m.orcamento}
+	}
+	m.reservado += custo
+	return custo, nil
+}
+
+// registrar accounts a call to the given service by the tenant and the label
+// in the given context, failed if err is not nil. The calls that may have been
+// billed, as told by cobrada, are charged the reserved cost.
+func (m *usageMeter) registrar(ctx context.Context, servico Servico, custo Valor, err error) {
+	tenant, _ := TenantFromContext(ctx)
+	label, _ := LabelFromContext(ctx)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := usageKey{tenant: tenant, label: label, servico: servico}
+	u, ok := m.usage[key]
+	if !ok {
+		u = &Usage{Tenant: tenant, Label: label, Servico: servico}
+		m.usage[key] = u
+	}
+	m.reservado -= custo
+	u.Consultas++
+	if err != nil {
+		u.Falhas++
+	}
+	if !cobrada(err) {
+		return
+	}
+	m.gasto += custo
+	u.Gasto += custo
+}
+
+// cobrada reports whether a call that returned err may have been billed. A
+// call is billed once it reaches the SOA WebServices, even if it times out or
+// is cancelled while waiting for the response, so only the calls that could
+// not connect and the ones that failed with a CodigoStatus known to be free
+// are not.
+func cobrada(err error) bool {
+	var statusErr *StatusError
+	var opErr *net.OpError
+	switch {
+	case err == nil:
+		return true
+	case errors.As(err, &statusErr):
+		info, _ := ConsultarStatus(statusErr.Codigo)
+		return !info.Gratuito
+	case errors.As(err, &opErr) && opErr.Op == "dial":
+		return false
+	}
+	return true
+}
+
+// snapshot returns the usage sorted by tenant, label and service.
+func (m *usageMeter) snapshot() []Usage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	usage := make([]Usage, 0, len(m.usage))
+	for _, u := range m.usage {
+		usage = append(usage, *u)
+	}
+	sort.Slice(usage, func(i, j int) bool {
+		if usage[i].Tenant != usage[j].Tenant {
+			return usage[i].Tenant < usage[j].Tenant
+		}
+		if usage[i].Label != usage[j].Label {
+			return usage[i].Label < usage[j].Label
+		}
+		return usage[i].Servico < usage[j].Servico
+	})
+	return usage
+}
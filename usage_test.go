@@ -0,0 +1,167 @@
+package soawebservices_test
+
+import (
+	"context"
+	"errors"
+	"github.com/diegohordi/soawebservices"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// errTransport fails every request with its error.
+type errTransport struct {
+	err error
+}
+
+func (e errTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, e.err
+}
+
+func TestClient_gasto(t *testing.T) {
+	credenciais := soawebservices.Credenciais{Email: "test@test.com", Senha: "test"}
+	tabela := soawebservices.TabelaPrecos{soawebservices.ServicoCNPJ: soawebservices.Reais(0, 40)}
+	tests := []struct {
+		name      string
+		ambiente  soawebservices.Ambiente
+		file      string
+		wantGasto soawebservices.Valor
+		wantErr   error
+	}{
+		{
+			name:      "should charge the successful calls until the budget is reached",
+			ambiente:  soawebservices.Producao,
+			file:      "consultacnpj_success.json",
+			wantGasto: soawebservices.Reais(0, 80),
+			wantErr:   soawebservices.ErrOrcamentoExcedido,
+		},
+		{
+			name:     "should not charge the calls failed with wrong credentials",
+			ambiente: soawebservices.Producao,
+			file:     "consultacnpj_wrong_credentials.json",
+			wantErr:  soawebservices.ErrCredenciaisInvalidas,
+		},
+		{
+			name:     "should not charge the calls made in test drive",
+			ambiente: soawebservices.TestDrive,
+			file:     "consultacnpj_success.json",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			client, err := soawebservices.NewClient(fixtureClient(t, tt.file), "https://soawebservices.com.br", tt.ambiente, credenciais,
+				soawebservices.WithTabelaPrecos(soawebservices.Producao, tabela),
+				soawebservices.WithTabelaPrecos(soawebservices.TestDrive, tabela),
+				soawebservices.WithOrcamento(soawebservices.Reais(1, 0)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx := soawebservices.WithLabel(context.TODO(), "cadastro")
+			for i := 0; i < 3; i++ {
+				_, err = client.ConsultarCNPJ(ctx, "99999999999962")
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			usage := client.(soawebservices.UsageReporter).Usage()
+			if got := soawebservices.GastoTotal(usage); got != tt.wantGasto {
+				t.Errorf("GastoTotal() got = %s, want %s", got, tt.wantGasto)
+			}
+			if len(usage) != 1 || usage[0].Label != "cadastro" {
+				t.Errorf("unexpected usage %+v", usage)
+			}
+		})
+	}
+}
+
+func TestClient_gasto_falhas(t *testing.T) {
+	credenciais := soawebservices.Credenciais{Email: "test@test.com", Senha: "test"}
+	tabela := soawebservices.TabelaPrecos{soawebservices.ServicoCNPJ: soawebservices.Reais(0, 40)}
+	tests := []struct {
+		name       string
+		httpClient *http.Client
+		timeout    time.Duration
+		wantGasto  soawebservices.Valor
+	}{
+		{
+			name: "should charge the calls that timed out after reaching the provider",
+			httpClient: &http.Client{
+				Transport: RoundTripFunc(func(req *http.Request) *http.Response {
+					<-req.Context().Done()
+					return httptest.NewRecorder().Result()
+				}),
+			},
+			timeout:   time.Millisecond,
+			wantGasto: soawebservices.Reais(0, 40),
+		},
+		{
+			name:       "should charge the calls failed with a CodigoStatus not known to be free",
+			httpClient: fixtureClient(t, "consultacnpj_invalid_cnpj.json"),
+			wantGasto:  soawebservices.Reais(0, 40),
+		},
+		{
+			name: "should not charge the calls that could not connect",
+			httpClient: &http.Client{
+				Transport: errTransport{err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			client, err := soawebservices.NewClient(tt.httpClient, "https://soawebservices.com.br", soawebservices.Producao, credenciais,
+				soawebservices.WithTabelaPrecos(soawebservices.Producao, tabela))
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx := context.TODO()
+			if tt.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			}
+			if _, err = client.ConsultarCNPJ(ctx, "99999999999962"); err == nil {
+				t.Fatal("expected the call to fail")
+			}
+			usage := client.(soawebservices.UsageReporter).Usage()
+			if got := soawebservices.GastoTotal(usage); got != tt.wantGasto {
+				t.Errorf("GastoTotal() got = %s, want %s", got, tt.wantGasto)
+			}
+			if len(usage) != 1 || usage[0].Falhas != 1 {
+				t.Errorf("unexpected usage %+v", usage)
+			}
+		})
+	}
+}
+
+func TestOrcamentoExcedidoError(t *testing.T) {
+	credenciais := soawebservices.Credenciais{Email: "test@test.com", Senha: "test"}
+	client, err := soawebservices.NewClient(fixtureClient(t, "consultacnpj_success.json"), "https://soawebservices.com.br", soawebservices.Producao, credenciais,
+		soawebservices.WithTabelaPrecos(soawebservices.Producao, soawebservices.TabelaPrecos{soawebservices.ServicoCNPJ: soawebservices.Reais(2, 0)}),
+		soawebservices.WithOrcamento(soawebservices.Reais(1, 0)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.ConsultarCNPJ(context.TODO(), "99999999999962")
+	var orcamentoErr *soawebservices.OrcamentoExcedidoError
+	if !errors.As(err, &orcamentoErr) {
+		t.Fatalf("expected an OrcamentoExcedidoError, got %v", err)
+	}
+	want := soawebservices.OrcamentoExcedidoError{
+		Servico:   soawebservices.ServicoCNPJ,
+		Custo:     soawebservices.Reais(2, 0),
+		Orcamento: soawebservices.Reais(1, 0),
+	}
+	if !reflect.DeepEqual(*orcamentoErr, want) {
+		t.Errorf("got %+v, want %+v", *orcamentoErr, want)
+	}
+	if usage := client.(soawebservices.UsageReporter).Usage(); len(usage) != 0 {
+		t.Errorf("expected the rejected call not to be accounted, got %+v", usage)
+	}
+}
@@ -0,0 +1,62 @@
+package soawebservices
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Valor is an amount of money in centavos.
+type Valor int64
+
+// Reais returns a Valor of the given reais and centavos.
+func Reais(reais int64, centavos int64) Valor {
+	return Valor(reais*100 + centavos)
+}
+
+// String returns the Valor in the Brazilian format, as in R$ 1.234,56.
+func (v Valor) String() string {
+	sinal := ""
+	if v < 0 {
+		sinal, v = "-", -v
+	}
+	reais := strconv.FormatInt(int64(v)/100, 10)
+	for i := len(reais) - 3; i > 0; i -= 3 {
+		reais = reais[:i] + "." + reais[i:]
+	}
+	return fmt.Sprintf("%sR$ %s,%02d", sinal, reais, int64(v)%100)
+}
+
+// ParseValor parses an amount in the Brazilian format, as in 1.234,56 or
+// R$ 1.234,56. Amounts without a decimal comma, as in 1234.56, are also
+// accepted.
+func ParseValor(value string) (Valor, error) {
+	s := strings.TrimSpace(value)
+	negativo := strings.HasPrefix(s, "-")
+	s = strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(s, "-"), "R$"))
+	if strings.Contains(s, ",") {
+		s = strings.ReplaceAll(s, ".", "")
+		s = strings.Replace(s, ",", ".", 1)
+	} else if i := strings.LastIndex(s, "."); i >= 0 && len(s)-i-1 == 3 {
+		s = strings.ReplaceAll(s, ".", "")
+	}
+	reais, centavos := s, ""
+	if i := strings.Index(s, "."); i >= 0 {
+		reais, centavos = s[:i], s[i+1:]
+	}
+	if len(centavos) > 2 || reais == "" && centavos == "" {
+		return 0, fmt.Errorf("invalid amount %q", value)
+	}
+	centavos += strings.Repeat("0", 2-len(centavos))
+	if reais == "" {
+		reais = "0"
+	}
+	n, err := strconv.ParseInt(reais+centavos, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q", value)
+	}
+	if negativo {
+		n = -n
+	}
+	return Valor(n), nil
+}
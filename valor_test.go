@@ -0,0 +1,58 @@
+package soawebservices_test
+
+import (
+	"github.com/diegohordi/soawebservices"
+	"testing"
+)
+
+func TestParseValor(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    soawebservices.Valor
+		wantErr bool
+	}{
+		{value: "1.234,56", want: 123456},
+		{value: "R$ 1.234,5", want: 123450},
+		{value: "0,10", want: 10},
+		{value: ",5", want: 50},
+		{value: "-10,00", want: -1000},
+		{value: "1234.56", want: 123456},
+		{value: "1.234", want: 123400},
+		{value: "1.234.567", want: 123456700},
+		{value: "12", want: 1200},
+		{value: "", wantErr: true},
+		{value: "1,234", wantErr: true},
+		{value: "abc", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.value, func(t *testing.T) {
+			t.Parallel()
+			got, err := soawebservices.ParseValor(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseValor() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseValor() got = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValor_String(t *testing.T) {
+	tests := []struct {
+		valor soawebservices.Valor
+		want  string
+	}{
+		{valor: 0, want: "R$ 0,00"},
+		{valor: 5, want: "R$ 0,05"},
+		{valor: 123456, want: "R$ 1.234,56"},
+		{valor: 123456789, want: "R$ 1.234.567,89"},
+		{valor: -1050, want: "-R$ 10,50"},
+	}
+	for _, tt := range tests {
+		if got := tt.valor.String(); got != tt.want {
+			t.Errorf("String() got = %s, want %s", got, tt.want)
+		}
+	}
+}
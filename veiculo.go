@@ -0,0 +1,153 @@
+package soawebservices
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"unicode"
+)
+
+const (
+	urlVeiculo = "veiculos/veiculo.ashx"
+)
+
+const (
+	ErrPlacaInvalida = Error("a placa informada é inválida (P021M001)")
+)
+
+// NormalizarPlaca returns the given placa in upper case and without
+// separators, as in ABC1234 or ABC1D23, failing with ErrPlacaInvalida if it
+// is neither in the old nor in the Mercosul format.
+func NormalizarPlaca(placa string) (string, error) {
+	p := strings.Map(func(r rune) rune {
+		if r == '-' || r == '.' || unicode.IsSpace(r) {
+			return -1
+		}
+		return unicode.ToUpper(r)
+	}, placa)
+	if len(p) != 7 {
+		return "", ErrPlacaInvalida
+	}
+	for i, r := range p {
+		var ok bool
+		switch i {
+		case 0, 1, 2:
+			ok = r >= 'A' && r <= 'Z'
+		case 4:
+			ok = r >= '0' && r <= '9' || r >= 'A' && r <= 'J'
+		default:
+			ok = r >= '0' && r <= '9'
+		}
+		if !ok {
+			return "", ErrPlacaInvalida
+		}
+	}
+	return p, nil
+}
+
+// PlacaMercosul reports whether the given normalized placa is in the Mercosul
+// format, as in ABC1D23.
+func PlacaMercosul(placa string) bool {
+	return len(placa) == 7 && placa[4] >= 'A' && placa[4] <= 'J'
+}
+
+func (d *defaultClient) buildConsultaVeiculoRequestBody(credenciais Credenciais, placa string) (io.Reader, error) {
+	consulta := newConsultaVeiculo(credenciais, placa)
+	buf, err := json.Marshal(consulta)
+	if err != nil {
+		return nil, fmt.Errorf("an error occurred while build the request body: %w", err)
+	}
+	return bytes.NewBuffer(buf), err
+}
+
+func (d *defaultClient) parseConsultaVeiculoResponseBody(resp *http.Response) (Veiculo, error) {
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+	result := veiculoResult{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return Veiculo{}, err
+	}
+	if result.Transacao.CodigoStatus == StatusVeiculoNaoEncontrado {
+		return Veiculo{}, nil
+	}
+	if err := parseTransacao(result.Status, result.Transacao); err != nil {
+		return Veiculo{}, err
+	}
+	campos := leitorDatas{estrito: d.datasEstritas}
+	veiculo := Veiculo{
+		Placa:         result.Placa,
+		Renavam:       result.Renavam,
+		Chassi:        result.Chassi,
+		Marca:         result.Marca,
+		Modelo:        result.Modelo,
+		AnoFabricacao: campos.lerInteiro("AnoFabricacao", result.AnoFabricacao),
+		AnoModelo:     campos.lerInteiro("AnoModelo", result.AnoModelo),
+		Cor:           result.Cor,
+		Combustivel:   result.Combustivel,
+		Municipio:     result.Municipio,
+		UF:            result.UF,
+		Restricoes:    result.Restricoes,
+		Avisos:        campos.avisos,
+	}
+	if campos.err != nil {
+		return Veiculo{}, campos.err
+	}
+	return veiculo, nil
+}
+
+// ConsultarVeiculo returns the vehicle of the given placa, in the old or in the
+// Mercosul format, formatted or not. Vehicles not found are not an error, but
+// an empty Veiculo.
+func (d *defaultClient) ConsultarVeiculo(ctx context.Context, placa string) (Veiculo, error) {
+	placa, err := NormalizarPlaca(placa)
+	if err != nil {
+		return Veiculo{}, err
+	}
+	credenciais, err := d.credenciais(ctx)
+	if err != nil {
+		return Veiculo{}, err
+	}
+	custo, err := d.usage.reservar(ServicoVeiculo)
+	if err != nil {
+		return Veiculo{}, err
+	}
+	result, err := d.consultarVeiculo(ctx, credenciais, placa)
+	d.usage.registrar(ctx, ServicoVeiculo, custo, err)
+	return result, err
+}
+
+func (d *defaultClient) consultarVeiculo(ctx context.Context, credenciais Credenciais, placa string) (Veiculo, error) {
+	errChan := make(chan error, 1)
+	resultChan := make(chan Veiculo, 1)
+	requestBody, err := d.buildConsultaVeiculoRequestBody(credenciais, placa)
+	if err != nil {
+		return Veiculo{}, err
+	}
+	go func() {
+		serviceURL := fmt.Sprintf("%s/restservices/%s/%s", d.baseURL, d.ambiente, urlVeiculo)
+		resp, err := d.httpClient.Post(serviceURL, "application/json", requestBody)
+		if err != nil {
+			errChan <- err
+			return
+		}
+		result, err := d.parseConsultaVeiculoResponseBody(resp)
+		if err != nil {
+			errChan <- err
+			return
+		}
+		resultChan <- result
+	}()
+	select {
+	case err = <-errChan:
+		return Veiculo{}, err
+	case <-ctx.Done():
+		return Veiculo{}, ctx.Err()
+	case result := <-resultChan:
+		return result, nil
+	}
+}
@@ -0,0 +1,103 @@
+package soawebservices_test
+
+import (
+	"context"
+	"errors"
+	"github.com/diegohordi/soawebservices"
+	"reflect"
+	"testing"
+)
+
+func TestNormalizarPlaca(t *testing.T) {
+	tests := []struct {
+		placa        string
+		want         string
+		wantMercosul bool
+		wantErr      error
+	}{
+		{placa: "abc-1234", want: "ABC1234"},
+		{placa: "ABC 1D23", want: "ABC1D23", wantMercosul: true},
+		{placa: "abc1j99", want: "ABC1J99", wantMercosul: true},
+		{placa: "ABC1K23", wantErr: soawebservices.ErrPlacaInvalida},
+		{placa: "AB12345", wantErr: soawebservices.ErrPlacaInvalida},
+		{placa: "ABC123", wantErr: soawebservices.ErrPlacaInvalida},
+		{placa: "", wantErr: soawebservices.ErrPlacaInvalida},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.placa, func(t *testing.T) {
+			t.Parallel()
+			got, err := soawebservices.NormalizarPlaca(tt.placa)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if got != tt.want {
+				t.Errorf("NormalizarPlaca() got = %s, want %s", got, tt.want)
+			}
+			if soawebservices.PlacaMercosul(got) != tt.wantMercosul {
+				t.Errorf("PlacaMercosul(%s) got = %v, want %v", got, !tt.wantMercosul, tt.wantMercosul)
+			}
+		})
+	}
+}
+
+func Test_defaultClient_ConsultarVeiculo(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		placa   string
+		want    soawebservices.Veiculo
+		wantErr error
+	}{
+		{
+			name:  "should return a vehicle",
+			file:  "consultaveiculo_success.json",
+			placa: "abc-1d23",
+			want: soawebservices.Veiculo{
+				Placa:         "ABC1D23",
+				Renavam:       "01234567890",
+				Chassi:        "9BWZZZ377VT004251",
+				Marca:         "VOLKSWAGEN",
+				Modelo:        "GOL 1.0",
+				AnoFabricacao: 2019,
+				AnoModelo:     2020,
+				Cor:           "PRATA",
+				Combustivel:   "ALCOOL/GASOLINA",
+				Municipio:     "SAO PAULO",
+				UF:            "SP",
+				Restricoes:    []string{"ALIENACAO FIDUCIARIA"},
+			},
+		},
+		{
+			name:  "should return an empty vehicle",
+			file:  "consultaveiculo_not_found.json",
+			placa: "ABC1234",
+		},
+		{
+			name:    "should fail due to the given invalid placa",
+			file:    "consultaveiculo_success.json",
+			placa:   "ABC12345",
+			wantErr: soawebservices.ErrPlacaInvalida,
+		},
+		{
+			name:    "should fail due to wrong credentials",
+			file:    "consultaveiculo_wrong_credentials.json",
+			placa:   "ABC1234",
+			wantErr: soawebservices.ErrCredenciaisInvalidas,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			client := MustCreateClient(fixtureClient(t, tt.file))
+			result, err := client.ConsultarVeiculo(context.TODO(), tt.placa)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if !reflect.DeepEqual(result, tt.want) {
+				t.Error("want ", tt.want, " but got ", result)
+			}
+		})
+	}
+}